@@ -6,9 +6,55 @@ import (
 	"digital-innovation/stratego/game"
 	"digital-innovation/stratego/models"
 	"fmt"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 )
 
-func runAIvsAI(ai1, ai2 string, matches int, logging bool) models.GameSummary {
+// Options configures an AI vs AI run.
+// Game i of a run is played with seed Seed+i, so running a single game with that seed replays it exactly.
+// The searching AIs play with a fixed amount of search per move instead of a time budget for that.
+type Options struct {
+	Matches int
+	Seed    uint64 // seed of the first game, 0 picks a random one
+	Workers int    // games played at the same time, 0 uses all CPU cores
+	Format  string // one of Formats, empty for FormatNone
+	Out     string // file to write the results to, empty for stdout
+	Logging bool
+	Rules   models.RuleSet // rule set of all games, the zero value plays classic games
+}
+
+// withDefaults fills in a random seed, the number of workers and the text summary.
+func (o Options) withDefaults() Options {
+	if o.Seed == 0 {
+		o.Seed = rand.Uint64()
+	}
+	if o.Workers <= 0 {
+		o.Workers = runtime.NumCPU()
+	}
+	if o.Format == "" {
+		o.Format = FormatNone
+	}
+	return o
+}
+
+// ruleSet returns the rule set of the games, the classic rules if none is set.
+func (o Options) ruleSet() models.RuleSet {
+	if o.Rules.Army == nil {
+		return models.ClassicRules()
+	}
+	return o.Rules
+}
+
+// runAIvsAI plays the games and sums up the results. If games is not nil, it gets the record of every game.
+func runAIvsAI(ai1, ai2 string, options Options, games gameWriter) (models.GameSummary, error) {
+	if err := validateAgents([]string{ai1, ai2}); err != nil {
+		return models.GameSummary{}, err
+	}
+
 	draws := 0
 
 	flagCaptures := 0
@@ -23,75 +69,57 @@ func runAIvsAI(ai1, ai2 string, matches int, logging bool) models.GameSummary {
 	player1Data := models.AiTournamentData{Name: player1Name}
 	player2Data := models.AiTournamentData{Name: player2Name}
 
-	for i := range matches {
-		// Create FRESH players and controllers for EACH game, use same ID & name
-		playerAlice := engine.NewPlayer(0, player1Name, "red")
-		playerBob := engine.NewPlayer(1, player2Name, "blue")
-
-		controllerAlice := AIhandler.CreateAI(ai1, &playerAlice)
-		controllerBob := AIhandler.CreateAI(ai2, &playerBob)
-
-		// Alternate who goes first
-		// Without this, player 1 wins more often than the other
-		var g *game.Game
-		if i%2 == 0 {
-			g = game.QuickStart(controllerAlice, controllerBob)
-			if logging {
-				fmt.Printf("Game %3d (Alice starts): ", i+1)
-			}
-		} else {
-			g = game.QuickStart(controllerBob, controllerAlice)
-			if logging {
-				fmt.Printf("Game %3d (Bob starts):   ", i+1)
-			}
+	matches := options.Matches
+	rules := options.ruleSet()
+	results := make([]matchResult, matches)
+	var writeErr error
+	forEachGame(matches, options.Workers, func(i int) {
+		// The runner only logs turns when games are not interleaved
+		results[i] = playMatch(ai1, ai2, player1Name, player2Name, rules, options.Seed+uint64(i), options.Logging && options.Workers == 1)
+	}, func(i int) {
+		if options.Logging {
+			printGameResult(i, results[i], player1Name, player2Name)
+		}
+		if games != nil && writeErr == nil {
+			writeErr = games.writeGame(results[i].record(i, player1Name, player2Name))
 		}
+		results[i].initial, results[i].history = nil, nil // only needed for the record, don't keep thousands of games in memory
+	})
+	if writeErr != nil {
+		return models.GameSummary{}, writeErr
+	}
 
-		runner := game.NewGameRunner(g, 0, 1000)
-		winner := runner.RunToCompletion(logging)
-		rounds := g.GetRound()
+	for _, result := range results {
+		rounds := result.rounds
 		totalRounds += rounds
 
 		if rounds < leastRounds {
 			leastRounds = rounds
 		}
 
-		if winner != nil {
-			var winnerData *models.AiTournamentData
-			winCause := g.GetWinCause()
-			if winner.GetName() == player1Name {
-				winnerData = &player1Data
-				if logging {
-					fmt.Printf("%v wins - %s (%d rounds)\n", player1Name, winCause, rounds)
-				}
-
-			} else {
-				winnerData = &player2Data
-				if logging {
-					fmt.Printf("%v wins - %s (%d rounds)\n", player2Name, winCause, rounds)
-				}
-			}
-
-			switch winCause {
-			case game.WinCauseFlagCaptured:
-				winnerData.WinCauseFlagCaptured++
-				flagCaptures++
-			case game.WinCauseNoMovablePieces:
-				winnerData.WinCauseNoMovesWin++
-				noMovesWins++
-			default:
-				winnerData.WinCauseMaxTurns++
-				maxTurnsWins++
-			}
+		if result.winner < 0 {
+			draws++
+			continue
+		}
 
-			winnerData.Wins++
+		winnerData := &player1Data
+		if result.winner == 1 {
+			winnerData = &player2Data
+		}
 
-		} else {
-			if logging {
-				fmt.Printf("Draw after %d rounds\n", rounds)
-			}
-			draws++
+		switch result.cause {
+		case game.WinCauseFlagCaptured:
+			winnerData.WinCauseFlagCaptured++
+			flagCaptures++
+		case game.WinCauseNoMovablePieces:
+			winnerData.WinCauseNoMovesWin++
+			noMovesWins++
+		default:
+			winnerData.WinCauseMaxTurns++
+			maxTurnsWins++
 		}
 
+		winnerData.Wins++
 	}
 
 	avgRounds := float64(totalRounds) / float64(matches)
@@ -104,10 +132,158 @@ func runAIvsAI(ai1, ai2 string, matches int, logging bool) models.GameSummary {
 		AverageRounds:        avgRounds,
 		LeastRounds:          leastRounds,
 		Matches:              matches,
+		Seed:                 options.Seed,
 		WinCauseFlagCaptured: flagCaptures,
 		WinCauseNoMovesWins:  noMovesWins,
 		WinCauseMaxTurns:     maxTurnsWins,
 	}
 
-	return gameSummary
+	return gameSummary, nil
+}
+
+// validateAgents checks that every agent is a built-in AI, before any game is played.
+func validateAgents(agents []string) error {
+	for _, agent := range agents {
+		if !slices.Contains(models.AINames, agent) {
+			return fmt.Errorf("unknown AI %q, expected one of: %s", agent, strings.Join(models.AINames, ", "))
+		}
+	}
+	return nil
+}
+
+// printGameResult logs the result of a game to stderr, stdout may have the results of the run.
+func printGameResult(i int, result matchResult, player1Name, player2Name string) {
+	starter := "Alice starts"
+	if !result.firstStarts {
+		starter = "Bob starts"
+	}
+	fmt.Fprintf(os.Stderr, "Game %3d (seed %d, %s): ", i+1, result.seed, starter)
+
+	switch result.winner {
+	case 0:
+		fmt.Fprintf(os.Stderr, "%v wins - %s (%d rounds)\n", player1Name, result.cause, result.rounds)
+	case 1:
+		fmt.Fprintf(os.Stderr, "%v wins - %s (%d rounds)\n", player2Name, result.cause, result.rounds)
+	default:
+		fmt.Fprintf(os.Stderr, "Draw after %d rounds\n", result.rounds)
+	}
+}
+
+// forEachGame calls play for the games 0 to games-1 on a pool of workers.
+// done is called for every finished game, one at a time.
+func forEachGame(games, workers int, play func(i int), done func(i int)) {
+	jobs := make(chan int)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				play(i)
+				mutex.Lock()
+				done(i)
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for i := range games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// matchResult is the outcome of a single game between two AIs.
+type matchResult struct {
+	seed        uint64
+	rules       models.RuleSet
+	firstStarts bool
+	winner      int // 0 for the first AI, 1 for the second, -1 for a draw
+	cause       game.WinCause
+	rounds      int
+	scores      [2]int // final piece scores of the first and second AI
+	initial     [][]models.PieceData
+	history     []models.HistoricalMove
+}
+
+// record returns the record of game i of a run.
+func (r matchResult) record(i int, name1, name2 string) models.AiGameRecord {
+	record := models.AiGameRecord{
+		Game:         i + 1,
+		Seed:         r.seed,
+		Rules:        r.rules,
+		Player1:      name1,
+		Player2:      name2,
+		FirstPlayer:  name1,
+		WinCause:     string(r.cause),
+		Rounds:       r.rounds,
+		Player1Score: r.scores[0],
+		Player2Score: r.scores[1],
+		InitialState: r.initial,
+		History:      r.history,
+	}
+	if !r.firstStarts {
+		record.FirstPlayer = name2
+	}
+	switch r.winner {
+	case 0:
+		record.Winner = name1
+	case 1:
+		record.Winner = name2
+	}
+	if r.winner >= 0 {
+		winnerID := r.winner
+		record.WinnerID = &winnerID
+	}
+	return record
+}
+
+// playMatch plays one game between fresh instances of the two AIs.
+// The seed decides the setups, the AIs' random choices and who starts, alternating between even and odd seeds.
+func playMatch(ai1, ai2, name1, name2 string, rules models.RuleSet, seed uint64, logging bool) matchResult {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	firstStarts := seed%2 == 0
+
+	// Create FRESH players and controllers for EACH game, use same ID & name
+	player1 := engine.NewPlayer(0, name1, "red")
+	player2 := engine.NewPlayer(1, name2, "blue")
+
+	controller1 := AIhandler.CreateReplayableAI(ai1, &player1)
+	controller2 := AIhandler.CreateReplayableAI(ai2, &player2)
+	controller1.SetSeed(rng.Uint64())
+	controller2.SetSeed(rng.Uint64())
+
+	// Alternate who goes first
+	// Without this, player 1 wins more often than the other
+	var g *game.Game
+	if firstStarts {
+		g = game.QuickStartWithRules(controller1, controller2, rules, rng)
+	} else {
+		g = game.QuickStartWithRules(controller2, controller1, rules, rng)
+	}
+
+	initial := g.GetInitialBoardState()
+	runner := game.NewGameRunner(g, 0, 1000)
+	winner := runner.RunToCompletion(logging)
+
+	result := matchResult{
+		seed:        seed,
+		rules:       rules,
+		firstStarts: firstStarts,
+		winner:      -1,
+		rounds:      g.GetRound(),
+		scores:      [2]int{player1.GetPieceScore(), player2.GetPieceScore()},
+		initial:     initial,
+		history:     g.HistoricalHistory,
+	}
+	if winner != nil {
+		result.cause = g.GetWinCause()
+		result.winner = 1
+		if winner == &player1 {
+			result.winner = 0
+		}
+	}
+	return result
 }
@@ -0,0 +1,118 @@
+package aivsai
+
+import (
+	"digital-innovation/stratego/models"
+	"reflect"
+	"testing"
+)
+
+func TestPlayMatchIsDeterministic(t *testing.T) {
+	for _, seed := range []uint64{1, 2, 12345} {
+		first := playMatch(models.Fato, models.Heuristic, "a", "b", models.ClassicRules(), seed, false)
+		second := playMatch(models.Fato, models.Heuristic, "a", "b", models.ClassicRules(), seed, false)
+		if first.winner != second.winner || first.cause != second.cause || first.rounds != second.rounds || first.scores != second.scores {
+			t.Errorf("Expected seed %d to replay the same game, got %+v and %+v", seed, first, second)
+		}
+	}
+}
+
+func TestPlayMatchReplaysSearchingAIs(t *testing.T) {
+	rules := models.BarrageRules().WithBoard(models.SmallBoard())
+	for _, seed := range []uint64{3, 4} {
+		first := playMatch(models.Minimax, models.Mcts, "a", "b", rules, seed, false)
+		second := playMatch(models.Minimax, models.Mcts, "a", "b", rules, seed, false)
+		if !reflect.DeepEqual(first.history, second.history) || first.winner != second.winner || first.cause != second.cause {
+			t.Errorf("Expected seed %d to replay the same game, got %d and %d moves", seed, len(first.history), len(second.history))
+		}
+	}
+}
+
+func TestRunAIvsAIMatchesSingleGames(t *testing.T) {
+	summary, err := runAIvsAI(models.Fafo, models.Fato, Options{Matches: 6, Seed: 100, Workers: 3}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if summary.Matches != 6 || summary.Player1data.Wins+summary.Player2data.Wins+summary.Draws != 6 {
+		t.Fatalf("Expected 6 games in the summary, got: %+v", summary)
+	}
+
+	// Game i of the run is the same as a single game with seed 100+i
+	rounds := 0
+	for i := range uint64(6) {
+		rounds += playMatch(models.Fafo, models.Fato, "Alice AI - fafo", "Bob AI - fato", models.ClassicRules(), 100+i, false).rounds
+	}
+	if rounds != summary.TotalRounds {
+		t.Errorf("Expected the run to replay game by game with %d rounds, got: %d", rounds, summary.TotalRounds)
+	}
+}
+
+func TestPlayMatchWithRules(t *testing.T) {
+	result := playMatch(models.Fafo, models.Heuristic, "a", "b", models.BarrageRules(), 5, false)
+	if result.rounds == 0 || result.rules.Name != models.RuleSetBarrage {
+		t.Fatalf("Expected a Barrage game to be played, got: %+v", result)
+	}
+
+	pieces := 0
+	for _, row := range result.initial {
+		for _, data := range row {
+			if data.OwnerID >= 0 {
+				pieces++
+			}
+		}
+	}
+	if pieces != 16 {
+		t.Errorf("Expected a Barrage game to start with 16 pieces, got %d", pieces)
+	}
+}
+
+func TestPlayMatchOnSmallBoard(t *testing.T) {
+	rules := models.BarrageRules().WithBoard(models.SmallBoard())
+	for _, ai := range []string{models.Fato, models.Heuristic, models.Minimax, models.Mcts} {
+		result := playMatch(ai, models.Fafo, "a", "b", rules, 7, false)
+		if result.rounds == 0 || len(result.initial) != 8 || len(result.initial[0]) != 8 {
+			t.Errorf("Expected %s to play a game on the 8x8 board, got: %+v", ai, result)
+		}
+	}
+}
+
+func TestPlayMatchSmallDuel(t *testing.T) {
+	rules := models.SmallDuelRules()
+	if err := rules.Validate(); err != nil {
+		t.Fatalf("Expected Duel on the small board to be valid, got: %v", err)
+	}
+	if err := models.DuelRules().WithBoard(models.SmallBoard()).Validate(); err == nil {
+		t.Error("Expected the army of Duel not to fit 2 rows of the small board")
+	}
+
+	result := playMatch(models.Heuristic, models.Fato, "a", "b", rules, 9, false)
+	if result.rounds == 0 || result.rules.Name != models.RuleSetDuelSmall || len(result.initial) != 8 {
+		t.Fatalf("Expected a Duel game to be played on the 8x8 board, got: %+v", result)
+	}
+	pieces := 0
+	for _, row := range result.initial {
+		for _, data := range row {
+			if data.OwnerID >= 0 {
+				pieces++
+			}
+		}
+	}
+	if pieces != 32 {
+		t.Errorf("Expected a Duel game on the small board to start with 32 pieces, got %d", pieces)
+	}
+}
+
+func TestPlayMatchWinnerOfSameAI(t *testing.T) {
+	// Both AIs have the same name, the winner is told apart by its player
+	secondWins := false
+	for seed := range uint64(6) {
+		named := playMatch(models.Fato, models.Fato, "a", "b", models.ClassicRules(), seed, false)
+		same := playMatch(models.Fato, models.Fato, "fato", "fato", models.ClassicRules(), seed, false)
+		if same.winner != named.winner {
+			t.Errorf("Expected seed %d to give winner %d with equal names, got: %d", seed, named.winner, same.winner)
+		}
+		secondWins = secondWins || named.winner == 1
+	}
+	if !secondWins {
+		t.Errorf("Expected the second AI to win a game")
+	}
+}
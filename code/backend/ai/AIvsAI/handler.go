@@ -3,25 +3,89 @@ package aivsai
 import (
 	"digital-innovation/stratego/models"
 	"fmt"
+	"io"
+	"math"
+	"strings"
 )
 
-func RunAIvsAI(ai1, ai2 string, matches int, format string, logging bool) {
-	summary := runAIvsAI(ai1, ai2, matches, logging)
+// RunAIvsAI plays options.Matches games between the two AIs and writes the results in options.Format
+// to options.Out, or stdout.
+func RunAIvsAI(ai1, ai2 string, options Options) (err error) {
+	options = options.withDefaults()
+	if err := CheckFormat(options.Format, Formats); err != nil {
+		return err
+	}
+	out, err := openOutput(options.Out)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	games := newGameWriter(options.Format, out)
+	summary, err := runAIvsAI(ai1, ai2, options, games)
+	if err != nil {
+		return err
+	}
 
-	switch format {
-	case "md":
-		printMarkdownSummary(summary, matches)
+	matches := options.Matches
+	switch {
+	case games != nil:
+		err = games.flush()
+	case options.Format == FormatJSON:
+		err = writeJSON(out, summary)
+	case options.Format == FormatMarkdown:
+		printMarkdownSummary(out, summary, matches)
 	default:
-		printDefaultSummary(summary, matches)
+		printDefaultSummary(out, summary, matches)
+	}
+	return err
+}
+
+// RunTournament plays a round-robin tournament between all agents, options.Matches games per pairing,
+// and writes the results in options.Format to options.Out, or stdout.
+func RunTournament(agents []string, options Options) (err error) {
+	options = options.withDefaults()
+	if err := CheckFormat(options.Format, Formats); err != nil {
+		return err
+	}
+	out, err := openOutput(options.Out)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
 
+	games := newGameWriter(options.Format, out)
+	summary, err := runTournament(agents, options, games)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case games != nil:
+		err = games.flush()
+	case options.Format == FormatJSON:
+		err = writeJSON(out, summary)
+	case options.Format == FormatMarkdown:
+		printMarkdownTournament(out, summary)
+	default:
+		printDefaultTournament(out, summary)
+	}
+	return err
 }
 
-func printMarkdownSummary(summary models.GameSummary, matches int) {
+func printMarkdownSummary(w io.Writer, summary models.GameSummary, matches int) {
 	// Top-level summary
-	fmt.Printf("\n### AI vs AI Tournament Summary (%d games)\n\n", matches)
-	fmt.Printf("**Total Matches:** %d  \n**Total Rounds:** %d  \n**Average Rounds (per game):** %.2f  \n**Shortest Game (rounds):** %d\n\n",
-		matches, summary.TotalRounds, summary.AverageRounds, summary.LeastRounds)
+	fmt.Fprintf(w, "\n### AI vs AI Tournament Summary (%d games)\n\n", matches)
+	fmt.Fprintf(w, "**Total Matches:** %d  \n**Total Rounds:** %d  \n**Average Rounds (per game):** %.2f  \n**Shortest Game (rounds):** %d  \n**Seed:** %d\n\n",
+		matches, summary.TotalRounds, summary.AverageRounds, summary.LeastRounds, summary.Seed)
 
 	// Overall win causes (aggregate)
 	totalFlag := summary.WinCauseFlagCaptured
@@ -29,72 +93,152 @@ func printMarkdownSummary(summary models.GameSummary, matches int) {
 	totalMaxTurns := summary.WinCauseMaxTurns
 	wonMatches := float64(matches - summary.Draws)
 
-	fmt.Println("#### Overall Win Causes")
-	fmt.Println()
-	fmt.Println("| Cause | Count | % |")
-	fmt.Println("|-------:|------:|---:|")
-	fmt.Printf("| Flag captured | %d | %.1f%% |\n", totalFlag, float64(totalFlag)*100.0/wonMatches)
-	fmt.Printf("| No movable pieces | %d | %.1f%% |\n", totalNoMoves, float64(totalNoMoves)*100.0/wonMatches)
-	fmt.Printf("| Max turns | %d | %.1f%% |\n\n", totalMaxTurns, float64(totalMaxTurns)*100.0/wonMatches)
+	fmt.Fprintln(w, "#### Overall Win Causes")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Cause | Count | % |")
+	fmt.Fprintln(w, "|-------:|------:|---:|")
+	fmt.Fprintf(w, "| Flag captured | %d | %.1f%% |\n", totalFlag, float64(totalFlag)*100.0/wonMatches)
+	fmt.Fprintf(w, "| No movable pieces | %d | %.1f%% |\n", totalNoMoves, float64(totalNoMoves)*100.0/wonMatches)
+	fmt.Fprintf(w, "| Max turns | %d | %.1f%% |\n\n", totalMaxTurns, float64(totalMaxTurns)*100.0/wonMatches)
 
 	// Per-player summary table
 	p1 := summary.Player1data
 	p2 := summary.Player2data
 
-	fmt.Println("#### Player Results")
-	fmt.Println()
-	fmt.Println("| Player | Wins | Win % | Flag captures | No-move wins | Max-turn wins |")
-	fmt.Println("|:-------|-----:|-----:|--------------:|-------------:|--------------:|")
-	fmt.Printf("| %s | %d | %.1f%% | %d | %d | %d |\n",
+	fmt.Fprintln(w, "#### Player Results")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Player | Wins | Win % | Flag captures | No-move wins | Max-turn wins |")
+	fmt.Fprintln(w, "|:-------|-----:|-----:|--------------:|-------------:|--------------:|")
+	fmt.Fprintf(w, "| %s | %d | %.1f%% | %d | %d | %d |\n",
 		p1.Name, p1.Wins, float64(p1.Wins)*100.0/wonMatches, p1.WinCauseFlagCaptured, p1.WinCauseNoMovesWin, p1.WinCauseMaxTurns)
-	fmt.Printf("| %s | %d | %.1f%% | %d | %d | %d |\n\n",
+	fmt.Fprintf(w, "| %s | %d | %.1f%% | %d | %d | %d |\n\n",
 		p2.Name, p2.Wins, float64(p2.Wins)*100.0/wonMatches, p2.WinCauseFlagCaptured, p2.WinCauseNoMovesWin, p2.WinCauseMaxTurns)
 
 	// Draws
-	fmt.Printf("**Draws:** %d (%.1f%%)\n", summary.Draws, float64(summary.Draws)*100.0/wonMatches)
+	fmt.Fprintf(w, "**Draws:** %d (%.1f%%)\n", summary.Draws, float64(summary.Draws)*100.0/wonMatches)
 }
 
-func printDefaultSummary(summary models.GameSummary, matches int) {
+func printDefaultSummary(w io.Writer, summary models.GameSummary, matches int) {
 	// Human-readable plain text summary
-	fmt.Println()
-	fmt.Println("========================================")
-	fmt.Printf("AI vs AI Tournament Summary (%d games)\n", matches)
-	fmt.Println("========================================")
-	fmt.Printf("Total Matches: %d\n", matches)
-	fmt.Printf("Total Rounds: %d\n", summary.TotalRounds)
-	fmt.Printf("Average Rounds (per game): %.2f\n", summary.AverageRounds)
-	fmt.Printf("Shortest Game (rounds): %d\n", summary.LeastRounds)
-	fmt.Println("----------------------------------------")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "========================================")
+	fmt.Fprintf(w, "AI vs AI Tournament Summary (%d games)\n", matches)
+	fmt.Fprintln(w, "========================================")
+	fmt.Fprintf(w, "Total Matches: %d\n", matches)
+	fmt.Fprintf(w, "Total Rounds: %d\n", summary.TotalRounds)
+	fmt.Fprintf(w, "Average Rounds (per game): %.2f\n", summary.AverageRounds)
+	fmt.Fprintf(w, "Shortest Game (rounds): %d\n", summary.LeastRounds)
+	fmt.Fprintf(w, "Seed: %d (game n is played with seed %d+n-1)\n", summary.Seed, summary.Seed)
+	fmt.Fprintln(w, "----------------------------------------")
 
 	// Overall win causes
-	fmt.Println("Overall Win Causes:")
+	fmt.Fprintln(w, "Overall Win Causes:")
 	totalFlag := summary.WinCauseFlagCaptured
 	totalNoMoves := summary.WinCauseNoMovesWins
 	totalMaxTurns := summary.WinCauseMaxTurns
 	wonMatches := float64(matches - summary.Draws)
 
-	fmt.Printf("  Flag captured:     %d (%.1f%%)\n", totalFlag, float64(totalFlag)*100.0/wonMatches)
-	fmt.Printf("  No movable pieces: %d (%.1f%%)\n", totalNoMoves, float64(totalNoMoves)*100.0/wonMatches)
-	fmt.Printf("  Max turns:         %d (%.1f%%)\n", totalMaxTurns, float64(totalMaxTurns)*100.0/wonMatches)
-	fmt.Println("----------------------------------------")
+	fmt.Fprintf(w, "  Flag captured:     %d (%.1f%%)\n", totalFlag, float64(totalFlag)*100.0/wonMatches)
+	fmt.Fprintf(w, "  No movable pieces: %d (%.1f%%)\n", totalNoMoves, float64(totalNoMoves)*100.0/wonMatches)
+	fmt.Fprintf(w, "  Max turns:         %d (%.1f%%)\n", totalMaxTurns, float64(totalMaxTurns)*100.0/wonMatches)
+	fmt.Fprintln(w, "----------------------------------------")
 
 	// Per-player breakdown
 	p1 := summary.Player1data
 	p2 := summary.Player2data
-	fmt.Printf("Player: %s\n", p1.Name)
-	fmt.Printf("  Wins: %d (%.1f%%)\n", p1.Wins, float64(p1.Wins)*100.0/wonMatches)
-	fmt.Printf("  Win causes:\n")
-	fmt.Printf("    Flag captured:     %d\n", p1.WinCauseFlagCaptured)
-	fmt.Printf("    No movable pieces: %d\n", p1.WinCauseNoMovesWin)
-	fmt.Printf("    Max turns:         %d\n", p1.WinCauseMaxTurns)
-	fmt.Println("----------------------------------------")
-	fmt.Printf("Player: %s\n", p2.Name)
-	fmt.Printf("  Wins: %d (%.1f%%)\n", p2.Wins, float64(p2.Wins)*100.0/wonMatches)
-	fmt.Printf("  Win causes:\n")
-	fmt.Printf("    Flag captured:     %d\n", p2.WinCauseFlagCaptured)
-	fmt.Printf("    No movable pieces: %d\n", p2.WinCauseNoMovesWin)
-	fmt.Printf("    Max turns:         %d\n", p2.WinCauseMaxTurns)
-	fmt.Println("----------------------------------------")
-
-	fmt.Printf("Draws: %d (%.1f%%)\n", summary.Draws, float64(summary.Draws)*100.0/wonMatches)
+	fmt.Fprintf(w, "Player: %s\n", p1.Name)
+	fmt.Fprintf(w, "  Wins: %d (%.1f%%)\n", p1.Wins, float64(p1.Wins)*100.0/wonMatches)
+	fmt.Fprintf(w, "  Win causes:\n")
+	fmt.Fprintf(w, "    Flag captured:     %d\n", p1.WinCauseFlagCaptured)
+	fmt.Fprintf(w, "    No movable pieces: %d\n", p1.WinCauseNoMovesWin)
+	fmt.Fprintf(w, "    Max turns:         %d\n", p1.WinCauseMaxTurns)
+	fmt.Fprintln(w, "----------------------------------------")
+	fmt.Fprintf(w, "Player: %s\n", p2.Name)
+	fmt.Fprintf(w, "  Wins: %d (%.1f%%)\n", p2.Wins, float64(p2.Wins)*100.0/wonMatches)
+	fmt.Fprintf(w, "  Win causes:\n")
+	fmt.Fprintf(w, "    Flag captured:     %d\n", p2.WinCauseFlagCaptured)
+	fmt.Fprintf(w, "    No movable pieces: %d\n", p2.WinCauseNoMovesWin)
+	fmt.Fprintf(w, "    Max turns:         %d\n", p2.WinCauseMaxTurns)
+	fmt.Fprintln(w, "----------------------------------------")
+
+	fmt.Fprintf(w, "Draws: %d (%.1f%%)\n", summary.Draws, float64(summary.Draws)*100.0/wonMatches)
+}
+
+func printMarkdownTournament(w io.Writer, summary models.TournamentSummary) {
+	fmt.Fprintf(w, "\n### AI Round-Robin Tournament (%d agents, %d games per pairing)\n\n", len(summary.Agents), summary.MatchesPerPairing)
+	fmt.Fprintf(w, "**Seed:** %d\n\n", summary.Seed)
+
+	fmt.Fprintln(w, "#### Standings")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| # | Agent | Games | Wins | Losses | Draws | Score | Elo | 95% CI |")
+	fmt.Fprintln(w, "|--:|:------|------:|-----:|-------:|------:|------:|----:|:-------|")
+	for i, s := range summary.Standings {
+		fmt.Fprintf(w, "| %d | %s | %d | %d | %d | %d | %.1f%% | %.0f | %.0f to %.0f |\n",
+			i+1, s.Name, s.Games, s.Wins, s.Losses, s.Draws, s.Score*100, s.Elo, s.EloLow, s.EloHigh)
+	}
+	fmt.Fprintln(w)
+
+	// Cross-table: score of the row agent against the column agent
+	fmt.Fprintln(w, "#### Cross-table (score of row vs column)")
+	fmt.Fprintln(w)
+	header := "| Agent |"
+	separator := "|:------|"
+	for _, column := range summary.Standings {
+		header += " " + column.Name + " |"
+		separator += "-----:|"
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, separator)
+	for _, row := range summary.Standings {
+		line := "| " + row.Name + " |"
+		for _, column := range summary.Standings {
+			line += " " + formatScore(summary.Pairings, row.Name, column.Name) + " |"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func printDefaultTournament(w io.Writer, summary models.TournamentSummary) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "========================================")
+	fmt.Fprintf(w, "AI Round-Robin Tournament (%d agents, %d games per pairing)\n", len(summary.Agents), summary.MatchesPerPairing)
+	fmt.Fprintln(w, "========================================")
+	fmt.Fprintf(w, "Seed: %d\n", summary.Seed)
+
+	width := len("Agent")
+	for _, s := range summary.Standings {
+		width = max(width, len(s.Name))
+	}
+
+	fmt.Fprintln(w, "Standings:")
+	fmt.Fprintf(w, "  %-3s %-*s %6s %5s %6s %5s %7s %6s  %s\n", "#", width, "Agent", "Games", "Wins", "Losses", "Draws", "Score", "Elo", "95% CI")
+	for i, s := range summary.Standings {
+		fmt.Fprintf(w, "  %-3d %-*s %6d %5d %6d %5d %6.1f%% %6.0f  %.0f to %.0f\n",
+			i+1, width, s.Name, s.Games, s.Wins, s.Losses, s.Draws, s.Score*100, s.Elo, s.EloLow, s.EloHigh)
+	}
+	fmt.Fprintln(w, "----------------------------------------")
+
+	fmt.Fprintln(w, "Cross-table (score of row vs column):")
+	line := fmt.Sprintf("  %-*s", width, "")
+	for _, column := range summary.Standings {
+		line += fmt.Sprintf(" %*s", max(len(column.Name), 6), column.Name)
+	}
+	fmt.Fprintln(w, line)
+	for _, row := range summary.Standings {
+		line := fmt.Sprintf("  %-*s", width, row.Name)
+		for _, column := range summary.Standings {
+			line += fmt.Sprintf(" %*s", max(len(column.Name), 6), formatScore(summary.Pairings, row.Name, column.Name))
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+	fmt.Fprintln(w, "----------------------------------------")
+}
+
+// formatScore formats the score of agent a against agent b as a percentage, or "-" if they did not play.
+func formatScore(pairings []models.PairingResult, a, b string) string {
+	score := pairingScore(pairings, a, b)
+	if math.IsNaN(score) {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", score*100)
 }
@@ -0,0 +1,142 @@
+package aivsai
+
+import (
+	"digital-innovation/stratego/datagathering"
+	"digital-innovation/stratego/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Output formats of an AI vs AI run. The summary formats print once at the end,
+// the per-game formats write a record for every game as soon as it is finished.
+const (
+	FormatNone     = "none"  // plain text summary
+	FormatMarkdown = "md"    // markdown summary
+	FormatJSON     = "json"  // JSON summary
+	FormatJSONL    = "jsonl" // one JSON record per game, including the move history
+	FormatCSV      = "csv"   // one CSV row per game, without the move history
+
+	FormatSamples       = "samples"     // training samples of every move as NDJSON, see datagathering
+	FormatSamplesBinary = "samples-bin" // training samples of every move in the binary dataset format
+)
+
+// Formats are the output formats of an AI vs AI run
+var Formats = []string{FormatNone, FormatMarkdown, FormatJSON, FormatJSONL, FormatCSV, FormatSamples, FormatSamplesBinary}
+
+// CheckFormat returns an error listing the formats if format is not one of them
+func CheckFormat(format string, formats []string) error {
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf("unknown format %q, expected one of: %s", format, strings.Join(formats, ", "))
+	}
+	return nil
+}
+
+// gameWriter writes the record of every finished game.
+type gameWriter interface {
+	writeGame(record models.AiGameRecord) error
+	flush() error
+}
+
+// newGameWriter returns the writer of a per-game format, or nil for the summary formats.
+func newGameWriter(format string, w io.Writer) gameWriter {
+	switch format {
+	case FormatJSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(w)}
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}
+	case FormatSamples:
+		return &sampleWriter{samples: datagathering.NewNDJSONWriter(w)}
+	case FormatSamplesBinary:
+		return &sampleWriter{samples: datagathering.NewBinaryWriter(w)}
+	default:
+		return nil
+	}
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlWriter) writeGame(record models.AiGameRecord) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *jsonlWriter) flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+var csvHeader = []string{"game", "seed", "player1", "player2", "firstPlayer", "winner", "winCause", "rounds", "player1Score", "player2Score", "moves"}
+
+func (w *csvWriter) writeGame(record models.AiGameRecord) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	return w.writer.Write([]string{
+		strconv.Itoa(record.Game),
+		strconv.FormatUint(record.Seed, 10),
+		record.Player1,
+		record.Player2,
+		record.FirstPlayer,
+		record.Winner,
+		record.WinCause,
+		strconv.Itoa(record.Rounds),
+		strconv.Itoa(record.Player1Score),
+		strconv.Itoa(record.Player2Score),
+		strconv.Itoa(len(record.History)),
+	})
+}
+
+func (w *csvWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type sampleWriter struct {
+	samples datagathering.Writer
+}
+
+func (w *sampleWriter) writeGame(record models.AiGameRecord) error {
+	return datagathering.WriteGame(w.samples, datagathering.FromRecord(record))
+}
+
+func (w *sampleWriter) flush() error {
+	return w.samples.Flush()
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// stdout is the output when no file is given; closing it does nothing.
+type stdout struct {
+	io.Writer
+}
+
+func (stdout) Close() error {
+	return nil
+}
+
+// openOutput creates the file at path for writing, or returns stdout if path is empty.
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return stdout{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
@@ -0,0 +1,141 @@
+package aivsai
+
+import (
+	"bytes"
+	"digital-innovation/stratego/datagathering"
+	"digital-innovation/stratego/models"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONLWritesOneRecordPerGame(t *testing.T) {
+	var buffer bytes.Buffer
+	games := newGameWriter(FormatJSONL, &buffer)
+	summary, err := runAIvsAI(models.Fafo, models.Fato, Options{Matches: 3, Seed: 7, Workers: 2}, games)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := games.flush(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got: %d", len(lines))
+	}
+	rounds := 0
+	seen := map[int]bool{}
+	for _, line := range lines {
+		var record models.AiGameRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected a JSON record, got: %v", err)
+		}
+		if record.Seed != 7+uint64(record.Game-1) {
+			t.Errorf("Expected game %d to have seed %d, got: %d", record.Game, 7+record.Game-1, record.Seed)
+		}
+		if len(record.History) == 0 {
+			t.Errorf("Expected game %d to have a move history", record.Game)
+		}
+		seen[record.Game] = true
+		rounds += record.Rounds
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected games 1 to 3, got: %v", seen)
+	}
+	if rounds != summary.TotalRounds {
+		t.Errorf("Expected the records to add up to %d rounds, got: %d", summary.TotalRounds, rounds)
+	}
+}
+
+func TestCSVWritesHeaderAndRows(t *testing.T) {
+	var buffer bytes.Buffer
+	games := newGameWriter(FormatCSV, &buffer)
+	if _, err := runTournament([]string{models.Fafo, models.Fato}, Options{Matches: 2, Seed: 1, Workers: 1}, games); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := games.flush(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got: %d rows", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Errorf("Expected header %v, got: %v", csvHeader, rows[0])
+	}
+	for _, row := range rows[1:] {
+		if row[2] != models.Fafo || row[3] != models.Fato {
+			t.Errorf("Expected a game of fafo vs fato, got: %v", row)
+		}
+	}
+}
+
+func TestSamplesWriteEveryMove(t *testing.T) {
+	var buffer bytes.Buffer
+	games := newGameWriter(FormatSamplesBinary, &buffer)
+	result := playMatch(models.Fafo, models.Fato, "a", "b", models.ClassicRules(), 3, false)
+	if err := games.writeGame(result.record(0, "a", "b")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := games.flush(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	samples, err := datagathering.ReadBinary(&buffer)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(samples) != len(result.history) {
+		t.Errorf("Expected a sample for each of the %d moves, got: %d", len(result.history), len(samples))
+	}
+}
+
+func TestRecordKeepsSpecialRules(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.Name = models.RuleSetCustom
+	rules.AggressorAdvantage = true
+	result := playMatch(models.Fafo, models.Fafo, "a", "b", rules, 1, false)
+
+	var record models.AiGameRecord
+	encoded, _ := json.Marshal(result.record(0, "a", "b"))
+	if err := json.Unmarshal(encoded, &record); err != nil {
+		t.Fatalf("Expected the record to decode, got: %v", err)
+	}
+	if !record.Rules.AggressorAdvantage {
+		t.Fatalf("Expected the record to keep the special rules, got: %+v", record.Rules)
+	}
+	if _, err := datagathering.FromRecord(record).Samples(); err != nil {
+		t.Errorf("Expected the game to replay with its rules, got: %v", err)
+	}
+
+	// Equal ranks both lose in classic combat, so the attacker's wins do not replay without the rules
+	record.Rules = models.RuleSet{}
+	if _, err := datagathering.FromRecord(record).Samples(); err == nil {
+		t.Errorf("Expected the game not to replay with classic combat")
+	}
+}
+
+func TestSummaryFormatsHaveNoGameWriter(t *testing.T) {
+	for _, format := range []string{FormatNone, FormatMarkdown, FormatJSON} {
+		if newGameWriter(format, &bytes.Buffer{}) != nil {
+			t.Errorf("Expected no game writer for %s", format)
+		}
+	}
+}
+
+func TestRunRejectsUnknownFormat(t *testing.T) {
+	options := Options{Matches: 1, Seed: 1, Workers: 1, Format: "xml"}
+	err := RunAIvsAI(models.Fafo, models.Fafo, options)
+	if err == nil || !strings.Contains(err.Error(), FormatSamplesBinary) {
+		t.Errorf("Expected an error listing the formats, got: %v", err)
+	}
+	if err := RunTournament([]string{models.Fafo, models.Fato}, options); err == nil {
+		t.Error("Expected a tournament to reject an unknown format")
+	}
+}
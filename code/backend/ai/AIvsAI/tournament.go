@@ -0,0 +1,210 @@
+package aivsai
+
+import (
+	"digital-innovation/stratego/models"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+)
+
+// eloBase is the average rating of the agents in a tournament.
+const eloBase = 1500.0
+
+// runTournament plays every pairing of the agents options.Matches times on a pool of workers.
+// Game k of the tournament is played with seed options.Seed+k, which alternates who moves first within a pairing.
+// If games is not nil, it gets the record of every game.
+func runTournament(agents []string, options Options, games gameWriter) (models.TournamentSummary, error) {
+	if err := validateAgents(agents); err != nil {
+		return models.TournamentSummary{}, err
+	}
+	// The standings are indexed by name, an agent must not play itself
+	for i, agent := range agents {
+		if slices.Contains(agents[:i], agent) {
+			return models.TournamentSummary{}, fmt.Errorf("agent %q is listed twice", agent)
+		}
+	}
+
+	var pairings []models.PairingResult
+	for i := range agents {
+		for j := i + 1; j < len(agents); j++ {
+			pairings = append(pairings, models.PairingResult{Agent1: agents[i], Agent2: agents[j]})
+		}
+	}
+
+	matches := options.Matches
+	rules := options.ruleSet()
+	results := make([]matchResult, len(pairings)*matches)
+	var writeErr error
+	forEachGame(len(results), options.Workers, func(k int) {
+		// Only read the names, done updates the results of the pairing at the same time
+		pairing := &pairings[k/matches]
+		results[k] = playMatch(pairing.Agent1, pairing.Agent2, pairing.Agent1, pairing.Agent2, rules, options.Seed+uint64(k), false)
+	}, func(k int) {
+		pairing := &pairings[k/matches]
+		result := results[k]
+		switch result.winner {
+		case 0:
+			pairing.Wins1++
+		case 1:
+			pairing.Wins2++
+		default:
+			pairing.Draws++
+		}
+		if options.Logging {
+			fmt.Fprintf(os.Stderr, "%s vs %s, game %3d (seed %d): %s (%d rounds)\n",
+				pairing.Agent1, pairing.Agent2, k%matches+1, result.seed, describeResult(*pairing, result), result.rounds)
+		}
+		if games != nil && writeErr == nil {
+			writeErr = games.writeGame(result.record(k, pairing.Agent1, pairing.Agent2))
+		}
+		results[k].initial, results[k].history = nil, nil
+	})
+	if writeErr != nil {
+		return models.TournamentSummary{}, writeErr
+	}
+
+	return models.TournamentSummary{
+		Agents:            agents,
+		MatchesPerPairing: matches,
+		Seed:              options.Seed,
+		Pairings:          pairings,
+		Standings:         computeStandings(agents, pairings),
+	}, nil
+}
+
+func describeResult(pairing models.PairingResult, result matchResult) string {
+	switch result.winner {
+	case 0:
+		return fmt.Sprintf("%s wins - %s", pairing.Agent1, result.cause)
+	case 1:
+		return fmt.Sprintf("%s wins - %s", pairing.Agent2, result.cause)
+	default:
+		return "draw"
+	}
+}
+
+// computeStandings sums up the results of every agent and estimates their Elo ratings.
+func computeStandings(agents []string, pairings []models.PairingResult) []models.AgentStanding {
+	index := make(map[string]int, len(agents))
+	standings := make([]models.AgentStanding, len(agents))
+	for i, agent := range agents {
+		index[agent] = i
+		standings[i].Name = agent
+	}
+
+	for _, p := range pairings {
+		a, b := &standings[index[p.Agent1]], &standings[index[p.Agent2]]
+		games := p.Wins1 + p.Wins2 + p.Draws
+		a.Games += games
+		b.Games += games
+		a.Wins += p.Wins1
+		a.Losses += p.Wins2
+		b.Wins += p.Wins2
+		b.Losses += p.Wins1
+		a.Draws += p.Draws
+		b.Draws += p.Draws
+	}
+
+	ratings := estimateElo(agents, pairings)
+	for i := range standings {
+		s := &standings[i]
+		if s.Games > 0 {
+			s.Score = (float64(s.Wins) + 0.5*float64(s.Draws)) / float64(s.Games)
+		}
+		margin := eloMargin(s.Score, s.Games)
+		s.Elo = ratings[i]
+		s.EloLow = ratings[i] - margin
+		s.EloHigh = ratings[i] + margin
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Elo > standings[j].Elo
+	})
+	return standings
+}
+
+// estimateElo fits a Bradley-Terry model to the pairings, counting draws as half a win for both sides.
+// Every pairing gets one extra virtual draw, so an agent that won or lost every game still has a finite rating.
+// The ratings are shifted so they average eloBase.
+func estimateElo(agents []string, pairings []models.PairingResult) []float64 {
+	n := len(agents)
+	index := make(map[string]int, n)
+	for i, agent := range agents {
+		index[agent] = i
+	}
+
+	points := make([]float64, n)
+	games := make([][]float64, n)
+	for i := range games {
+		games[i] = make([]float64, n)
+	}
+	for _, p := range pairings {
+		a, b := index[p.Agent1], index[p.Agent2]
+		total := float64(p.Wins1+p.Wins2+p.Draws) + 1
+		points[a] += float64(p.Wins1) + 0.5*float64(p.Draws) + 0.5
+		points[b] += float64(p.Wins2) + 0.5*float64(p.Draws) + 0.5
+		games[a][b] += total
+		games[b][a] += total
+	}
+
+	// Minorization-maximization updates of the strengths
+	strength := make([]float64, n)
+	for i := range strength {
+		strength[i] = 1
+	}
+	for range 1000 {
+		for i := range strength {
+			denominator := 0.0
+			for j := range strength {
+				if games[i][j] > 0 {
+					denominator += games[i][j] / (strength[i] + strength[j])
+				}
+			}
+			if denominator > 0 {
+				strength[i] = points[i] / denominator
+			}
+		}
+	}
+
+	ratings := make([]float64, n)
+	mean := 0.0
+	for i, s := range strength {
+		ratings[i] = 400 * math.Log10(s)
+		mean += ratings[i] / float64(n)
+	}
+	for i := range ratings {
+		ratings[i] += eloBase - mean
+	}
+	return ratings
+}
+
+// eloMargin returns the half-width of the 95% confidence interval of a rating based on score over games.
+// It maps the standard error of the score to Elo through the slope of the logistic curve at that score.
+func eloMargin(score float64, games int) float64 {
+	if games == 0 {
+		return math.Inf(1)
+	}
+	// Keep the score away from 0 and 1, where the slope is infinite
+	limit := 0.5 / float64(games)
+	score = math.Min(math.Max(score, limit), 1-limit)
+	standardError := math.Sqrt(score * (1 - score) / float64(games))
+	return 1.96 * standardError * 400 / (math.Ln10 * score * (1 - score))
+}
+
+// pairingScore returns the score of agent a against agent b, with draws counting half, or NaN if they did not play.
+func pairingScore(pairings []models.PairingResult, a, b string) float64 {
+	for _, p := range pairings {
+		games := float64(p.Wins1 + p.Wins2 + p.Draws)
+		switch {
+		case games == 0:
+			continue
+		case p.Agent1 == a && p.Agent2 == b:
+			return (float64(p.Wins1) + 0.5*float64(p.Draws)) / games
+		case p.Agent1 == b && p.Agent2 == a:
+			return (float64(p.Wins2) + 0.5*float64(p.Draws)) / games
+		}
+	}
+	return math.NaN()
+}
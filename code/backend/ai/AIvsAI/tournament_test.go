@@ -0,0 +1,75 @@
+package aivsai
+
+import (
+	"digital-innovation/stratego/models"
+	"math"
+	"testing"
+)
+
+func TestEstimateElo(t *testing.T) {
+	agents := []string{"a", "b", "c"}
+	pairings := []models.PairingResult{
+		{Agent1: "a", Agent2: "b", Wins1: 30, Wins2: 10},
+		{Agent1: "a", Agent2: "c", Wins1: 30, Wins2: 10},
+		{Agent1: "b", Agent2: "c", Wins1: 20, Wins2: 20},
+	}
+
+	ratings := estimateElo(agents, pairings)
+	if math.Abs(ratings[0]+ratings[1]+ratings[2]-3*eloBase) > 1e-6 {
+		t.Errorf("Expected the ratings to average %v, got: %v", eloBase, ratings)
+	}
+	if ratings[0] <= ratings[1] || math.Abs(ratings[1]-ratings[2]) > 1e-6 {
+		t.Errorf("Expected a ahead and b level with c, got: %v", ratings)
+	}
+}
+
+func TestEloMargin(t *testing.T) {
+	if eloMargin(0.5, 400) >= eloMargin(0.5, 100) {
+		t.Errorf("Expected more games to narrow the confidence interval")
+	}
+	if margin := eloMargin(1, 10); math.IsInf(margin, 0) || math.IsNaN(margin) {
+		t.Errorf("Expected a finite margin for a perfect score, got: %v", margin)
+	}
+}
+
+func TestRunTournament(t *testing.T) {
+	agents := []string{models.Fafo, models.Fato, models.Heuristic}
+	summary, err := runTournament(agents, Options{Matches: 4, Seed: 1, Workers: 4}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(summary.Pairings) != 3 {
+		t.Fatalf("Expected 3 pairings, got: %d", len(summary.Pairings))
+	}
+	for _, p := range summary.Pairings {
+		if p.Wins1+p.Wins2+p.Draws != 4 {
+			t.Errorf("Expected 4 games between %s and %s, got: %+v", p.Agent1, p.Agent2, p)
+		}
+	}
+	for _, s := range summary.Standings {
+		if s.Games != 8 || s.Wins+s.Losses+s.Draws != s.Games {
+			t.Errorf("Expected 8 games for %s, got: %+v", s.Name, s)
+		}
+		if s.EloLow > s.Elo || s.EloHigh < s.Elo {
+			t.Errorf("Expected the rating of %s within its confidence interval, got: %+v", s.Name, s)
+		}
+	}
+}
+
+func TestRunTournamentRejectsInvalidAgents(t *testing.T) {
+	testCases := []struct {
+		name   string
+		agents []string
+	}{
+		{"Duplicate", []string{models.Fafo, models.Fato, models.Fafo}},
+		{"Unknown", []string{models.Fafo, "alphazero"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := runTournament(tc.agents, Options{Matches: 1, Seed: 1, Workers: 1}, nil); err == nil {
+				t.Errorf("Expected the agents %v to be rejected", tc.agents)
+			}
+		})
+	}
+}
@@ -2,30 +2,45 @@ package ai
 
 import (
 	"digital-innovation/stratego/engine"
+	"math/rand/v2"
 )
 
 // AI is the interface that all AI implementations must satisfy.
 // It extends the PlayerController interface
 type AI interface {
 	engine.PlayerController
+	SetSeed(seed uint64)
 }
 
 type BaseAI struct {
 	player *engine.Player
 	memory *AIMemory
+	rng    *rand.Rand
 }
 
 func NewBaseAI(player *engine.Player, hasMemory bool) *BaseAI {
 	var memory *AIMemory = nil
 	if hasMemory {
 		memory = NewAIMemory()
+		memory.SetOwner(player)
 	}
 	return &BaseAI{
 		player: player,
 		memory: memory,
+		rng:    rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
 	}
 }
 
+// GetRand returns the AI's random source; all random decisions of an AI should come from it
+func (ai *BaseAI) GetRand() *rand.Rand {
+	return ai.rng
+}
+
+// SetSeed reseeds the AI's random source, so the AI makes the same decisions in the same game
+func (ai *BaseAI) SetSeed(seed uint64) {
+	ai.rng = rand.New(rand.NewPCG(seed, seed))
+}
+
 // GetPlayer returns the player associated with the AI.
 func (ai *BaseAI) GetPlayer() *engine.Player {
 	return ai.player
@@ -43,25 +58,39 @@ func (ai *BaseAI) GetMemory() *AIMemory {
 
 // AnalyzeMove is called after opponent moves - override in subclasses for learning
 // Default implementation updates memory automatically
-func (ai *BaseAI) AnalyzeMove(move engine.Move, opponent *engine.Player, round int) {
+func (ai *BaseAI) AnalyzeMove(move engine.ViewMove, round int) {
 	if ai.memory == nil {
 		return
 	}
 
-	from := move.GetFrom()
-	to := move.GetTo()
-
-	if ai.memory.Recall(from) != nil {
-		ai.memory.MovePiece(from, to)
-	}
+	ai.memory.MovePiece(move.From, move.To)
 }
 
-// ObserveCombat is called when combat occurs - override for learning from reveals
-// Default implementation updates memory with revealed pieces
-func (ai *BaseAI) ObserveCombat(attackerPos, defenderPos engine.Position, attackerPiece, defenderPiece *engine.Piece, round int) {
+// ObserveCombat is called when combat occurs, including the AI's own attacks - override for learning from reveals
+// Default implementation updates memory with revealed enemy pieces and counts captured enemy pieces.
+// The defender has no type if silent defense keeps it hidden from the AI
+func (ai *BaseAI) ObserveCombat(attackerPos, defenderPos engine.Position, attackerPiece, defenderPiece engine.SeenPiece, round int) {
 	if ai.memory == nil {
 		return
 	}
 
 	ai.memory.UpdateFromCombat(attackerPos, defenderPos, attackerPiece, defenderPiece, round)
+	if entry := ai.memory.Recall(defenderPos); entry != nil && entry.Piece.OwnerID == ai.player.GetID() {
+		ai.memory.Forget(defenderPos) // memory only tracks enemy pieces
+	}
+	for _, piece := range []engine.SeenPiece{attackerPiece, defenderPiece} {
+		if piece.Type != nil && !piece.Alive && piece.OwnerID != ai.player.GetID() {
+			ai.memory.RecordCapture(piece)
+		}
+	}
+}
+
+// ObserveRescue is called when a piece is brought back by the rescue rule.
+// Default implementation remembers a rescued enemy piece, which is revealed, and no longer counts it as captured
+func (ai *BaseAI) ObserveRescue(pos engine.Position, piece engine.SeenPiece, round int) {
+	if ai.memory == nil || piece.OwnerID == ai.player.GetID() {
+		return
+	}
+
+	ai.memory.RecordRescue(pos, piece, round)
 }
@@ -0,0 +1,316 @@
+package ai
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"sort"
+)
+
+// sinkhornIterations is how often the belief distributions are rescaled to match the remaining piece counts.
+const sinkhornIterations = 30
+
+// belief is what the memory knows about one enemy piece: which ranks are still possible
+// (the likelihood of everything observed so far) and the resulting distribution over ranks.
+// Both are indexed like ArmyTypes.
+type belief struct {
+	possible     [12]bool
+	distribution [12]float64
+}
+
+func newBelief() *belief {
+	b := &belief{}
+	for i := range b.possible {
+		b.possible[i] = true
+	}
+	return b
+}
+
+// markMoved rules out the pieces that cannot move.
+func (b *belief) markMoved() {
+	b.possible[rankIndex(models.Flag.GetRank())] = false
+	b.possible[rankIndex(models.Bomb.GetRank())] = false
+}
+
+// markKnown rules out every rank but the given one.
+func (b *belief) markKnown(rank byte) {
+	index := rankIndex(rank)
+	for i := range b.possible {
+		b.possible[i] = i == index
+	}
+}
+
+// markBeats rules out the ranks that do not win against the attacker.
+func (b *belief) markBeats(attacker *models.PieceType, rules models.RuleSet) {
+	for i := range ArmyTypes {
+		if engine.PredictCombatWithRules(attacker, &ArmyTypes[i], rules) != engine.CombatDefenderWon {
+			b.possible[i] = false
+		}
+	}
+}
+
+// knownRank returns the first possible rank.
+func (b *belief) knownRank() byte {
+	for i, possible := range b.possible {
+		if possible {
+			return ArmyTypes[i].GetRank()
+		}
+	}
+	return 0
+}
+
+// rankIndex returns the index of a rank in ArmyTypes.
+func rankIndex(rank byte) int {
+	for i, pieceType := range ArmyTypes {
+		if pieceType.GetRank() == rank {
+			return i
+		}
+	}
+	return -1
+}
+
+// SyncView adds a belief for every enemy piece in the view that the memory does not track yet,
+// and drops beliefs about squares that no longer hold an enemy piece. Moved and revealed pieces
+// in the view narrow down the ranks. AIs call it before querying the beliefs.
+func (m *AIMemory) SyncView(view *engine.PlayerView) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rules = view.GetRules()
+
+	for y := range view.GetHeight() {
+		for x := range view.GetWidth() {
+			vp := view.GetPieceAt(engine.NewPosition(x, y))
+			if !vp.IsEnemy() {
+				m.beliefs[y][x] = nil
+				continue
+			}
+
+			b := m.beliefs[y][x]
+			if b == nil {
+				b = newBelief()
+				m.beliefs[y][x] = b
+			}
+			if vp.Moved {
+				b.markMoved()
+			}
+			if vp.Knowledge == engine.PieceRevealed {
+				b.markKnown(vp.Type.GetRank())
+			}
+		}
+	}
+	m.posterior = false
+}
+
+// moveBelief moves the belief about an enemy piece along with the piece. Callers hold the lock.
+func (m *AIMemory) moveBelief(from, to engine.Position) {
+	b := m.beliefs[from.Y][from.X]
+	if b == nil {
+		b = newBelief()
+	}
+	b.markMoved()
+	if abs(from.X-to.X) > 1 || abs(from.Y-to.Y) > 1 {
+		b.markKnown(models.Scout.GetRank())
+	}
+
+	m.beliefs[from.Y][from.X] = nil
+	m.beliefs[to.Y][to.X] = b
+	m.posterior = false
+}
+
+// combatBelief updates the beliefs after combat, which reveals both pieces. Callers hold the lock.
+// The attacker's square is empty afterwards, and the defender's square holds the survivor if any.
+// An enemy defender hidden by silent defense (no type while the own attacker lost) keeps its belief,
+// narrowed to the ranks that beat the attacker.
+func (m *AIMemory) combatBelief(attackerPos, defenderPos engine.Position, attackerPiece, defenderPiece engine.SeenPiece) {
+	hidden := m.beliefs[defenderPos.Y][defenderPos.X]
+	m.beliefs[attackerPos.Y][attackerPos.X] = nil
+	m.beliefs[defenderPos.Y][defenderPos.X] = nil
+	m.posterior = false
+
+	if defenderPiece.Type == nil && !attackerPiece.Alive && m.owner != nil && attackerPiece.OwnerID == m.owner.GetID() {
+		if hidden == nil {
+			hidden = newBelief()
+		}
+		hidden.markBeats(attackerPiece.Type, m.rules)
+		m.beliefs[defenderPos.Y][defenderPos.X] = hidden
+		return
+	}
+
+	survivor := defenderPiece
+	if attackerPiece.Alive {
+		survivor = attackerPiece
+	}
+	if survivor.Type != nil && survivor.Alive && (m.owner == nil || survivor.OwnerID != m.owner.GetID()) {
+		b := newBelief()
+		b.markKnown(survivor.Type.GetRank())
+		m.beliefs[defenderPos.Y][defenderPos.X] = b
+	}
+}
+
+// updatePosterior combines the beliefs with the number of enemy pieces of each rank that are left.
+// Known pieces keep their rank. Every other piece starts with an equal weight on the ranks it can still have;
+// the weights are then alternately scaled so each rank sums to the number of unknown pieces left of that rank
+// and each piece sums to one.
+// This spreads, e.g., the six bombs over the pieces that have not moved. Callers hold the write lock.
+func (m *AIMemory) updatePosterior() {
+	if m.posterior {
+		return
+	}
+	m.posterior = true
+
+	// Pieces with a single possible rank are known and take that rank out of the counts
+	var remaining [12]float64
+	for i, pieceType := range ArmyTypes {
+		remaining[i] = float64(m.rules.Count(pieceType) - m.captured[pieceType.GetRank()])
+	}
+	var tracked []*belief
+	for y := range m.beliefs {
+		for x := range m.beliefs[y] {
+			b := m.beliefs[y][x]
+			if b == nil {
+				continue
+			}
+			possible := 0
+			for i := range b.possible {
+				b.distribution[i] = 0
+				if b.possible[i] {
+					b.distribution[i] = 1
+					possible++
+				}
+			}
+			if possible == 1 {
+				remaining[rankIndex(b.knownRank())]--
+			} else {
+				tracked = append(tracked, b)
+			}
+		}
+	}
+	if len(tracked) == 0 {
+		return
+	}
+
+	// Scale the counts to the unknown pieces, in case the memory did not see every capture
+	total := 0.0
+	for i := range remaining {
+		remaining[i] = max(remaining[i], 0)
+		total += remaining[i]
+	}
+	if total > 0 {
+		for i := range remaining {
+			remaining[i] *= float64(len(tracked)) / total
+		}
+	}
+
+	for range sinkhornIterations {
+		for i := range remaining {
+			sum := 0.0
+			for _, b := range tracked {
+				sum += b.distribution[i]
+			}
+			if sum > 0 {
+				for _, b := range tracked {
+					b.distribution[i] *= remaining[i] / sum
+				}
+			}
+		}
+		for _, b := range tracked {
+			normalize(b)
+		}
+	}
+}
+
+// normalize scales the distribution to sum to one. If the counts ruled out every possible rank,
+// the belief falls back to an even spread over the possible ranks.
+func normalize(b *belief) {
+	sum := 0.0
+	for _, p := range b.distribution {
+		sum += p
+	}
+	if sum == 0 {
+		for i, possible := range b.possible {
+			if possible {
+				b.distribution[i] = 1
+				sum++
+			}
+		}
+		if sum == 0 {
+			return
+		}
+	}
+	for i := range b.distribution {
+		b.distribution[i] /= sum
+	}
+}
+
+// ProbabilityOf returns the probability that the enemy piece at pos has the given rank,
+// or 0 if the memory tracks no enemy piece there.
+func (m *AIMemory) ProbabilityOf(pos engine.Position, rank byte) float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	index := rankIndex(rank)
+	b := m.beliefs[pos.Y][pos.X]
+	if b == nil || index < 0 {
+		return 0
+	}
+	m.updatePosterior()
+	return b.distribution[index]
+}
+
+// MostLikelyRank returns the most likely rank of the enemy piece at pos and its probability.
+// The probability is 0 if the memory tracks no enemy piece there.
+func (m *AIMemory) MostLikelyRank(pos engine.Position) (byte, float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	b := m.beliefs[pos.Y][pos.X]
+	if b == nil {
+		return 0, 0
+	}
+	m.updatePosterior()
+	best := 0
+	for i, p := range b.distribution {
+		if p > b.distribution[best] {
+			best = i
+		}
+	}
+	return ArmyTypes[best].GetRank(), b.distribution[best]
+}
+
+// MostLikelyFlagPositions returns the positions of the enemy pieces that can still be the flag,
+// most likely first.
+func (m *AIMemory) MostLikelyFlagPositions() []engine.Position {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.updatePosterior()
+	flag := rankIndex(models.Flag.GetRank())
+	type candidate struct {
+		pos         engine.Position
+		probability float64
+	}
+	var candidates []candidate
+	for y := range m.beliefs {
+		for x := range m.beliefs[y] {
+			if b := m.beliefs[y][x]; b != nil && b.distribution[flag] > 0 {
+				candidates = append(candidates, candidate{engine.NewPosition(x, y), b.distribution[flag]})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].probability > candidates[j].probability
+	})
+
+	positions := make([]engine.Position, len(candidates))
+	for i, c := range candidates {
+		positions[i] = c.pos
+	}
+	return positions
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"math"
+	"testing"
+)
+
+func newBeliefGame() (*game.Game, *engine.Player, *engine.Player, *AIMemory) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+
+	memory := NewAIMemory()
+	memory.SetOwner(&player1)
+	memory.SyncView(g.ViewFor(&player1))
+	return g, &player1, &player2, memory
+}
+
+func TestBeliefsStartFromPieceCounts(t *testing.T) {
+	_, _, _, memory := newBeliefGame()
+
+	pos := engine.NewPosition(3, 2)
+	if p := memory.ProbabilityOf(pos, models.Bomb.GetRank()); math.Abs(p-6.0/40) > 1e-9 {
+		t.Errorf("Expected a bomb probability of 6/40, got: %v", p)
+	}
+	if p := memory.ProbabilityOf(pos, models.Flag.GetRank()); math.Abs(p-1.0/40) > 1e-9 {
+		t.Errorf("Expected a flag probability of 1/40, got: %v", p)
+	}
+	if p := memory.ProbabilityOf(engine.NewPosition(3, 7), models.Flag.GetRank()); p != 0 {
+		t.Errorf("Expected no belief about an own square, got: %v", p)
+	}
+}
+
+func TestBeliefsFromMoves(t *testing.T) {
+	_, _, _, memory := newBeliefGame()
+
+	// A piece that moves is neither bomb nor flag, which makes the unmoved pieces more likely to be one
+	moved := engine.NewPosition(0, 4)
+	memory.MovePiece(engine.NewPosition(0, 3), moved)
+	if p := memory.ProbabilityOf(moved, models.Bomb.GetRank()); p != 0 {
+		t.Errorf("Expected a moved piece not to be a bomb, got: %v", p)
+	}
+	if p := memory.ProbabilityOf(engine.NewPosition(3, 2), models.Bomb.GetRank()); p <= 6.0/40 {
+		t.Errorf("Expected the bomb probability of unmoved pieces to rise, got: %v", p)
+	}
+
+	// Only scouts move more than one square
+	before := memory.ProbabilityOf(moved, models.Scout.GetRank())
+	scout := engine.NewPosition(1, 5)
+	memory.MovePiece(engine.NewPosition(1, 3), scout)
+	if p := memory.ProbabilityOf(scout, models.Scout.GetRank()); math.Abs(p-1) > 1e-9 {
+		t.Errorf("Expected a long move to reveal a scout, got: %v", p)
+	}
+	if p := memory.ProbabilityOf(moved, models.Scout.GetRank()); p >= before {
+		t.Errorf("Expected a known scout to lower the scout probability of others, got: %v", p)
+	}
+}
+
+func TestBeliefsFromCombat(t *testing.T) {
+	g, player1, player2, memory := newBeliefGame()
+
+	// An enemy general beats one of our pieces and is known from then on
+	attackerPos, defenderPos := engine.NewPosition(4, 3), engine.NewPosition(4, 4)
+	general := engine.NewPiece(models.General, player2)
+	loser := g.Board.GetPieceAt(engine.NewPosition(4, 6))
+	loser.Eliminate()
+	memory.MovePiece(attackerPos, defenderPos)
+	memory.UpdateFromCombat(attackerPos, defenderPos, engine.NewSeenPiece(general), engine.NewSeenPiece(loser), 1)
+	if p := memory.ProbabilityOf(defenderPos, models.General.GetRank()); math.Abs(p-1) > 1e-9 {
+		t.Errorf("Expected the surviving general to be known, got: %v", p)
+	}
+	if p := memory.ProbabilityOf(engine.NewPosition(5, 3), models.General.GetRank()); p != 0 {
+		t.Errorf("Expected no other piece to be the only general, got: %v", p)
+	}
+
+	// A captured marshal leaves no marshal for the other pieces
+	marshal := engine.NewPiece(models.Marshal, player2)
+	marshal.Eliminate()
+	ownMarshal := engine.NewPiece(models.Marshal, player1)
+	ownMarshal.Eliminate()
+	memory.UpdateFromCombat(engine.NewPosition(6, 3), engine.NewPosition(6, 4), engine.NewSeenPiece(marshal), engine.NewSeenPiece(ownMarshal), 2)
+	memory.RecordCapture(engine.NewSeenPiece(marshal))
+	if p := memory.ProbabilityOf(engine.NewPosition(7, 3), models.Marshal.GetRank()); p != 0 {
+		t.Errorf("Expected no marshal probability after its capture, got: %v", p)
+	}
+}
+
+func TestBeliefsFromSilentDefense(t *testing.T) {
+	_, player1, _, memory := newBeliefGame()
+
+	// Our captain loses against a defender that silent defense keeps hidden: it is a bomb or stronger than a captain
+	captain := engine.NewPiece(models.Captain, player1)
+	captain.Eliminate()
+	defenderPos := engine.NewPosition(4, 3)
+	hidden := engine.SeenPiece{OwnerID: 1, Alive: true}
+	memory.UpdateFromCombat(engine.NewPosition(4, 4), defenderPos, engine.NewSeenPiece(captain), hidden, 1)
+	for _, pieceType := range []models.PieceType{models.Flag, models.Spy, models.Scout, models.Captain} {
+		if p := memory.ProbabilityOf(defenderPos, pieceType.GetRank()); p != 0 {
+			t.Errorf("Expected the hidden defender not to be a %s, got: %v", pieceType.GetName(), p)
+		}
+	}
+	if p := memory.ProbabilityOf(defenderPos, models.Bomb.GetRank()); p <= 0 {
+		t.Errorf("Expected the hidden defender to possibly be a bomb, got: %v", p)
+	}
+	if p := memory.ProbabilityOf(defenderPos, models.Major.GetRank()); p <= 0 {
+		t.Errorf("Expected the hidden defender to possibly be a major, got: %v", p)
+	}
+}
+
+func TestMostLikelyFlagPositions(t *testing.T) {
+	_, _, _, memory := newBeliefGame()
+
+	// Move the enemy pieces in row 3 that are not behind a lake
+	for _, x := range []int{0, 1, 4, 5, 8, 9} {
+		memory.MovePiece(engine.NewPosition(x, 3), engine.NewPosition(x, 4))
+	}
+
+	positions := memory.MostLikelyFlagPositions()
+	if len(positions) != 34 {
+		t.Fatalf("Expected 34 possible flag positions, got: %d", len(positions))
+	}
+	for _, pos := range positions {
+		if pos.Y > 3 {
+			t.Errorf("Expected no moved piece among the flag positions, got: %v", pos)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package ai
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+)
+
+// ArmyTypes are the piece types an army can have, the rule set of the game decides how many of each.
+var ArmyTypes = []models.PieceType{
+	models.Flag, models.Bomb, models.Spy, models.Scout, models.Miner, models.Sergeant,
+	models.Lieutenant, models.Captain, models.Major, models.Colonel, models.General, models.Marshal,
+}
+
+// On sampled boards own pieces get color 0 and enemy pieces color 1.
+const (
+	OwnColor   byte = 0
+	EnemyColor byte = 1
+)
+
+// KnownConfidence is the confidence from which a remembered enemy piece is treated as known.
+const KnownConfidence = 0.5
+
+// hiddenSquare is an enemy square whose piece is not known.
+type hiddenSquare struct {
+	square uint8
+	moved  bool
+}
+
+// Determinizer samples full-information boards from what an AI knows, so search-based AIs can treat
+// the hidden enemy pieces as if they were known. It is read-only after creation, so several goroutines
+// may sample from it at once, each with its own random source.
+type Determinizer struct {
+	board   engine.CompactBoard // own and known enemy pieces
+	hidden  []hiddenSquare
+	unknown []byte // piece IDs of the enemy pieces that are not known
+}
+
+// NewDeterminizer collects what the view and the memory tell about the enemy.
+// Enemy pieces that are revealed, remembered with at least KnownConfidence or certain by the memory's beliefs
+// are placed as known, and pieces the memory saw being captured are left out of the unknown pieces.
+// The enemy army is taken from the rule set of the view.
+// The memory may be nil; callers sync it with the view first.
+func NewDeterminizer(view *engine.PlayerView, memory *AIMemory) *Determinizer {
+	d := &Determinizer{board: view.EmptyCompactBoard()}
+	rules := view.GetRules()
+	remaining := make(map[byte]int, len(ArmyTypes))
+	for _, pieceType := range ArmyTypes {
+		id, _ := engine.GetPieceIDFromRank(pieceType.GetRank())
+		remaining[id] = rules.Count(pieceType)
+		if memory != nil {
+			remaining[id] -= memory.CapturedCount(pieceType.GetRank())
+		}
+	}
+
+	player := view.GetPlayer()
+	for y := range view.GetHeight() {
+		for x := range view.GetWidth() {
+			pos := engine.NewPosition(x, y)
+			vp := view.GetPieceAt(pos)
+			square := engine.SquareIndex(pos)
+
+			switch {
+			case vp.Knowledge == engine.PieceOwn:
+				id, _ := engine.GetPieceIDFromRank(vp.Type.GetRank())
+				d.board.SetPiece(square, id, OwnColor, vp.Moved)
+			case vp.IsEnemy():
+				if pieceType := knownEnemyType(pos, vp, memory, player); pieceType != nil {
+					id, _ := engine.GetPieceIDFromRank(pieceType.GetRank())
+					d.board.SetPiece(square, id, EnemyColor, vp.Moved)
+					remaining[id]--
+				} else {
+					d.hidden = append(d.hidden, hiddenSquare{square: square, moved: vp.Moved})
+				}
+			}
+		}
+	}
+
+	for _, pieceType := range ArmyTypes {
+		id, _ := engine.GetPieceIDFromRank(pieceType.GetRank())
+		for range remaining[id] {
+			d.unknown = append(d.unknown, id)
+		}
+	}
+	return d
+}
+
+// knownEnemyType returns the type of an enemy piece if it is revealed, remembered with enough confidence,
+// or the only rank its belief still allows.
+func knownEnemyType(pos engine.Position, vp engine.ViewPiece, memory *AIMemory, player *engine.Player) *models.PieceType {
+	if vp.Knowledge == engine.PieceRevealed {
+		return vp.Type
+	}
+	if memory == nil {
+		return nil
+	}
+	entry := memory.Recall(pos)
+	if entry != nil && entry.Confidence >= KnownConfidence && entry.Piece.OwnerID != player.GetID() {
+		return entry.Piece.Type
+	}
+	if rank, probability := memory.MostLikelyRank(pos); probability >= 1 {
+		return &ArmyTypes[rankIndex(rank)]
+	}
+	return nil
+}
+
+// Sample returns a determinization: a full board in which every hidden enemy square holds one of the unknown
+// pieces. Squares of enemy pieces that have moved never get a bomb or the flag. When the unknown pieces do
+// not match the hidden squares (e.g. after captures the AI did not observe), surplus pieces are dropped,
+// keeping the flag, and missing pieces are filled with random movable ones.
+func (d *Determinizer) Sample(rng *rand.Rand) engine.CompactBoard {
+	board := d.board
+	pool := make([]byte, len(d.unknown))
+	copy(pool, d.unknown)
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	// Moved pieces first, so they can take the movable pieces
+	order := make([]hiddenSquare, 0, len(d.hidden))
+	for _, h := range d.hidden {
+		if h.moved {
+			order = append(order, h)
+		}
+	}
+	for _, h := range d.hidden {
+		if !h.moved {
+			order = append(order, h)
+		}
+	}
+
+	// The flag is never dropped, so place it first on a random unmoved square
+	if i := indexOf(pool, engine.PieceIDFlag); i >= 0 {
+		if unmoved := len(order) - countMoved(order); unmoved > 0 {
+			pick := len(order) - 1 - rng.IntN(unmoved)
+			board.SetPiece(order[pick].square, engine.PieceIDFlag, EnemyColor, false)
+			order = append(order[:pick], order[pick+1:]...)
+			pool = append(pool[:i], pool[i+1:]...)
+		}
+	}
+
+	for _, h := range order {
+		id := takePiece(&pool, h.moved, rng)
+		board.SetPiece(h.square, id, EnemyColor, h.moved)
+	}
+	return board
+}
+
+// takePiece removes a piece ID from the pool and returns it; moved squares only take movable pieces.
+func takePiece(pool *[]byte, movable bool, rng *rand.Rand) byte {
+	for i, id := range *pool {
+		if !movable || (id != engine.PieceIDBomb && id != engine.PieceIDFlag) {
+			*pool = append((*pool)[:i], (*pool)[i+1:]...)
+			return id
+		}
+	}
+	// Nothing suitable left, guess a movable piece
+	return engine.PieceIDSpy + byte(rng.IntN(int(engine.PieceIDMarshal-engine.PieceIDSpy+1)))
+}
+
+func indexOf(pool []byte, id byte) int {
+	for i, p := range pool {
+		if p == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func countMoved(squares []hiddenSquare) int {
+	n := 0
+	for _, h := range squares {
+		if h.moved {
+			n++
+		}
+	}
+	return n
+}
+
+// HomeRow returns the back row of the view's player, 0 or the last row of the board (9 on the classic board),
+// judged by its flag or else by where its pieces stand.
+func HomeRow(view *engine.PlayerView) int {
+	player := view.GetPlayer()
+	last := view.GetHeight() - 1
+	sum, count := 0, 0
+	for _, piece := range player.GetAlivePieces() {
+		if pos, exists := player.GetPiecePosition(piece); exists {
+			if piece.GetRank() == models.Flag.GetRank() {
+				if pos.Y*2 < last {
+					return 0 // the flag never moves, so it tells the side for sure
+				}
+				return last
+			}
+			sum += pos.Y
+			count++
+		}
+	}
+	if count > 0 && sum*2 < count*last {
+		return 0
+	}
+	return last
+}
+
+// ListAllLegalMoves lists the legal moves of all own pieces of the view's player.
+func ListAllLegalMoves(view *engine.PlayerView) []engine.Move {
+	player := view.GetPlayer()
+	var legal []engine.Move
+	for _, piece := range player.GetAlivePieces() {
+		if !piece.CanMove() {
+			continue
+		}
+		pos, exists := player.GetPiecePosition(piece)
+		if !exists {
+			continue
+		}
+		moves, err := view.ListLegalMoves(pos)
+		if err == nil {
+			legal = append(legal, moves...)
+		}
+	}
+	return legal
+}
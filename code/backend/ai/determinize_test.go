@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestSampleIsConsistent(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+
+	// Mark one enemy piece as moved, so it can be neither bomb nor flag
+	moved := engine.NewPosition(0, 3)
+	g.Board.GetPieceAt(moved).SetMoved(true)
+
+	determinizer := NewDeterminizer(g.ViewFor(&player1), nil)
+	if len(determinizer.hidden) != 40 || len(determinizer.unknown) != 40 {
+		t.Fatalf("Expected 40 hidden squares and unknown pieces, got %d and %d", len(determinizer.hidden), len(determinizer.unknown))
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		board := determinizer.Sample(rng)
+
+		counts := make(map[byte]int)
+		for square := range uint8(100) {
+			if board[square]&engine.BitOccupied != 0 && board.Color(square) == EnemyColor {
+				counts[board.PieceID(square)]++
+			}
+		}
+		if counts[engine.PieceIDFlag] != 1 || counts[engine.PieceIDBomb] != 6 || counts[engine.PieceIDScout] != 8 {
+			t.Fatalf("Expected a full enemy army, got %v", counts)
+		}
+
+		id := board.PieceID(engine.SquareIndex(moved))
+		if id == engine.PieceIDBomb || id == engine.PieceIDFlag {
+			t.Fatalf("Expected a moved piece not to be sampled as bomb or flag")
+		}
+	}
+}
+
+func TestDeterminizerUsesMemory(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+
+	memory := NewAIMemory()
+	known := engine.NewPosition(4, 3)
+	memory.Remember(known, engine.NewSeenPiece(g.Board.GetPieceAt(known)), 1.0, 0)
+	memory.RecordCapture(engine.NewSeenPiece(engine.NewPiece(models.Scout, &player2)))
+
+	determinizer := NewDeterminizer(g.ViewFor(&player1), memory)
+	if len(determinizer.hidden) != 39 || len(determinizer.unknown) != 38 {
+		t.Errorf("Expected 39 hidden squares and 38 unknown pieces, got %d and %d", len(determinizer.hidden), len(determinizer.unknown))
+	}
+
+	board := determinizer.Sample(rand.New(rand.NewPCG(1, 2)))
+	want, _ := engine.GetPieceIDFromRank(g.Board.GetPieceAt(known).GetRank())
+	if id := board.PieceID(engine.SquareIndex(known)); id != want {
+		t.Errorf("Expected the remembered piece %d on %v, got %d", want, known, id)
+	}
+}
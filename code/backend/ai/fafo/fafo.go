@@ -3,7 +3,6 @@ package fafo
 import (
 	"digital-innovation/stratego/ai"
 	"digital-innovation/stratego/engine"
-	"math/rand/v2"
 )
 
 type FafoAI struct {
@@ -21,20 +20,20 @@ func (ai *FafoAI) PickRandomPiece() *engine.Piece {
 	if len(pieces) == 0 {
 		return nil
 	}
-	random := rand.IntN(len(pieces))
+	random := ai.GetRand().IntN(len(pieces))
 	return pieces[random]
 }
 
-func (ai *FafoAI) MakeMove(board *engine.Board) engine.Move {
-	return ai.FindRandomMove(board)
+func (ai *FafoAI) MakeMove(view *engine.PlayerView) engine.Move {
+	return ai.FindRandomMove(view)
 }
 
 // findRandomMove picks any valid move as last resort
-func (ai *FafoAI) FindRandomMove(board *engine.Board) engine.Move {
+func (ai *FafoAI) FindRandomMove(view *engine.PlayerView) engine.Move {
 	pieces := ai.GetPlayer().GetAlivePieces()
 	shuffled := make([]*engine.Piece, len(pieces))
 	copy(shuffled, pieces)
-	rand.Shuffle(len(shuffled), func(i, j int) {
+	ai.GetRand().Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
@@ -47,12 +46,12 @@ func (ai *FafoAI) FindRandomMove(board *engine.Board) engine.Move {
 			continue
 		}
 
-		moves, err := board.ListMoves(pos)
+		moves, err := view.ListLegalMoves(pos)
 		if err != nil || len(moves) == 0 {
 			continue
 		}
 
-		chosen := moves[rand.IntN(len(moves))]
+		chosen := moves[ai.GetRand().IntN(len(moves))]
 		return engine.NewMove(chosen.GetFrom(), chosen.GetTo(), ai.GetPlayer())
 	}
 
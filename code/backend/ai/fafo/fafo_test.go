@@ -46,14 +46,14 @@ func TestMakeMove(t *testing.T) {
 
 	g := game.QuickStart(controller1, controller2)
 
-	move1 := controller1.MakeMove(g.Board)
+	move1 := controller1.MakeMove(g.ViewFor(&player1))
 	piece := g.Board.GetPieceAt(move1.GetFrom())
 
 	if piece.GetOwner() != &player1 {
 		t.Errorf("Expected piece owner to be Alice, but got %v", piece.GetOwner().GetName())
 	}
 
-	move2 := controller2.MakeMove(g.Board)
+	move2 := controller2.MakeMove(g.ViewFor(&player2))
 	piece2 := g.Board.GetPieceAt(move2.GetFrom())
 
 	if piece2.GetOwner() != &player2 {
@@ -87,13 +87,13 @@ func TestNoMovesLeft(t *testing.T) {
 		t.Errorf("Expected all pieces to be eliminated, but got %d", len(player1.GetAlivePieces()))
 	}
 
-	move1 := controller1.MakeMove(g.Board)
+	move1 := controller1.MakeMove(g.ViewFor(&player1))
 
 	if !move1.IsEmpty() {
 		t.Errorf("Expected no move to be made, but got %v", move1)
 	}
 
-	move2 := controller2.MakeMove(g.Board)
+	move2 := controller2.MakeMove(g.ViewFor(&player2))
 	piece2 := g.Board.GetPieceAt(move2.GetFrom())
 
 	if piece2 == nil {
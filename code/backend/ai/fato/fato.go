@@ -6,7 +6,6 @@ import (
 	"digital-innovation/stratego/engine"
 	"digital-innovation/stratego/models"
 	"math"
-	"math/rand/v2"
 )
 
 // TODO: choose aggression on frontend
@@ -52,32 +51,32 @@ func (ai *FatoAI) GetAggression() float64 {
 	return ai.aggression
 }
 
-func (ai *FatoAI) MakeMove(board *engine.Board) engine.Move {
+func (ai *FatoAI) MakeMove(view *engine.PlayerView) engine.Move {
 	// Not so random huh? :-)
 
 	// 1. Try to attack a known enemy piece
-	if move, found := ai.findAttackMove(board); found {
+	if move, found := ai.findAttackMove(view); found {
 		return move
 	}
 
 	// 2. Try to explore toward enemy territory
-	if move, found := ai.findExplorationMove(board); found {
+	if move, found := ai.findExplorationMove(view); found {
 		return move
 	}
 
 	// 3. Fallback: random valid move
-	return ai.FindRandomMove(board)
+	return ai.FindRandomMove(view)
 }
 
 // findAttackMove looks for moves that attack known/visible enemy pieces
-func (ai *FatoAI) findAttackMove(board *engine.Board) (engine.Move, bool) {
+func (ai *FatoAI) findAttackMove(view *engine.PlayerView) (engine.Move, bool) {
 	memory := ai.GetMemory()
 	pieces := ai.GetPlayer().GetAlivePieces()
 
 	// Shuffle pieces to add variety
 	shuffled := make([]*engine.Piece, len(pieces))
 	copy(shuffled, pieces)
-	rand.Shuffle(len(shuffled), func(i, j int) {
+	ai.GetRand().Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
@@ -93,14 +92,14 @@ func (ai *FatoAI) findAttackMove(board *engine.Board) (engine.Move, bool) {
 			continue
 		}
 
-		moves, err := board.ListMoves(pos)
+		moves, err := view.ListLegalMoves(pos)
 		if err != nil {
 			continue
 		}
 
 		for _, move := range moves {
-			target := board.GetPieceAt(move.GetTo())
-			if target != nil && target.GetOwner() != ai.GetPlayer() {
+			target := view.GetPieceAt(move.GetTo())
+			if target.IsEnemy() {
 				score := ai.evaluateAttack(piece, target, move.GetTo(), memory)
 
 				// Aggression determines minimum acceptable score
@@ -125,25 +124,25 @@ func (ai *FatoAI) findAttackMove(board *engine.Board) (engine.Move, bool) {
 }
 
 // evaluateAttack scores an attack opportunity
-func (ai *FatoAI) evaluateAttack(attacker *engine.Piece, target *engine.Piece, targetPos engine.Position, memory *ai.AIMemory) float64 {
+func (ai *FatoAI) evaluateAttack(attacker *engine.Piece, target engine.ViewPiece, targetPos engine.Position, memory *ai.AIMemory) float64 {
 	score := 0.0
 
 	// Check memory for target
 	remembered := memory.Recall(targetPos)
 
 	switch {
-	case target.IsRevealed():
-		rankDiff := float64(attacker.GetRank() - target.GetRank())
+	case target.Knowledge == engine.PieceRevealed:
+		rankDiff := float64(attacker.GetRank() - target.Type.GetRank())
 		score = rankDiff * 10
 
-		if target.GetType().GetName() == "Flag" {
+		if target.Type.GetName() == "Flag" {
 			score += 10000
 		}
-		if target.GetType().GetName() == "Bomb" && attacker.GetType().GetName() != "Miner" {
+		if target.Type.GetName() == "Bomb" && attacker.GetType().GetName() != "Miner" {
 			score -= 1000
 		}
 	case remembered != nil && remembered.Confidence > 0.5:
-		rankDiff := float64(attacker.GetRank() - remembered.Piece.GetRank())
+		rankDiff := float64(attacker.GetRank() - remembered.Piece.Type.GetRank())
 		score = rankDiff * 10 * remembered.Confidence
 
 		if remembered.Confidence < 0.8 {
@@ -158,22 +157,22 @@ func (ai *FatoAI) evaluateAttack(attacker *engine.Piece, target *engine.Piece, t
 		case attacker.GetRank() >= 3:
 			score = 5.0
 		}
-		score += rand.Float64()*10 - 5
+		score += ai.GetRand().Float64()*10 - 5
 	}
 	return score
 }
 
 // findExplorationMove moves toward enemy side
-func (ai *FatoAI) findExplorationMove(board *engine.Board) (engine.Move, bool) {
+func (ai *FatoAI) findExplorationMove(view *engine.PlayerView) (engine.Move, bool) {
 	enemyY := 0
 	if ai.GetPlayer().GetID() == 1 {
-		enemyY = 9
+		enemyY = view.GetHeight() - 1
 	}
 
 	pieces := ai.GetPlayer().GetAlivePieces()
 	shuffled := make([]*engine.Piece, len(pieces))
 	copy(shuffled, pieces)
-	rand.Shuffle(len(shuffled), func(i, j int) {
+	ai.GetRand().Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
@@ -186,7 +185,7 @@ func (ai *FatoAI) findExplorationMove(board *engine.Board) (engine.Move, bool) {
 			continue
 		}
 
-		moves, err := board.ListMoves(pos)
+		moves, err := view.ListLegalMoves(pos)
 		if err != nil {
 			continue
 		}
@@ -194,7 +193,7 @@ func (ai *FatoAI) findExplorationMove(board *engine.Board) (engine.Move, bool) {
 		var bestMove *engine.Move
 		bestDist := 100
 		for _, move := range moves {
-			if board.GetPieceAt(move.GetTo()) == nil {
+			if view.GetPieceAt(move.GetTo()).Knowledge == engine.SquareEmpty {
 				dist := int(math.Abs(float64(move.GetTo().Y - enemyY)))
 				if dist < bestDist {
 					bestDist = dist
@@ -212,22 +211,20 @@ func (ai *FatoAI) findExplorationMove(board *engine.Board) (engine.Move, bool) {
 
 // AnalyzeMove observes opponent moves and updates memory
 // Overrides BaseAI to add scout detection
-func (ai *FatoAI) AnalyzeMove(opponentMove engine.Move, opponent *engine.Player, round int) {
+func (ai *FatoAI) AnalyzeMove(opponentMove engine.ViewMove, round int) {
 	memory := ai.GetMemory()
-	from := opponentMove.GetFrom()
-	to := opponentMove.GetTo()
+	from := opponentMove.From
+	to := opponentMove.To
 
 	// First, apply default memory updates (move tracking)
-	if memory.Recall(from) != nil {
-		memory.MovePiece(from, to)
-	}
+	memory.MovePiece(from, to)
 
 	// Detect scout moves (moving >1 square in straight line)
 	deltaX := int(math.Abs(float64(from.X - to.X)))
 	deltaY := int(math.Abs(float64(from.Y - to.Y)))
 
 	if deltaX > 1 || deltaY > 1 {
-		scoutPiece := engine.NewPiece(models.Scout, opponent)
-		memory.Remember(to, scoutPiece, 1.0, round)
+		scout := models.Scout
+		memory.Remember(to, engine.SeenPiece{OwnerID: opponentMove.PlayerID, Type: &scout, Alive: true}, 1.0, round)
 	}
 }
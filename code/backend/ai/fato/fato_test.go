@@ -33,7 +33,7 @@ func TestIsPieceMemorized(t *testing.T) {
 
 	// Remember a piece
 	piece := engine.NewPiece(models.Scout, &player)
-	ai.GetMemory().Remember(position, piece, 1.0, 1)
+	ai.GetMemory().Remember(position, engine.NewSeenPiece(piece), 1.0, 1)
 
 	// Should now be memorized
 	if ai.GetMemory().Recall(position) == nil {
@@ -52,7 +52,7 @@ func TestAnalyzeMove(t *testing.T) {
 
 	// Normal move (1 square) - should NOT be remembered as scout
 	normalMove := engine.NewMove(engine.NewPosition(1, 1), engine.NewPosition(1, 2), &humanPlayer)
-	ai.AnalyzeMove(normalMove, &humanPlayer, 1)
+	ai.AnalyzeMove(engine.NewViewMove(normalMove), 1)
 
 	if ai.GetMemory().Recall(normalMove.GetTo()) != nil {
 		t.Errorf("Expected piece to not be remembered after normal move")
@@ -60,15 +60,15 @@ func TestAnalyzeMove(t *testing.T) {
 
 	// Scout move (2+ squares) - should be remembered as scout
 	scoutMove := engine.NewMove(engine.NewPosition(1, 1), engine.NewPosition(1, 3), &humanPlayer)
-	ai.AnalyzeMove(scoutMove, &humanPlayer, 1)
+	ai.AnalyzeMove(engine.NewViewMove(scoutMove), 1)
 
 	remembered := ai.GetMemory().Recall(scoutMove.GetTo())
 	if remembered == nil {
 		t.Errorf("Expected piece to be remembered after scout move")
 	}
 
-	if remembered != nil && remembered.Piece.GetType().GetName() != "Scout" {
-		t.Errorf("Expected remembered piece to be Scout, got: %s", remembered.Piece.GetType().GetName())
+	if remembered != nil && remembered.Piece.Type.GetName() != "Scout" {
+		t.Errorf("Expected remembered piece to be Scout, got: %s", remembered.Piece.Type.GetName())
 	}
 
 	if remembered != nil && remembered.Confidence != 1.0 {
@@ -84,7 +84,7 @@ func TestMemoryUpdatesOnMove(t *testing.T) {
 	// Remember a piece at position (2, 2)
 	pos1 := engine.NewPosition(2, 2)
 	piece := engine.NewPiece(models.Captain, &humanPlayer)
-	ai.GetMemory().Remember(pos1, piece, 0.9, 1)
+	ai.GetMemory().Remember(pos1, engine.NewSeenPiece(piece), 0.9, 1)
 
 	// Verify it's there
 	if ai.GetMemory().Recall(pos1) == nil {
@@ -94,7 +94,7 @@ func TestMemoryUpdatesOnMove(t *testing.T) {
 	// Simulate opponent moving that piece from (2,2) to (2,3)
 	pos2 := engine.NewPosition(2, 3)
 	move := engine.NewMove(pos1, pos2, &humanPlayer)
-	ai.AnalyzeMove(move, &humanPlayer, 2)
+	ai.AnalyzeMove(engine.NewViewMove(move), 2)
 
 	// Memory should have moved from pos1 to pos2
 	if ai.GetMemory().Recall(pos1) != nil {
@@ -4,17 +4,41 @@ import (
 	"digital-innovation/stratego/ai"
 	"digital-innovation/stratego/ai/fafo"
 	"digital-innovation/stratego/ai/fato"
+	"digital-innovation/stratego/ai/heuristic"
+	"digital-innovation/stratego/ai/mcts"
+	"digital-innovation/stratego/ai/minimax"
 	"digital-innovation/stratego/engine"
 	"digital-innovation/stratego/models"
 )
 
+// CreateAI creates the AI with the given name, it panics on unknown names.
+// The searching AIs stop after a time budget per move.
 func CreateAI(ai string, player *engine.Player) ai.AI {
 	switch ai {
 	case models.Fafo:
 		return fafo.NewFafoAI(player, false)
 	case models.Fato:
 		return fato.NewFatoAI(player, true)
+	case models.Heuristic:
+		return heuristic.NewHeuristicAI(player)
+	case models.Minimax:
+		return minimax.NewMinimaxAI(player)
+	case models.Mcts:
+		return mcts.NewMctsAI(player)
 	default:
 		panic("I don't know that AI! " + ai)
 	}
 }
+
+// CreateReplayableAI creates the AI with the given name like CreateAI, but the searching AIs do not use the clock,
+// so the moves only depend on the seed of the AI and a game can be replayed exactly.
+func CreateReplayableAI(ai string, player *engine.Player) ai.AI {
+	switch ai {
+	case models.Minimax:
+		return minimax.NewMinimaxAIWithConfig(player, minimax.ReplayConfig())
+	case models.Mcts:
+		return mcts.NewMctsAIWithConfig(player, mcts.ReplayConfig())
+	default:
+		return CreateAI(ai, player)
+	}
+}
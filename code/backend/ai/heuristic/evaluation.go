@@ -0,0 +1,226 @@
+package heuristic
+
+import (
+	"digital-innovation/stratego/ai"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+)
+
+// averageValue is the average strategic value of a piece in a full army,
+// used when the AI knows nothing about a defender.
+const averageValue = 5.5
+
+// minConfidence is the confidence from which a remembered piece is trusted.
+const minConfidence = 0.5
+
+// MoveContext is everything an evaluation term can look at when scoring a move.
+type MoveContext struct {
+	View   *engine.PlayerView
+	Memory *ai.AIMemory
+	Move   engine.Move
+	Piece  *engine.Piece    // the own piece that moves
+	Target engine.ViewPiece // what is known about the destination square
+	Home   int              // row of the own back line, 0 or the last row
+}
+
+// Term scores one aspect of a move. Positive scores are good for the AI.
+type Term func(ctx *MoveContext) float64
+
+// WeightedTerm is a term of an evaluation together with its weight.
+type WeightedTerm struct {
+	Name   string
+	Weight float64
+	Term   Term
+}
+
+// Evaluation scores a move as the weighted sum of its terms.
+// Terms can be added, removed or reweighted to try out different playing styles.
+type Evaluation []WeightedTerm
+
+// Evaluate returns the weighted sum of all terms for the move.
+func (e Evaluation) Evaluate(ctx *MoveContext) float64 {
+	score := 0.0
+	for _, term := range e {
+		if term.Weight != 0 {
+			score += term.Weight * term.Term(ctx)
+		}
+	}
+	return score
+}
+
+// DefaultEvaluation returns the evaluation used by NewHeuristicAI.
+func DefaultEvaluation() Evaluation {
+	return Evaluation{
+		{Name: "material", Weight: 1.0, Term: Material},
+		{Name: "flag safety", Weight: 2.0, Term: FlagSafety},
+		{Name: "mobility", Weight: 0.3, Term: Mobility},
+		{Name: "information gain", Weight: 1.5, Term: InformationGain},
+		{Name: "miner preservation", Weight: 1.0, Term: MinerPreservation},
+		{Name: "advance", Weight: 0.2, Term: Advance},
+	}
+}
+
+// Material estimates the change in strategic value of the move, using PieceType.GetStrategicValue.
+// Attacks on known pieces are predicted exactly; attacks on unknown pieces assume an average defender
+// and, if the defender never moved, the risk of hitting a bomb.
+func Material(ctx *MoveContext) float64 {
+	if !ctx.Target.IsEnemy() {
+		return 0
+	}
+
+	attackerValue := float64(ctx.Piece.GetStrategicValue())
+	if defender, confidence := knownType(ctx); defender != nil {
+		return confidence * materialOutcome(ctx.Piece.GetType(), defender, ctx.View.GetRules())
+	}
+
+	bombRisk := 0.0
+	if !ctx.Target.Moved && ctx.Piece.GetRank() != models.Miner.GetRank() {
+		rules := ctx.View.GetRules()
+		bombRisk = float64(rules.Count(models.Bomb)) / float64(rules.ArmySize())
+	}
+	win := 0.5 * (1 - bombRisk)
+	return win*averageValue - (1-win)*attackerValue
+}
+
+// FlagSafety rewards keeping pieces next to the own flag and attacking enemy pieces that come close to it.
+func FlagSafety(ctx *MoveContext) float64 {
+	flag, ok := ownFlag(ctx.View)
+	if !ok {
+		return 0
+	}
+
+	score := 0.0
+	from, to := ctx.Move.GetFrom(), ctx.Move.GetTo()
+	if distance(from, flag) == 1 && distance(to, flag) > 1 {
+		score -= 1 // leaves a guard post
+	}
+	if distance(from, flag) > 1 && distance(to, flag) == 1 {
+		score += 0.5
+	}
+	if ctx.Target.IsEnemy() && distance(to, flag) <= 2 {
+		score += 2 // removes a threat to the flag
+	}
+	return score
+}
+
+// Mobility rewards moves that give the piece more free squares to move to.
+func Mobility(ctx *MoveContext) float64 {
+	return float64(freeNeighbours(ctx.View, ctx.Move.GetTo(), ctx.Move.GetFrom())-
+		freeNeighbours(ctx.View, ctx.Move.GetFrom(), ctx.Move.GetFrom())) / 4
+}
+
+// InformationGain rewards attacks that reveal unknown pieces, more so when a cheap piece takes the risk.
+func InformationGain(ctx *MoveContext) float64 {
+	if !ctx.Target.IsEnemy() {
+		return 0
+	}
+
+	gain := 0.0
+	switch ctx.Target.Knowledge {
+	case engine.PieceUnknown:
+		gain = 1
+	case engine.PieceMoved:
+		gain = 0.5
+	}
+	if entry := ctx.Memory.Recall(ctx.Move.GetTo()); entry != nil && entry.Confidence >= minConfidence {
+		gain *= 1 - entry.Confidence
+	}
+	return gain * (1 - float64(ctx.Piece.GetStrategicValue())/10)
+}
+
+// MinerPreservation keeps miners out of fights they do not need, as they are needed to clear bombs.
+// The fewer miners are left, the higher the penalty; attacking a known bomb is rewarded instead.
+func MinerPreservation(ctx *MoveContext) float64 {
+	if ctx.Piece.GetRank() != models.Miner.GetRank() || !ctx.Target.IsEnemy() {
+		return 0
+	}
+	if defender, _ := knownType(ctx); defender != nil && defender.GetRank() == models.Bomb.GetRank() {
+		return 2
+	}
+
+	miners := 0
+	for _, piece := range ctx.View.GetPlayer().GetAlivePieces() {
+		if piece.GetRank() == models.Miner.GetRank() {
+			miners++
+		}
+	}
+	return -2 / float64(miners)
+}
+
+// Advance rewards moving towards the enemy side of the board, so the AI keeps making progress.
+func Advance(ctx *MoveContext) float64 {
+	from, to := ctx.Move.GetFrom(), ctx.Move.GetTo()
+	return float64(abs(to.Y-ctx.Home) - abs(from.Y-ctx.Home))
+}
+
+// knownType returns the type of the defender if it is revealed or remembered, with the confidence of that knowledge.
+func knownType(ctx *MoveContext) (*models.PieceType, float64) {
+	if ctx.Target.Knowledge == engine.PieceRevealed {
+		return ctx.Target.Type, 1
+	}
+	entry := ctx.Memory.Recall(ctx.Move.GetTo())
+	if entry != nil && entry.Confidence >= minConfidence && entry.Piece.OwnerID != ctx.View.GetPlayer().GetID() {
+		return entry.Piece.Type, entry.Confidence
+	}
+	if rank, probability := ctx.Memory.MostLikelyRank(ctx.Move.GetTo()); probability >= minConfidence {
+		for i := range ai.ArmyTypes {
+			if ai.ArmyTypes[i].GetRank() == rank {
+				return &ai.ArmyTypes[i], probability
+			}
+		}
+	}
+	return nil, 0
+}
+
+// materialOutcome is the change in material when attacker attacks defender in a game played with the rule set.
+func materialOutcome(attacker, defender *models.PieceType, rules models.RuleSet) float64 {
+	attackerValue, defenderValue := float64(attacker.GetStrategicValue()), float64(defender.GetStrategicValue())
+	switch engine.PredictCombatWithRules(attacker, defender, rules) {
+	case engine.CombatFlagCaptured:
+		return 1000
+	case engine.CombatAttackerWon:
+		return defenderValue
+	case engine.CombatDefenderWon:
+		return -attackerValue
+	default:
+		return defenderValue - attackerValue
+	}
+}
+
+// ownFlag finds the own flag on the board.
+func ownFlag(view *engine.PlayerView) (engine.Position, bool) {
+	player := view.GetPlayer()
+	for _, piece := range player.GetAlivePieces() {
+		if piece.GetRank() == models.Flag.GetRank() {
+			return player.GetPiecePosition(piece)
+		}
+	}
+	return engine.Position{}, false
+}
+
+// freeNeighbours counts the orthogonal neighbours of pos a piece could move to,
+// treating the square the piece comes from as free.
+func freeNeighbours(view *engine.PlayerView, pos, vacated engine.Position) int {
+	count := 0
+	for _, d := range []engine.Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+		n := engine.NewPosition(pos.X+d.X, pos.Y+d.Y)
+		if !view.IsInBounds(n) || view.IsLake(n) {
+			continue
+		}
+		if n == vacated || view.GetPieceAt(n).Knowledge != engine.PieceOwn {
+			count++
+		}
+	}
+	return count
+}
+
+func distance(a, b engine.Position) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
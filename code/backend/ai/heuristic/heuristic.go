@@ -0,0 +1,73 @@
+package heuristic
+
+import (
+	"digital-innovation/stratego/ai"
+	"digital-innovation/stratego/engine"
+)
+
+// HeuristicAI scores every legal move with an Evaluation and plays the best one.
+// It remembers revealed enemy pieces in its AIMemory to judge later attacks.
+type HeuristicAI struct {
+	ai.BaseAI
+	evaluation Evaluation
+}
+
+func NewHeuristicAI(player *engine.Player) *HeuristicAI {
+	return NewHeuristicAIWithEvaluation(player, DefaultEvaluation())
+}
+
+func NewHeuristicAIWithEvaluation(player *engine.Player, evaluation Evaluation) *HeuristicAI {
+	return &HeuristicAI{
+		BaseAI:     *ai.NewBaseAI(player, true),
+		evaluation: evaluation,
+	}
+}
+
+// GetEvaluation returns the evaluation used to score moves.
+func (h *HeuristicAI) GetEvaluation() Evaluation {
+	return h.evaluation
+}
+
+// SetEvaluation replaces the evaluation used to score moves.
+func (h *HeuristicAI) SetEvaluation(evaluation Evaluation) {
+	h.evaluation = evaluation
+}
+
+func (h *HeuristicAI) MakeMove(view *engine.PlayerView) engine.Move {
+	player := h.GetPlayer()
+	h.GetMemory().SyncView(view)
+	ctx := &MoveContext{View: view, Memory: h.GetMemory(), Home: ai.HomeRow(view)}
+
+	var best engine.Move
+	bestScore := 0.0
+	for _, piece := range player.GetAlivePieces() {
+		if !piece.CanMove() {
+			continue
+		}
+		pos, exists := player.GetPiecePosition(piece)
+		if !exists {
+			continue
+		}
+
+		moves, err := view.ListLegalMoves(pos)
+		if err != nil {
+			continue
+		}
+
+		for _, move := range moves {
+			ctx.Move = move
+			ctx.Piece = piece
+			ctx.Target = view.GetPieceAt(move.GetTo())
+
+			// A little noise breaks ties, so equal moves are not always played in the same order
+			score := h.evaluation.Evaluate(ctx) + h.GetRand().Float64()*0.01
+			if best.IsEmpty() || score > bestScore {
+				best = engine.NewMove(move.GetFrom(), move.GetTo(), player)
+				bestScore = score
+			}
+		}
+	}
+
+	// An empty move signals that no valid moves are left
+	return best
+}
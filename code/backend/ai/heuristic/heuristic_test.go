@@ -0,0 +1,81 @@
+package heuristic_test
+
+import (
+	"digital-innovation/stratego/ai/heuristic"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"testing"
+)
+
+func TestMakeMove(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := heuristic.NewHeuristicAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	controller2 := heuristic.NewHeuristicAI(&player2)
+
+	g := game.QuickStart(controller1, controller2)
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if err := g.ValidateMove(&move); err != nil {
+		t.Errorf("Expected a valid move, got: %v", err)
+	}
+}
+
+func TestMakeMoveAttacksRevealedWeakerPiece(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := heuristic.NewHeuristicAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	controller2 := heuristic.NewHeuristicAI(&player2)
+	g := game.NewGame(controller1, controller2)
+
+	weak := engine.NewPiece(models.Sergeant, &player2)
+	weak.Reveal()
+	g.Board.SetPieceAt(engine.NewPosition(4, 9), engine.NewPiece(models.Flag, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 5), engine.NewPiece(models.Colonel, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 4), weak)
+	g.Board.SetPieceAt(engine.NewPosition(0, 0), engine.NewPiece(models.Flag, &player2))
+	g.InitializePieces()
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if move.GetFrom() != engine.NewPosition(5, 5) || move.GetTo() != engine.NewPosition(5, 4) {
+		t.Errorf("Expected the colonel to attack the revealed sergeant, got %v", move)
+	}
+}
+
+func TestCustomEvaluation(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	retreat := heuristic.Evaluation{{
+		Name:   "retreat",
+		Weight: 1,
+		Term: func(ctx *heuristic.MoveContext) float64 {
+			return float64(ctx.Move.GetTo().Y - ctx.Move.GetFrom().Y)
+		},
+	}}
+	controller1 := heuristic.NewHeuristicAIWithEvaluation(&player1, retreat)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.NewGame(controller1, engine.NewHumanPlayerController(&player2))
+
+	g.Board.SetPieceAt(engine.NewPosition(5, 5), engine.NewPiece(models.Captain, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(0, 0), engine.NewPiece(models.Flag, &player2))
+	g.InitializePieces()
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if move.GetTo() != engine.NewPosition(5, 6) {
+		t.Errorf("Expected the custom evaluation to pick the retreat to (5,6), got %v", move)
+	}
+}
+
+func TestNoMovesLeft(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := heuristic.NewHeuristicAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.NewGame(controller1, engine.NewHumanPlayerController(&player2))
+
+	g.Board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Flag, &player1))
+	g.InitializePieces()
+
+	if move := controller1.MakeMove(g.ViewFor(&player1)); !move.IsEmpty() {
+		t.Errorf("Expected an empty move when no piece can move, got %v", move)
+	}
+}
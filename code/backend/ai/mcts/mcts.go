@@ -0,0 +1,142 @@
+package mcts
+
+import (
+	"digital-innovation/stratego/ai"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Config controls how much the MctsAI searches per move. The search stops when either the iterations or the
+// time budget run out; zero disables that limit, but at least one of the two has to be set.
+// Without a time budget the search only depends on the AI's seed, so games can be replayed exactly.
+type Config struct {
+	Iterations   int           // iterations per move, split over the workers
+	TimeBudget   time.Duration // wall-clock time per move
+	Workers      int           // goroutines searching independent trees, merged at the root
+	PlayoutDepth int           // moves per random playout before the material decides
+	Exploration  float64       // UCB exploration constant
+}
+
+// DefaultConfig returns the configuration used by NewMctsAI.
+func DefaultConfig() Config {
+	return Config{
+		Iterations:   0,
+		TimeBudget:   50 * time.Millisecond,
+		Workers:      runtime.GOMAXPROCS(0),
+		PlayoutDepth: 100,
+		Exploration:  0.7,
+	}
+}
+
+// ReplayConfig returns a configuration with a fixed number of iterations on a single worker instead of the
+// time budget, so a game can be replayed from the seed of the AI on any machine.
+// It searches about as much per move as DefaultConfig on one core.
+func ReplayConfig() Config {
+	config := DefaultConfig()
+	config.Iterations = 400
+	config.TimeBudget = 0
+	config.Workers = 1
+	return config
+}
+
+// MctsAI runs information set Monte Carlo tree search: every iteration samples a determinization of the hidden
+// enemy pieces, walks a tree shared by all determinizations and finishes with a FAFO-style random playout.
+// With several workers each searches its own tree and the root statistics are merged.
+type MctsAI struct {
+	ai.BaseAI
+	config Config
+}
+
+func NewMctsAI(player *engine.Player) *MctsAI {
+	return NewMctsAIWithConfig(player, DefaultConfig())
+}
+
+func NewMctsAIWithConfig(player *engine.Player, config Config) *MctsAI {
+	return &MctsAI{
+		BaseAI: *ai.NewBaseAI(player, true),
+		config: config,
+	}
+}
+
+// GetConfig returns the search configuration.
+func (m *MctsAI) GetConfig() Config {
+	return m.config
+}
+
+func (m *MctsAI) MakeMove(view *engine.PlayerView) engine.Move {
+	player := m.GetPlayer()
+	legal := ai.ListAllLegalMoves(view)
+	if len(legal) == 0 {
+		return engine.Move{} // no valid moves left
+	}
+	if len(legal) == 1 {
+		return legal[0]
+	}
+
+	rootMoves := make([]engine.CompactMove, len(legal))
+	for i, move := range legal {
+		rootMoves[i] = engine.NewCompactMove(move)
+	}
+
+	m.GetMemory().SyncView(view)
+	visits := m.search(ai.NewDeterminizer(view, m.GetMemory()), rootMoves, view.GetRules())
+
+	choice := m.GetRand().IntN(len(legal))
+	for i := range legal {
+		if visits[i] > visits[choice] {
+			choice = i
+		}
+	}
+	return engine.NewMove(legal[choice].GetFrom(), legal[choice].GetTo(), player)
+}
+
+// search runs the workers and returns the merged visit counts of the root moves.
+func (m *MctsAI) search(determinizer *ai.Determinizer, rootMoves []engine.CompactMove, rules models.RuleSet) []int {
+	config := m.config
+	workers := max(config.Workers, 1)
+	var deadline time.Time
+	if config.TimeBudget > 0 {
+		deadline = time.Now().Add(config.TimeBudget)
+	}
+	iterations := -1 // unlimited
+	if config.Iterations > 0 {
+		iterations = max(config.Iterations/workers, 1)
+	} else if deadline.IsZero() {
+		iterations = 1 // no limit at all, do the least possible work
+	}
+
+	trees := make([]*tree, workers)
+	for i := range trees {
+		rng := rand.New(rand.NewPCG(m.GetRand().Uint64(), m.GetRand().Uint64()))
+		trees[i] = newTree(determinizer, rootMoves, config, rules, rng)
+	}
+
+	var wg sync.WaitGroup
+	for _, t := range trees {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; iterations < 0 || i < iterations; i++ {
+				if !deadline.IsZero() && time.Now().After(deadline) {
+					break
+				}
+				t.iterate()
+			}
+		}()
+	}
+	wg.Wait()
+
+	visits := make([]int, len(rootMoves))
+	for _, t := range trees {
+		for i, move := range rootMoves {
+			if c := t.root.child(move); c != nil {
+				visits[i] += c.visits
+			}
+		}
+	}
+	return visits
+}
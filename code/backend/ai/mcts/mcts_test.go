@@ -0,0 +1,62 @@
+package mcts_test
+
+import (
+	"digital-innovation/stratego/ai/mcts"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"testing"
+	"time"
+)
+
+func TestMakeMove(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := mcts.NewMctsAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	controller2 := mcts.NewMctsAI(&player2)
+
+	g := game.QuickStart(controller1, controller2)
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if err := g.ValidateMove(&move); err != nil {
+		t.Errorf("Expected a valid move, got: %v", err)
+	}
+}
+
+func TestMakeMoveCapturesRevealedFlag(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	config := mcts.Config{Iterations: 2000, Workers: 2, PlayoutDepth: 50, Exploration: 0.7}
+	controller1 := mcts.NewMctsAIWithConfig(&player1, config)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.NewGame(controller1, engine.NewHumanPlayerController(&player2))
+
+	flag := engine.NewPiece(models.Flag, &player2)
+	flag.Reveal()
+	g.Board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Flag, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 5), engine.NewPiece(models.Sergeant, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 4), flag)
+	g.Board.SetPieceAt(engine.NewPosition(9, 0), engine.NewPiece(models.Marshal, &player2))
+	g.InitializePieces()
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if move.GetTo() != engine.NewPosition(5, 4) {
+		t.Errorf("Expected the sergeant to capture the flag, got %v", move)
+	}
+}
+
+func TestMakeMoveRespectsTimeBudget(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	config := mcts.Config{TimeBudget: 20 * time.Millisecond, Workers: 4, PlayoutDepth: 100, Exploration: 0.7}
+	controller1 := mcts.NewMctsAIWithConfig(&player1, config)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(controller1, engine.NewHumanPlayerController(&player2))
+
+	start := time.Now()
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the move within the time budget, took %v", elapsed)
+	}
+	if err := g.ValidateMove(&move); err != nil {
+		t.Errorf("Expected a valid move, got: %v", err)
+	}
+}
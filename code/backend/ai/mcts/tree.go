@@ -0,0 +1,172 @@
+package mcts
+
+import (
+	"digital-innovation/stratego/ai"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"math"
+	"math/rand/v2"
+)
+
+// pieceValues maps piece IDs to their strategic value, used to score playouts that did not end the game.
+var pieceValues = func() [16]float64 {
+	var values [16]float64
+	for _, pieceType := range ai.ArmyTypes {
+		id, _ := engine.GetPieceIDFromRank(pieceType.GetRank())
+		values[id] = float64(pieceType.GetStrategicValue())
+	}
+	return values
+}()
+
+// node is a node of the information set tree. Its statistics are shared by all determinizations
+// in which its move is legal.
+type node struct {
+	move     engine.CompactMove
+	color    byte // color that made the move leading to this node
+	parent   *node
+	children []*node
+	visits   int
+	avail    int     // how often the node was available for selection
+	wins     float64 // summed rewards for color
+}
+
+func (n *node) child(move engine.CompactMove) *node {
+	for _, c := range n.children {
+		if c.move == move {
+			return c
+		}
+	}
+	return nil
+}
+
+// tree runs single-observer ISMCTS iterations for one worker. It is not safe for concurrent use;
+// root-parallel search gives every worker its own tree and random source.
+type tree struct {
+	root         *node
+	rootMoves    []engine.CompactMove
+	determinizer *ai.Determinizer
+	config       Config
+	rules        models.RuleSet // combat on the sampled boards is resolved with the rules of the game
+	rng          *rand.Rand
+	moves        []engine.CompactMove // move buffer for selection
+	playout      []engine.CompactMove // move buffer for playouts
+	untried      []engine.CompactMove
+	legal        []*node
+}
+
+func newTree(determinizer *ai.Determinizer, rootMoves []engine.CompactMove, config Config, rules models.RuleSet, rng *rand.Rand) *tree {
+	return &tree{
+		root:         &node{color: ai.EnemyColor},
+		rootMoves:    rootMoves,
+		determinizer: determinizer,
+		config:       config,
+		rules:        rules,
+		rng:          rng,
+		moves:        make([]engine.CompactMove, 0, 128),
+		playout:      make([]engine.CompactMove, 0, 128),
+	}
+}
+
+// iterate runs one iteration: determinize, select, expand, play out and back-propagate.
+func (t *tree) iterate() {
+	board := t.determinizer.Sample(t.rng)
+	current := t.root
+	color := ai.OwnColor
+	winner := -1
+
+	for {
+		// The root only considers the moves the rules allow; deeper down the determinized board decides
+		var moves []engine.CompactMove
+		if current == t.root {
+			moves = t.rootMoves
+		} else {
+			moves = board.GenerateMoves(color, t.moves[:0])
+			t.moves = moves
+		}
+		if len(moves) == 0 {
+			winner = int(1 - color)
+			break
+		}
+
+		t.untried, t.legal = t.untried[:0], t.legal[:0]
+		for _, move := range moves {
+			if c := current.child(move); c != nil {
+				t.legal = append(t.legal, c)
+			} else {
+				t.untried = append(t.untried, move)
+			}
+		}
+
+		var next *node
+		if len(t.untried) > 0 {
+			// Expand one untried move
+			next = &node{move: t.untried[t.rng.IntN(len(t.untried))], color: color, parent: current}
+			current.children = append(current.children, next)
+		} else {
+			next = t.selectChild()
+		}
+		for _, c := range t.legal {
+			c.avail++
+		}
+		if next.avail == 0 {
+			next.avail = 1
+		}
+
+		current = next
+		outcome := board.ApplyWithRules(current.move, t.rules)
+		if outcome == engine.CombatFlagCaptured {
+			winner = int(color)
+			break
+		}
+		color = 1 - color
+		if len(t.untried) > 0 {
+			winner = board.RandomPlayoutWithRules(t.rng, color, t.config.PlayoutDepth, t.playout, t.rules)
+			break
+		}
+	}
+
+	reward := float64(0)
+	switch winner {
+	case int(ai.OwnColor):
+		reward = 1
+	case -1:
+		reward = materialShare(&board)
+	}
+	for n := current; n != nil; n = n.parent {
+		n.visits++
+		if n.color == ai.OwnColor {
+			n.wins += reward
+		} else {
+			n.wins += 1 - reward
+		}
+	}
+}
+
+// selectChild picks the legal child with the highest UCB score, using the availability count
+// instead of the parent visits so rarely legal moves are not explored too little.
+func (t *tree) selectChild() *node {
+	var best *node
+	bestScore := math.Inf(-1)
+	for _, c := range t.legal {
+		score := c.wins/float64(c.visits) + t.config.Exploration*math.Sqrt(math.Log(float64(c.avail))/float64(c.visits))
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+// materialShare returns the own share of the strategic value left on the board, between 0 and 1.
+func materialShare(board *engine.CompactBoard) float64 {
+	var material [2]float64
+	for square := range uint8(100) {
+		if board[square]&engine.BitOccupied != 0 {
+			material[board.Color(square)] += pieceValues[board.PieceID(square)]
+		}
+	}
+	total := material[ai.OwnColor] + material[ai.EnemyColor]
+	if total == 0 {
+		return 0.5
+	}
+	return material[ai.OwnColor] / total
+}
@@ -2,25 +2,40 @@ package ai
 
 import (
 	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
 	"sync"
 )
 
 type MemoryEntry struct {
-	Piece      *engine.Piece
+	Piece      engine.SeenPiece
 	Confidence float64 // how sure are we? 1.0 = revealed, <1.0 = guess
 	LastSeen   int
 }
 
 type AIMemory struct {
-	field [10][10]*MemoryEntry
-	mutex sync.RWMutex
+	field     [models.MaxBoardSize][models.MaxBoardSize]*MemoryEntry
+	captured  map[byte]int   // enemy pieces seen being captured, by rank
+	rules     models.RuleSet // decides the enemy army, taken from the view in SyncView
+	owner     *engine.Player
+	beliefs   [models.MaxBoardSize][models.MaxBoardSize]*belief // rank distributions of the enemy pieces, see beliefs.go
+	posterior bool                                              // whether the belief distributions are up to date
+	mutex     sync.RWMutex
 }
 
 func NewAIMemory() *AIMemory {
-	return &AIMemory{}
+	return &AIMemory{captured: make(map[byte]int), rules: models.ClassicRules()}
 }
 
-func (m *AIMemory) Remember(pos engine.Position, piece *engine.Piece, confidence float64, round int) {
+// SetOwner sets the player the memory belongs to, so combat only updates the beliefs about enemy pieces.
+// Without an owner every piece in combat is treated as an enemy piece.
+func (m *AIMemory) SetOwner(player *engine.Player) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.owner = player
+}
+
+func (m *AIMemory) Remember(pos engine.Position, piece engine.SeenPiece, confidence float64, round int) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -47,7 +62,8 @@ func (m *AIMemory) Forget(pos engine.Position) {
 	m.field[pos.Y][pos.X] = nil
 }
 
-// MovePiece updates memory when a piece moves (critical for correctness)
+// MovePiece updates memory when an enemy piece moves (critical for correctness)
+// The belief about the piece moves along: it can no longer be a bomb or the flag, and only scouts move more than one square
 func (m *AIMemory) MovePiece(from, to engine.Position) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -56,16 +72,17 @@ func (m *AIMemory) MovePiece(from, to engine.Position) {
 		m.field[to.Y][to.X] = m.field[from.Y][from.X]
 		m.field[from.Y][from.X] = nil
 	}
+	m.moveBelief(from, to)
 }
 
 // UpdateFromCombat processes combat results to update memory
 // Call this when pieces are revealed in combat
-func (m *AIMemory) UpdateFromCombat(attackerPos, defenderPos engine.Position, attackerPiece, defenderPiece *engine.Piece, round int) {
+func (m *AIMemory) UpdateFromCombat(attackerPos, defenderPos engine.Position, attackerPiece, defenderPiece engine.SeenPiece, round int) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	// attacker survived and moved, update memory
-	if attackerPiece != nil && attackerPiece.IsAlive() {
+	if attackerPiece.Alive {
 		m.field[defenderPos.Y][defenderPos.X] = &MemoryEntry{
 			Piece:      attackerPiece,
 			Confidence: 1.0,
@@ -76,8 +93,8 @@ func (m *AIMemory) UpdateFromCombat(attackerPos, defenderPos engine.Position, at
 		// Attacker died, clear both positions
 		m.field[attackerPos.Y][attackerPos.X] = nil
 
-		// defender survived, remember it
-		if defenderPiece != nil && defenderPiece.IsAlive() {
+		// defender survived, remember it unless it stayed hidden
+		if defenderPiece.Type != nil && defenderPiece.Alive {
 			m.field[defenderPos.Y][defenderPos.X] = &MemoryEntry{
 				Piece:      defenderPiece,
 				Confidence: 1.0,
@@ -88,6 +105,43 @@ func (m *AIMemory) UpdateFromCombat(attackerPos, defenderPos engine.Position, at
 			m.field[defenderPos.Y][defenderPos.X] = nil
 		}
 	}
+	m.combatBelief(attackerPos, defenderPos, attackerPiece, defenderPiece)
+}
+
+// RecordCapture counts an enemy piece that was seen being captured
+func (m *AIMemory) RecordCapture(piece engine.SeenPiece) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.captured[piece.Type.GetRank()]++
+	m.posterior = false
+}
+
+// RecordRescue remembers an enemy piece brought back by the rescue rule, which is no longer captured
+func (m *AIMemory) RecordRescue(pos engine.Position, piece engine.SeenPiece, round int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.field[pos.Y][pos.X] = &MemoryEntry{
+		Piece:      piece,
+		Confidence: 1.0,
+		LastSeen:   round,
+	}
+	if m.captured[piece.Type.GetRank()] > 0 {
+		m.captured[piece.Type.GetRank()]--
+	}
+	b := newBelief()
+	b.markKnown(piece.Type.GetRank())
+	m.beliefs[pos.Y][pos.X] = b
+	m.posterior = false
+}
+
+// CapturedCount returns how many enemy pieces of the rank were seen being captured
+func (m *AIMemory) CapturedCount(rank byte) int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.captured[rank]
 }
 
 // Clear resets all memory (for new game)
@@ -95,7 +149,10 @@ func (m *AIMemory) Clear() {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	m.field = [10][10]*MemoryEntry{}
+	m.field = [models.MaxBoardSize][models.MaxBoardSize]*MemoryEntry{}
+	m.captured = make(map[byte]int)
+	m.beliefs = [models.MaxBoardSize][models.MaxBoardSize]*belief{}
+	m.posterior = false
 }
 
 // GetKnownEnemyPositions returns all positions where we remember enemy pieces
@@ -105,8 +162,8 @@ func (m *AIMemory) GetKnownEnemyPositions() []engine.Position {
 	defer m.mutex.RUnlock()
 
 	positions := make([]engine.Position, 0, 10)
-	for y := range 10 {
-		for x := 0; x < 10; x++ {
+	for y := range m.field {
+		for x := range m.field[y] {
 			if m.field[y][x] != nil {
 				positions = append(positions, engine.NewPosition(x, y))
 			}
@@ -121,8 +178,8 @@ func (m *AIMemory) DecayConfidence(decayRate float64, minConfidence float64) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	for y := range 10 {
-		for x := range 10 {
+	for y := range m.field {
+		for x := range m.field[y] {
 			if entry := m.field[y][x]; entry != nil && entry.Confidence < 1.0 {
 				entry.Confidence *= (1.0 - decayRate)
 				if entry.Confidence < minConfidence {
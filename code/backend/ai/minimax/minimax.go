@@ -0,0 +1,113 @@
+package minimax
+
+import (
+	"digital-innovation/stratego/ai"
+	"digital-innovation/stratego/engine"
+	"time"
+)
+
+// Config controls how much the MinimaxAI searches per move.
+// Without a time budget the search only depends on the AI's seed, so games can be replayed exactly.
+type Config struct {
+	Depth      int           // plies searched on each determinization
+	Samples    int           // maximum number of determinizations per move
+	TimeBudget time.Duration // time per move; sampling stops when it runs out, zero means no limit
+}
+
+// DefaultConfig returns the configuration used by NewMinimaxAI.
+func DefaultConfig() Config {
+	return Config{
+		Depth:      2,
+		Samples:    16,
+		TimeBudget: 50 * time.Millisecond,
+	}
+}
+
+// ReplayConfig returns the configuration of DefaultConfig without the time budget,
+// so a game can be replayed from the seed of the AI.
+func ReplayConfig() Config {
+	config := DefaultConfig()
+	config.TimeBudget = 0
+	return config
+}
+
+// MinimaxAI handles the hidden enemy pieces by determinization: for each move it samples boards in which the
+// unknown enemy pieces are filled in consistently with what it has seen, runs alpha-beta on each of them,
+// and plays the move that was best on most samples.
+type MinimaxAI struct {
+	ai.BaseAI
+	config Config
+}
+
+func NewMinimaxAI(player *engine.Player) *MinimaxAI {
+	return NewMinimaxAIWithConfig(player, DefaultConfig())
+}
+
+func NewMinimaxAIWithConfig(player *engine.Player, config Config) *MinimaxAI {
+	return &MinimaxAI{
+		BaseAI: *ai.NewBaseAI(player, true),
+		config: config,
+	}
+}
+
+// GetConfig returns the search configuration.
+func (m *MinimaxAI) GetConfig() Config {
+	return m.config
+}
+
+func (m *MinimaxAI) MakeMove(view *engine.PlayerView) engine.Move {
+	var deadline time.Time // zero means no limit
+	if m.config.TimeBudget > 0 {
+		deadline = time.Now().Add(m.config.TimeBudget)
+	}
+	player := m.GetPlayer()
+
+	legal := ai.ListAllLegalMoves(view)
+	if len(legal) == 0 {
+		return engine.Move{} // no valid moves left
+	}
+	if len(legal) == 1 {
+		return legal[0]
+	}
+
+	rootMoves := make([]engine.CompactMove, len(legal))
+	for i, move := range legal {
+		rootMoves[i] = engine.NewCompactMove(move)
+	}
+
+	m.GetMemory().SyncView(view)
+	determinizer := ai.NewDeterminizer(view, m.GetMemory())
+	votes := make([]int, len(legal))
+	totals := make([]float64, len(legal))
+	search := newSearch(m.config.Depth, ai.HomeRow(view), view.GetHeight(), view.GetRules(), deadline)
+
+	for sample := 0; sample < max(m.config.Samples, 1); sample++ {
+		board := determinizer.Sample(m.GetRand())
+		scores, complete := search.scoreRootMoves(&board, rootMoves, max(m.config.Depth, 1))
+		if !complete {
+			break // out of time, the sample is only partly searched
+		}
+
+		best := 0
+		for i, score := range scores {
+			totals[i] += score
+			if score > scores[best] {
+				best = i
+			}
+		}
+		votes[best]++
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+	}
+
+	// Most votes wins, the total score breaks ties. Without any finished sample, play a random legal move.
+	choice := m.GetRand().IntN(len(legal))
+	for i := range legal {
+		if votes[i] > votes[choice] || (votes[i] == votes[choice] && totals[i] > totals[choice]) {
+			choice = i
+		}
+	}
+	return engine.NewMove(legal[choice].GetFrom(), legal[choice].GetTo(), player)
+}
@@ -0,0 +1,81 @@
+package minimax_test
+
+import (
+	"digital-innovation/stratego/ai/minimax"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"testing"
+	"time"
+)
+
+func TestMakeMove(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := minimax.NewMinimaxAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	controller2 := minimax.NewMinimaxAI(&player2)
+
+	g := game.QuickStart(controller1, controller2)
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if err := g.ValidateMove(&move); err != nil {
+		t.Errorf("Expected a valid move, got: %v", err)
+	}
+}
+
+func TestMakeMoveCapturesRevealedFlag(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := minimax.NewMinimaxAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.NewGame(controller1, engine.NewHumanPlayerController(&player2))
+
+	flag := engine.NewPiece(models.Flag, &player2)
+	flag.Reveal()
+	g.Board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Flag, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 5), engine.NewPiece(models.Sergeant, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 4), flag)
+	g.Board.SetPieceAt(engine.NewPosition(9, 0), engine.NewPiece(models.Marshal, &player2))
+	g.InitializePieces()
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if move.GetTo() != engine.NewPosition(5, 4) {
+		t.Errorf("Expected the sergeant to capture the flag, got %v", move)
+	}
+}
+
+func TestMakeMoveAvoidsRevealedStrongerPiece(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	controller1 := minimax.NewMinimaxAI(&player1)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.NewGame(controller1, engine.NewHumanPlayerController(&player2))
+
+	marshal := engine.NewPiece(models.Marshal, &player2)
+	marshal.Reveal()
+	g.Board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Flag, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 5), engine.NewPiece(models.General, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(5, 4), marshal)
+	g.Board.SetPieceAt(engine.NewPosition(9, 0), engine.NewPiece(models.Flag, &player2))
+	g.InitializePieces()
+
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if move.GetTo() == engine.NewPosition(5, 4) {
+		t.Errorf("Expected the general not to attack the marshal")
+	}
+}
+
+func TestMakeMoveRespectsTimeBudget(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	config := minimax.Config{Depth: 3, Samples: 1_000_000, TimeBudget: 20 * time.Millisecond}
+	controller1 := minimax.NewMinimaxAIWithConfig(&player1, config)
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(controller1, engine.NewHumanPlayerController(&player2))
+
+	start := time.Now()
+	move := controller1.MakeMove(g.ViewFor(&player1))
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the move within the time budget, took %v", elapsed)
+	}
+	if err := g.ValidateMove(&move); err != nil {
+		t.Errorf("Expected a valid move, got: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package minimax
+
+import (
+	"digital-innovation/stratego/ai"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"math"
+	"time"
+)
+
+// winScore is the score of a captured flag, far above any material difference.
+const winScore = 10000.0
+
+// advanceWeight is the score of moving a piece one row towards the enemy, so the AI keeps making progress.
+const advanceWeight = 0.05
+
+// pieceValues maps piece IDs to their strategic value.
+var pieceValues = func() [16]float64 {
+	var values [16]float64
+	for _, pieceType := range ai.ArmyTypes {
+		id, _ := engine.GetPieceIDFromRank(pieceType.GetRank())
+		values[id] = float64(pieceType.GetStrategicValue())
+	}
+	return values
+}()
+
+// search runs depth-limited alpha-beta on determinized boards.
+// It keeps one move buffer per ply, so the search itself does not allocate.
+type search struct {
+	home     [2]int // back row of each color
+	rules    models.RuleSet
+	deadline time.Time
+	buffers  [][]engine.CompactMove
+	nodes    int
+	aborted  bool
+}
+
+func newSearch(depth, ownHome, height int, rules models.RuleSet, deadline time.Time) *search {
+	buffers := make([][]engine.CompactMove, depth+1)
+	for i := range buffers {
+		buffers[i] = make([]engine.CompactMove, 0, 128)
+	}
+	return &search{home: [2]int{ownHome, height - 1 - ownHome}, rules: rules, deadline: deadline, buffers: buffers}
+}
+
+// scoreRootMoves returns the minimax score of the root moves on the board, from the view of the own color.
+// It returns false if the time ran out before all moves were searched.
+func (s *search) scoreRootMoves(board *engine.CompactBoard, moves []engine.CompactMove, depth int) ([]float64, bool) {
+	scores := make([]float64, len(moves))
+	alpha := math.Inf(-1)
+	for i, move := range moves {
+		child := *board
+		if child.ApplyWithRules(move, s.rules) == engine.CombatFlagCaptured {
+			scores[i] = winScore
+		} else {
+			// Moves that cannot beat the best move so far only get an upper bound, which is enough to vote
+			scores[i] = -s.negamax(&child, ai.EnemyColor, depth-1, math.Inf(-1), -alpha)
+		}
+		if s.aborted {
+			return nil, false
+		}
+		alpha = max(alpha, scores[i])
+	}
+	return scores, true
+}
+
+// negamax returns the score of the board for the color to move, searching depth more plies.
+func (s *search) negamax(board *engine.CompactBoard, color byte, depth int, alpha, beta float64) float64 {
+	s.nodes++
+	if s.nodes&1023 == 0 && !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		s.aborted = true
+	}
+	if s.aborted || depth <= 0 {
+		return s.evaluate(board, color)
+	}
+
+	moves := board.GenerateMoves(color, s.buffers[depth][:0])
+	s.buffers[depth] = moves
+	if len(moves) == 0 {
+		return -winScore // no movable pieces left
+	}
+
+	best := math.Inf(-1)
+	for _, move := range moves {
+		child := *board
+		var score float64
+		if child.ApplyWithRules(move, s.rules) == engine.CombatFlagCaptured {
+			score = winScore
+		} else {
+			score = -s.negamax(&child, 1-color, depth-1, -beta, -alpha)
+		}
+		if score > best {
+			best = score
+		}
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
+
+// evaluate scores the board for the given color as the difference in strategic value of the pieces left,
+// plus a small bonus for pieces that have advanced towards the enemy.
+func (s *search) evaluate(board *engine.CompactBoard, color byte) float64 {
+	score := 0.0
+	for square := range uint8(100) {
+		if board[square]&engine.BitOccupied == 0 {
+			continue
+		}
+		id, owner := board.PieceID(square), board.Color(square)
+		value := pieceValues[id]
+		if id != engine.PieceIDFlag && id != engine.PieceIDBomb {
+			row := int(square / models.MaxBoardSize)
+			value += advanceWeight * float64(max(row-s.home[owner], s.home[owner]-row))
+		}
+		if owner == color {
+			score += value
+		} else {
+			score -= value
+		}
+	}
+	return score
+}
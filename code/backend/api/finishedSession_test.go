@@ -0,0 +1,34 @@
+package api_test
+
+import (
+	"digital-innovation/stratego/api"
+	"digital-innovation/stratego/models"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFinishedSessionIsRemoved(t *testing.T) {
+	server := api.NewGameServer()
+	server.SetSessionTimeouts(time.Minute, 10*time.Millisecond)
+	baseline := runtime.NumGoroutine()
+
+	handler, err := server.CreateGame("finished-game", models.AiVsAi, models.Fafo, models.Fafo)
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	if err := handler.Session.StartGameFromSetup(true); err != nil {
+		t.Fatalf("Expected no error starting game, got: %v", err)
+	}
+
+	if !waitFor(30*time.Second, func() bool { return server.SessionStats().Active == 0 }) {
+		t.Fatal("Expected the finished session to be removed")
+	}
+	if stats := server.SessionStats(); stats.Finished != 1 || stats.Abandoned != 0 {
+		t.Errorf("Expected 1 finished and 0 abandoned sessions, got: %+v", stats)
+	}
+	if handler.Session.IsRunning() {
+		t.Error("Expected the game of a removed session not to run")
+	}
+	expectNoLeakedGoroutines(t, baseline)
+}
@@ -2,11 +2,43 @@ package api
 
 import (
 	"digital-innovation/stratego/db"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Sizes of the pages of paginated endpoints, see queryPage
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// UserGamesPage is a page of the finished games of a user
+type UserGamesPage struct {
+	Games    []models.UserGame `json:"games"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+	Total    int               `json:"total"` // games matching the filters on all pages
+}
+
+// GameReplay is the board of a finished game after a number of moves, with all pieces revealed
+type GameReplay struct {
+	GameID          string                 `json:"gameId"`
+	Move            int                    `json:"move"` // moves played on the board, 0 for the initial setup
+	TotalMoves      int                    `json:"totalMoves"`
+	Board           [][]PieceDTO           `json:"board"`
+	Lakes           []PositionDTO          `json:"lakes"`
+	LastMove        *models.HistoricalMove `json:"lastMove,omitempty"`
+	CurrentPlayerID int                    `json:"currentPlayerId"`
+	Round           int                    `json:"round"`
+}
+
 // HandleGetGameHistory handles GET /games/:id/history
 // @Summary Get game history
 // @Description Retrieve the full move history and initial setup of a finished game
@@ -31,3 +63,187 @@ func (s *GameServer) HandleGetGameHistory(c *gin.Context) {
 
 	sendJSON(c, history, http.StatusOK)
 }
+
+// HandleGetGameReplay handles GET /games/:id/replay
+// @Summary Replay a game
+// @Description Reconstruct the board of a finished game after a number of moves, with all pieces revealed
+// @Tags games
+// @Produce json
+// @Param id path string true "Game ID"
+// @Param move query int false "Number of moves to play, 0 for the initial setup, all moves if omitted"
+// @Success 200 {object} api.GameReplay
+// @Failure 400 {object} map[string]string "Invalid move number"
+// @Failure 404 {object} map[string]string "Game not found"
+// @Router /games/{id}/replay [get]
+func (s *GameServer) HandleGetGameReplay(c *gin.Context) {
+	gameID := c.Param("id")
+	move := -1
+	if value := c.Query("move"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			sendError(c, "Invalid move number", http.StatusBadRequest)
+			return
+		}
+		move = n
+	}
+
+	history, err := db.GetGameHistory(gameID)
+	if err != nil {
+		sendError(c, "Game history not found or error retrieving it", http.StatusNotFound)
+		return
+	}
+	if move == -1 {
+		move = len(history.Moves)
+	}
+	if move > len(history.Moves) {
+		sendError(c, "Move number is after the last move", http.StatusBadRequest)
+		return
+	}
+
+	g, err := game.ReplayWithRules(history.Rules, history.InitialState, history.Moves[:move])
+	if err != nil {
+		sendError(c, "Stored game cannot be replayed", http.StatusInternalServerError)
+		return
+	}
+
+	replay := GameReplay{
+		GameID:          gameID,
+		Move:            move,
+		TotalMoves:      len(history.Moves),
+		Board:           revealedBoardDTO(g.Board),
+		Lakes:           LakesToDTO(g.Board),
+		CurrentPlayerID: g.CurrentPlayer.GetID(),
+		Round:           g.GetRound(),
+	}
+	if move > 0 {
+		replay.LastMove = &history.Moves[move-1]
+	}
+	sendJSON(c, replay, http.StatusOK)
+}
+
+// HandleGetUserGames handles GET /users/:id/games
+// @Summary List games of a user
+// @Description Retrieve a page of the finished games of a user, newest first
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page, starting at 1"
+// @Param pageSize query int false "Games per page, at most 100"
+// @Param gameType query string false "Game type: human_vs_ai, human_vs_human or ai_vs_ai"
+// @Param result query string false "Result for the user: win, loss or draw"
+// @Param from query string false "Finished on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Finished before this time, or on or before this date (YYYY-MM-DD or RFC 3339)"
+// @Success 200 {object} api.UserGamesPage
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Router /users/{id}/games [get]
+func (s *GameServer) HandleGetUserGames(c *gin.Context) {
+	userID, err := parseID(c, "id")
+	if err != nil || userID == 0 {
+		sendError(c, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	page, pageSize, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	filter := models.UserGamesFilter{
+		GameType: c.Query("gameType"),
+		Result:   c.Query("result"),
+		Limit:    pageSize,
+		Offset:   (page - 1) * pageSize,
+	}
+	switch filter.GameType {
+	case "", models.HumanVsAi, models.HumanVsHuman, models.AiVsAi:
+	default:
+		sendError(c, "Invalid game type", http.StatusBadRequest)
+		return
+	}
+	switch filter.Result {
+	case "", models.GameResultWin, models.GameResultLoss, models.GameResultDraw:
+	default:
+		sendError(c, "Invalid result, it must be win, loss or draw", http.StatusBadRequest)
+		return
+	}
+	if filter.From, err = queryTime(c, "from", false); err != nil {
+		sendError(c, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = queryTime(c, "to", true); err != nil {
+		sendError(c, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	games, total, err := db.GetUserGames(userID, filter)
+	if err != nil {
+		sendError(c, "Failed to get games", http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(c, UserGamesPage{Games: games, Page: page, PageSize: pageSize, Total: total}, http.StatusOK)
+}
+
+// queryPage returns the page and page size query parameters, it sends an error and returns false if they are invalid
+func queryPage(c *gin.Context) (page, pageSize int, ok bool) {
+	page, err := queryInt(c, "page", 1)
+	if err != nil || page < 1 {
+		sendError(c, "Invalid page", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	pageSize, err = queryInt(c, "pageSize", defaultPageSize)
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		sendError(c, fmt.Sprintf("Invalid page size, it must be between 1 and %d", maxPageSize), http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return page, pageSize, true
+}
+
+// queryInt returns an integer query parameter, or the default if it is not set
+func queryInt(c *gin.Context, key string, defaultValue int) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// queryTime parses a query parameter as RFC 3339 time or as date, nil if it is not set
+// A date as end of a range includes the whole day
+func queryTime(c *gin.Context, key string, end bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// revealedBoardDTO converts a board with all pieces visible, empty squares have owner -1
+func revealedBoardDTO(board *engine.Board) [][]PieceDTO {
+	field := board.GetField()
+	boardDTO := make([][]PieceDTO, len(field))
+	for y, row := range field {
+		boardDTO[y] = make([]PieceDTO, len(row))
+		for x, piece := range row {
+			viewerID := -1
+			if piece != nil {
+				viewerID = piece.GetOwner().GetID()
+			}
+			dto := PieceToDTO(piece, viewerID)
+			dto.Position = PositionDTO{X: x, Y: y}
+			dto.Revealed = piece != nil
+			boardDTO[y][x] = dto
+		}
+	}
+	return boardDTO
+}
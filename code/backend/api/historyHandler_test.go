@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"digital-innovation/stratego/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHistoryEndpointsRejectInvalidParameters(t *testing.T) {
+	server := api.NewGameServer()
+	server.DisableRateLimit()
+	handler := server.Handler()
+
+	testCases := []struct {
+		name string
+		url  string
+	}{
+		{"UserID", "/users/abc/games"},
+		{"Page", "/users/1/games?page=0"},
+		{"PageSize", "/users/1/games?pageSize=101"},
+		{"GameType", "/users/1/games?gameType=chess"},
+		{"Result", "/users/1/games?result=won"},
+		{"From", "/users/1/games?from=yesterday"},
+		{"To", "/users/1/games?to=2026-13-01"},
+		{"NegativeMove", "/games/some-game/replay?move=-1"},
+		{"MoveNumber", "/games/some-game/replay?move=last"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.url, nil))
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d for %s, got: %d %s", http.StatusBadRequest, tc.url, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
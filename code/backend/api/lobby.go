@@ -0,0 +1,215 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Statuses of a user in the lobby
+const (
+	LobbyStatusIdle    = "idle"    // not queued and no recent match
+	LobbyStatusQueued  = "queued"  // waiting for an opponent
+	LobbyStatusMatched = "matched" // paired, the game is ready
+)
+
+const (
+	baseRatingWindow   = 100.0            // largest rating difference between ranked players that just joined
+	ratingWindowGrowth = 10.0             // widening of the window per second of waiting
+	lobbyMatchLifetime = 5 * time.Minute  // how long a match can be fetched after pairing
+	lobbyQueueTimeout  = 10 * time.Minute // how long a player waits before being dropped from the queue
+)
+
+var ErrAlreadyQueued = errors.New("already in the queue")
+
+// LobbyMatch tells a player which game they were paired into
+type LobbyMatch struct {
+	GameID       string    `json:"gameId"`
+	Seat         int       `json:"seat"` // player to connect as, 0 (Red) or 1 (Blue)
+	WsURL        string    `json:"wsUrl"`
+	OpponentID   int       `json:"opponentId"`
+	OpponentName string    `json:"opponentName"`
+	MatchedAt    time.Time `json:"matchedAt"`
+}
+
+// LobbyStatus is the state of a user in the lobby
+type LobbyStatus struct {
+	Status         string      `json:"status"`             // LobbyStatusIdle, LobbyStatusQueued or LobbyStatusMatched
+	Position       int         `json:"position,omitempty"` // place in the queue, starting at 1
+	WaitingSeconds int         `json:"waitingSeconds,omitempty"`
+	QueueLength    int         `json:"queueLength"` // players waiting for an opponent
+	Match          *LobbyMatch `json:"match,omitempty"`
+}
+
+// LobbyPlayer is a user waiting in the lobby
+type LobbyPlayer struct {
+	UserID   int
+	Username string
+	Ranked   bool    // only paired with ranked players of a similar rating
+	Rating   float64 // only used when ranked
+	JoinedAt time.Time
+
+	matched chan LobbyMatch // receives the match, buffered
+}
+
+// CreateMatchFunc creates the game of two paired players, player1 plays Red
+type CreateMatchFunc func(player1, player2 *LobbyPlayer) (gameID string, err error)
+
+// Lobby pairs players that are looking for a human vs human game
+// Unranked players are paired in the order they joined, ranked players with the first ranked player
+// whose rating is within the window of both, which widens the longer they wait
+type Lobby struct {
+	mutex       sync.Mutex
+	queue       []*LobbyPlayer // in the order of joining
+	matches     map[int]LobbyMatch
+	createMatch CreateMatchFunc
+}
+
+func NewLobby(createMatch CreateMatchFunc) *Lobby {
+	return &Lobby{
+		matches:     make(map[int]LobbyMatch),
+		createMatch: createMatch,
+	}
+}
+
+// Join queues a user and pairs them if an opponent is waiting
+// The returned channel receives the match once the user is paired, also when that happens in a later Join or Pair
+func (l *Lobby) Join(userID int, username string, ranked bool, rating float64) (<-chan LobbyMatch, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.indexOf(userID) >= 0 {
+		return nil, ErrAlreadyQueued
+	}
+	delete(l.matches, userID)
+
+	player := &LobbyPlayer{
+		UserID:   userID,
+		Username: username,
+		Ranked:   ranked,
+		Rating:   rating,
+		JoinedAt: time.Now(),
+		matched:  make(chan LobbyMatch, 1),
+	}
+	l.queue = append(l.queue, player)
+	l.pair()
+	return player.matched, nil
+}
+
+// Leave removes a user from the queue and forgets their last match, it reports whether they were queued
+func (l *Lobby) Leave(userID int) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.matches, userID)
+	i := l.indexOf(userID)
+	if i < 0 {
+		return false
+	}
+	l.queue = append(l.queue[:i], l.queue[i+1:]...)
+	return true
+}
+
+// Status returns the state of a user, pairing waiting players first since their rating windows widen over time
+func (l *Lobby) Status(userID int) LobbyStatus {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.pair()
+	if match, ok := l.matches[userID]; ok {
+		return LobbyStatus{Status: LobbyStatusMatched, QueueLength: len(l.queue), Match: &match}
+	}
+	if i := l.indexOf(userID); i >= 0 {
+		return LobbyStatus{
+			Status:         LobbyStatusQueued,
+			Position:       i + 1,
+			WaitingSeconds: int(time.Since(l.queue[i].JoinedAt).Seconds()),
+			QueueLength:    len(l.queue),
+		}
+	}
+	return LobbyStatus{Status: LobbyStatusIdle, QueueLength: len(l.queue)}
+}
+
+// Pair pairs the waiting players whose rating windows have grown enough
+func (l *Lobby) Pair() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.pair()
+}
+
+// pair pairs compatible players in the order they joined, the caller must hold the mutex
+func (l *Lobby) pair() {
+	now := time.Now()
+	for userID, match := range l.matches {
+		if now.Sub(match.MatchedAt) > lobbyMatchLifetime {
+			delete(l.matches, userID)
+		}
+	}
+	// Players that stopped polling would otherwise be paired into a game nobody joins
+	l.queue = slices.DeleteFunc(l.queue, func(player *LobbyPlayer) bool {
+		return now.Sub(player.JoinedAt) > lobbyQueueTimeout
+	})
+
+	for i := 0; i < len(l.queue); i++ {
+		for j := i + 1; j < len(l.queue); j++ {
+			player1, player2 := l.queue[i], l.queue[j]
+			if !compatible(player1, player2, now) {
+				continue
+			}
+
+			gameID, err := l.createMatch(player1, player2)
+			if err != nil {
+				// Both stay queued, the next pairing tries again
+				continue
+			}
+			l.queue = append(l.queue[:j], l.queue[j+1:]...)
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			l.notify(player1, player2, gameID, 0, now)
+			l.notify(player2, player1, gameID, 1, now)
+			i--
+			break
+		}
+	}
+}
+
+func (l *Lobby) notify(player, opponent *LobbyPlayer, gameID string, seat int, now time.Time) {
+	match := LobbyMatch{
+		GameID:       gameID,
+		Seat:         seat,
+		WsURL:        fmt.Sprintf("/game/%s?player=%d", gameID, seat),
+		OpponentID:   opponent.UserID,
+		OpponentName: opponent.Username,
+		MatchedAt:    now,
+	}
+	l.matches[player.UserID] = match
+	player.matched <- match
+}
+
+func (l *Lobby) indexOf(userID int) int {
+	for i, player := range l.queue {
+		if player.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+// compatible reports whether two waiting players can be paired
+func compatible(a, b *LobbyPlayer, now time.Time) bool {
+	if a.Ranked != b.Ranked {
+		return false
+	}
+	if !a.Ranked {
+		return true
+	}
+	difference := math.Abs(a.Rating - b.Rating)
+	return difference <= ratingWindow(a, now) && difference <= ratingWindow(b, now)
+}
+
+// ratingWindow returns the largest rating difference a ranked player accepts after waiting until now
+func ratingWindow(player *LobbyPlayer, now time.Time) float64 {
+	return baseRatingWindow + ratingWindowGrowth*now.Sub(player.JoinedAt).Seconds()
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"digital-innovation/stratego/auth"
+	"digital-innovation/stratego/models"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// lobbyPollPeriod is how often a lobby WebSocket re-pairs the queue and sends the queue status
+const lobbyPollPeriod = 2 * time.Second
+
+// JoinLobbyRequest is the request to enter the matchmaking queue
+type JoinLobbyRequest struct {
+	Ranked bool `json:"ranked"` // only pair with ranked players of a similar rating
+}
+
+// createMatchGame creates the human vs human game of two players paired by the lobby
+func (s *GameServer) createMatchGame(player1, player2 *LobbyPlayer) (string, error) {
+	gameID := fmt.Sprintf("match-%d-%d", time.Now().Unix(), time.Now().UnixNano()%1000000)
+	handler, err := s.CreateGame(gameID, models.HumanVsHuman, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	// Only the paired users can take the seats, see HandleWebSocketConnection
+	player1ID, player2ID := player1.UserID, player2.UserID
+	handler.Session.Player1UserID = &player1ID
+	handler.Session.Player2UserID = &player2ID
+
+	log.Printf("Lobby: Paired user %d and user %d in game %s", player1ID, player2ID, gameID)
+	return gameID, nil
+}
+
+// HandleJoinLobby handles POST /lobby/queue
+// @Summary Join the matchmaking queue
+// @Description Queue the authenticated user for a human vs human game, poll GET /lobby/queue until matched
+// @Tags lobby
+// @Accept json
+// @Produce json
+// @Param request body api.JoinLobbyRequest false "Queue options"
+// @Success 200 {object} api.LobbyStatus
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Already in the queue"
+// @Router /lobby/queue [post]
+func (s *GameServer) HandleJoinLobby(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		sendError(c, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req JoinLobbyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendError(c, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := s.lobby.Join(user.ID, user.Username, req.Ranked, s.lobbyRating(user.ID)); err != nil {
+		if errors.Is(err, ErrAlreadyQueued) {
+			sendError(c, "Already in the queue", http.StatusConflict)
+			return
+		}
+		sendError(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(c, s.lobby.Status(user.ID), http.StatusOK)
+}
+
+// HandleGetLobbyStatus handles GET /lobby/queue
+// @Summary Matchmaking status
+// @Description Whether the authenticated user is queued or matched, with the game to join once matched
+// @Tags lobby
+// @Produce json
+// @Success 200 {object} api.LobbyStatus
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /lobby/queue [get]
+func (s *GameServer) HandleGetLobbyStatus(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		sendError(c, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sendJSON(c, s.lobby.Status(user.ID), http.StatusOK)
+}
+
+// HandleLeaveLobby handles DELETE /lobby/queue
+// @Summary Leave the matchmaking queue
+// @Description Remove the authenticated user from the queue
+// @Tags lobby
+// @Success 204 "Left the queue"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /lobby/queue [delete]
+func (s *GameServer) HandleLeaveLobby(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		sendError(c, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.lobby.Leave(user.ID)
+	sendNoContent(c)
+}
+
+// HandleLobbyWebSocket handles WebSocket connections to the lobby
+// @Summary Matchmaking WebSocket
+// @Description Queue the authenticated user while connected. The server sends lobbyStatus messages while waiting and a matchFound message with the seat and game to join, then closes the connection
+// @Tags lobby
+// @Param ranked query bool false "Only pair with ranked players of a similar rating"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Already in the queue"
+// @Router /lobby/ws [get]
+func (s *GameServer) HandleLobbyWebSocket(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		sendError(c, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ranked := c.Query("ranked") == "true"
+	matched, err := s.lobby.Join(user.ID, user.Username, ranked, s.lobbyRating(user.ID))
+	if err != nil {
+		if errors.Is(err, ErrAlreadyQueued) {
+			sendError(c, "Already in the queue", http.StatusConflict)
+			return
+		}
+		sendError(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Lobby WebSocket upgrade error: %v", err)
+		s.lobby.Leave(user.ID)
+		return
+	}
+
+	go s.serveLobbyClient(conn, user.ID, matched)
+}
+
+// serveLobbyClient keeps a user queued until they are matched or disconnect
+func (s *GameServer) serveLobbyClient(conn *websocket.Conn, userID int, matched <-chan LobbyMatch) {
+	defer conn.Close()
+
+	// Clients only send to close the connection
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(lobbyPollPeriod)
+	defer ticker.Stop()
+
+	status := s.lobby.Status(userID)
+	for {
+		if status.Status == LobbyStatusIdle {
+			// Dropped from the queue, e.g. after waiting too long
+			writeLobbyMessage(conn, MsgTypeError, ErrorMessage{Error: "no longer in the queue"})
+			return
+		}
+		if status.Status == LobbyStatusQueued && !writeLobbyMessage(conn, MsgTypeLobbyStatus, status) {
+			s.lobby.Leave(userID)
+			return
+		}
+
+		select {
+		case match := <-matched:
+			if writeLobbyMessage(conn, MsgTypeMatchFound, match) {
+				err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "matched"))
+				if err != nil {
+					log.Printf("Error writing close message: %v", err)
+				}
+			}
+			return
+
+		case <-disconnected:
+			s.lobby.Leave(userID)
+			return
+
+		case <-ticker.C:
+			status = s.lobby.Status(userID)
+		}
+	}
+}
+
+// writeLobbyMessage writes a message to a lobby connection and reports whether it succeeded
+func writeLobbyMessage(conn *websocket.Conn, msgType string, data any) bool {
+	if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		log.Printf("Error setting write deadline: %v", err)
+		return false
+	}
+	if err := conn.WriteJSON(WSMessage{Type: msgType, Data: data}); err != nil {
+		log.Printf("Error writing lobby message: %v", err)
+		return false
+	}
+	return true
+}
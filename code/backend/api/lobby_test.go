@@ -0,0 +1,165 @@
+package api_test
+
+import (
+	"digital-innovation/stratego/api"
+	"digital-innovation/stratego/auth"
+	"digital-innovation/stratego/models"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestLobby returns a lobby that records the pairs it creates games for
+func newTestLobby() (*api.Lobby, *[][2]int) {
+	pairs := &[][2]int{}
+	lobby := api.NewLobby(func(player1, player2 *api.LobbyPlayer) (string, error) {
+		*pairs = append(*pairs, [2]int{player1.UserID, player2.UserID})
+		return "match", nil
+	})
+	return lobby, pairs
+}
+
+func TestLobbyPairsUnrankedPlayersInOrder(t *testing.T) {
+	lobby, pairs := newTestLobby()
+	for userID := 1; userID <= 3; userID++ {
+		if _, err := lobby.Join(userID, "user", false, 0); err != nil {
+			t.Fatalf("Expected no error joining, got: %v", err)
+		}
+	}
+
+	if len(*pairs) != 1 || (*pairs)[0] != [2]int{1, 2} {
+		t.Fatalf("Expected users 1 and 2 to be paired, got: %v", *pairs)
+	}
+	first, second := lobby.Status(1), lobby.Status(2)
+	if first.Status != api.LobbyStatusMatched || first.Match.Seat != 0 || first.Match.OpponentID != 2 ||
+		first.Match.WsURL != "/game/match?player=0" {
+		t.Errorf("Expected user 1 to play Red against user 2, got: %+v", first.Match)
+	}
+	if second.Status != api.LobbyStatusMatched || second.Match.Seat != 1 || second.Match.OpponentID != 1 {
+		t.Errorf("Expected user 2 to play Blue against user 1, got: %+v", second.Match)
+	}
+	if third := lobby.Status(3); third.Status != api.LobbyStatusQueued || third.Position != 1 || third.QueueLength != 1 {
+		t.Errorf("Expected user 3 to wait first in the queue, got: %+v", third)
+	}
+}
+
+func TestLobbyPairsRankedPlayersByRating(t *testing.T) {
+	lobby, pairs := newTestLobby()
+	joins := []struct {
+		userID int
+		ranked bool
+		rating float64
+	}{
+		{1, true, 1500},
+		{2, true, 1900}, // too strong for user 1
+		{3, false, 0},   // not ranked
+		{4, true, 1550},
+	}
+	for _, join := range joins {
+		if _, err := lobby.Join(join.userID, "user", join.ranked, join.rating); err != nil {
+			t.Fatalf("Expected no error joining, got: %v", err)
+		}
+	}
+
+	if len(*pairs) != 1 || (*pairs)[0] != [2]int{1, 4} {
+		t.Errorf("Expected only users 1 and 4 to be paired, got: %v", *pairs)
+	}
+}
+
+func TestLobbyJoinAndLeave(t *testing.T) {
+	lobby, _ := newTestLobby()
+	if _, err := lobby.Join(1, "user", false, 0); err != nil {
+		t.Fatalf("Expected no error joining, got: %v", err)
+	}
+	if _, err := lobby.Join(1, "user", false, 0); !errors.Is(err, api.ErrAlreadyQueued) {
+		t.Errorf("Expected joining twice to fail with ErrAlreadyQueued, got: %v", err)
+	}
+	if !lobby.Leave(1) {
+		t.Error("Expected Leave to report the user was queued")
+	}
+	if status := lobby.Status(1); status.Status != api.LobbyStatusIdle {
+		t.Errorf("Expected the user to be idle after leaving, got: %+v", status)
+	}
+}
+
+// loginCookie creates an auth session and returns its cookie
+func loginCookie(t *testing.T, userID int, username string) *http.Cookie {
+	t.Helper()
+	session, err := auth.Store.CreateSession(userID, username)
+	if err != nil {
+		t.Fatalf("Expected no error creating session, got: %v", err)
+	}
+	t.Cleanup(func() { auth.Store.DeleteSession(session.ID) })
+	return &http.Cookie{Name: "session_id", Value: session.ID}
+}
+
+func TestLobbyMatchCreatesGameForBothUsers(t *testing.T) {
+	server := api.NewGameServer()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+	alice, bob := loginCookie(t, 101, "alice"), loginCookie(t, 102, "bob")
+
+	// Alice waits on the WebSocket, Bob joins through REST
+	header := http.Header{"Cookie": {alice.String()}}
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/lobby/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		t.Fatalf("Expected no error connecting to the lobby, got: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	var queued struct {
+		Type string          `json:"type"`
+		Data api.LobbyStatus `json:"data"`
+	}
+	if err := conn.ReadJSON(&queued); err != nil || queued.Type != api.MsgTypeLobbyStatus || queued.Data.Status != api.LobbyStatusQueued {
+		t.Fatalf("Expected Alice to be queued, got: %+v, %v", queued, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL+"/lobby/queue", strings.NewReader(`{"ranked": false}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.AddCookie(bob)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error joining the queue, got: %v", err)
+	}
+	defer resp.Body.Close()
+	var status api.LobbyStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil || status.Status != api.LobbyStatusMatched {
+		t.Fatalf("Expected Bob to be matched, got: %d %+v, %v", resp.StatusCode, status, err)
+	}
+	if status.Match.Seat != 1 || status.Match.OpponentName != "alice" {
+		t.Errorf("Expected Bob to play Blue against alice, got: %+v", status.Match)
+	}
+
+	var found struct {
+		Type string         `json:"type"`
+		Data api.LobbyMatch `json:"data"`
+	}
+	if err := conn.ReadJSON(&found); err != nil || found.Type != api.MsgTypeMatchFound {
+		t.Fatalf("Expected Alice to be notified of the match, got: %+v, %v", found, err)
+	}
+	if found.Data.GameID != status.Match.GameID || found.Data.Seat != 0 || found.Data.WsURL != "/game/"+found.Data.GameID+"?player=0" {
+		t.Errorf("Expected Alice to play Red in Bob's game, got: %+v", found.Data)
+	}
+
+	handler, exists := server.GetSession(status.Match.GameID)
+	if !exists {
+		t.Fatal("Expected the matched game to exist")
+	}
+	defer handler.Session.Stop()
+	if handler.GameType != models.HumanVsHuman || handler.Session.Player1UserID == nil || *handler.Session.Player1UserID != 101 ||
+		handler.Session.Player2UserID == nil || *handler.Session.Player2UserID != 102 {
+		t.Errorf("Expected a human vs human game of Alice and Bob, got type %s", handler.GameType)
+	}
+}
@@ -32,6 +32,8 @@ const (
 	MsgTypeValidMoves  = "validMoves"
 	MsgTypeSetupPhase  = "setupPhase"
 	MsgTypeMoveHistory = "moveHistory"
+	MsgTypeLobbyStatus = "lobbyStatus"
+	MsgTypeMatchFound  = "matchFound"
 )
 
 // Base message structure
@@ -61,7 +63,7 @@ type StartGameMessage struct {
 
 type LoadSetupMessage struct {
 	PlayerID  *int   `json:"playerId,omitempty"`
-	SetupData string `json:"setupData"` // Base64 encoded 40 bytes
+	SetupData string `json:"setupData"` // Base64 encoded, one byte per setup square (40 in the classic game)
 }
 
 type RandomizeSetupMessage struct {
@@ -90,6 +92,7 @@ type GameStateMessage struct {
 	Player2AlivePieces int    `json:"player2AlivePieces"`
 	IsSetupPhase       bool   `json:"isSetupPhase"`
 	Headless           bool   `json:"headless"`
+	Spectators         int    `json:"spectators"`
 }
 
 type MoveResultMessage struct {
@@ -117,6 +120,7 @@ type BoardStateMessage struct {
 	Board    [][]PieceDTO           `json:"board"`
 	Width    int                    `json:"width"`
 	Height   int                    `json:"height"`
+	Lakes    []PositionDTO          `json:"lakes"`
 	LastMove *models.HistoricalMove `json:"lastMove,omitempty"`
 }
 
@@ -168,6 +172,25 @@ func PositionToDTO(pos engine.Position) PositionDTO {
 	return PositionDTO{X: pos.X, Y: pos.Y}
 }
 
+// LakesToDTO converts the lake squares of a board
+func LakesToDTO(board *engine.Board) []PositionDTO {
+	lakes := make([]PositionDTO, len(board.GetLakes()))
+	for i, lake := range board.GetLakes() {
+		lakes[i] = PositionToDTO(lake)
+	}
+	return lakes
+}
+
+// newBoardStateMessage returns a board state with the size and lakes of the board
+func newBoardStateMessage(board *engine.Board, boardDTO [][]PieceDTO) BoardStateMessage {
+	return BoardStateMessage{
+		Board:  boardDTO,
+		Width:  board.GetWidth(),
+		Height: board.GetHeight(),
+		Lakes:  LakesToDTO(board),
+	}
+}
+
 func MoveToDTO(move engine.Move) MoveDTO {
 	return MoveDTO{
 		From: PositionToDTO(move.GetFrom()),
@@ -3,6 +3,7 @@ package api_test
 import (
 	"digital-innovation/stratego/api"
 	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
 	"digital-innovation/stratego/models"
 	"testing"
 )
@@ -51,6 +52,33 @@ func TestPieceToDTORevealed(t *testing.T) {
 	}
 }
 
+func TestPieceToDTOSilentDefense(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	rules := models.ClassicRules()
+	rules.SilentDefense = true
+	g := game.NewGameWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules)
+
+	scout := engine.NewPiece(models.Scout, &player1)
+	bomb := engine.NewPiece(models.Bomb, &player2)
+	g.Board.SetPieceAt(engine.NewPosition(4, 6), scout)
+	g.Board.SetPieceAt(engine.NewPosition(4, 5), bomb)
+	g.InitializePieces()
+	move := engine.NewMove(engine.NewPosition(4, 6), engine.NewPosition(4, 5), &player1)
+	g.MakeMove(&move, scout)
+
+	// The bomb that stopped the scout stays hidden from the attacker, its owner still sees it
+	if dto := api.PieceToDTO(bomb, 0); dto.Rank != "" || dto.Revealed {
+		t.Errorf("Expected the defending bomb to be hidden from the attacker, got: %+v", dto)
+	}
+	if dto := api.PieceToDTO(bomb, 1); dto.Type != "Bomb" {
+		t.Errorf("Expected the owner to see the bomb, got: %+v", dto)
+	}
+	if dto := api.PieceToDTO(scout, 1); dto.Type != "Scout" || !dto.Revealed {
+		t.Errorf("Expected the attacking scout to be revealed, got: %+v", dto)
+	}
+}
+
 func TestPieceToDTONil(t *testing.T) {
 	dto := api.PieceToDTO(nil, 0)
 
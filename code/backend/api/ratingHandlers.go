@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"digital-innovation/stratego/db"
+	"digital-innovation/stratego/models"
+	"digital-innovation/stratego/rating"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaderboardPage is a page of a leaderboard
+type LeaderboardPage struct {
+	Ratings  []models.Rating `json:"ratings"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"pageSize"`
+	Total    int             `json:"total"` // rated players on all pages
+}
+
+// UserRating is the rating of a user with a page of its history
+type UserRating struct {
+	Rating   models.Rating         `json:"rating"`
+	History  []models.RatingChange `json:"history"` // newest first
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+	Total    int                   `json:"total"` // rated games on all pages
+}
+
+// HandleGetLeaderboard handles GET /leaderboard
+// @Summary User leaderboard
+// @Description Retrieve a page of the ratings of users, highest first
+// @Tags leaderboard
+// @Produce json
+// @Param page query int false "Page, starting at 1"
+// @Param pageSize query int false "Ratings per page, at most 100"
+// @Success 200 {object} api.LeaderboardPage
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Router /leaderboard [get]
+func (s *GameServer) HandleGetLeaderboard(c *gin.Context) {
+	s.sendLeaderboard(c, models.RatedUser)
+}
+
+// HandleGetAILeaderboard handles GET /leaderboard/ai
+// @Summary AI leaderboard
+// @Description Retrieve a page of the ratings of AIs, highest first
+// @Tags leaderboard
+// @Produce json
+// @Param page query int false "Page, starting at 1"
+// @Param pageSize query int false "Ratings per page, at most 100"
+// @Success 200 {object} api.LeaderboardPage
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Router /leaderboard/ai [get]
+func (s *GameServer) HandleGetAILeaderboard(c *gin.Context) {
+	s.sendLeaderboard(c, models.RatedAI)
+}
+
+func (s *GameServer) sendLeaderboard(c *gin.Context, playerType string) {
+	page, pageSize, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	ratings, total, err := db.GetLeaderboard(playerType, pageSize, (page-1)*pageSize)
+	if err != nil {
+		sendError(c, "Failed to get leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(c, LeaderboardPage{Ratings: ratings, Page: page, PageSize: pageSize, Total: total}, http.StatusOK)
+}
+
+// HandleGetUserRating handles GET /users/:id/rating
+// @Summary Get user rating
+// @Description Retrieve the rating of a user with a page of its history, newest first
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page, starting at 1"
+// @Param pageSize query int false "Rating changes per page, at most 100"
+// @Success 200 {object} api.UserRating
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 404 {object} map[string]string "User has no rated games"
+// @Router /users/{id}/rating [get]
+func (s *GameServer) HandleGetUserRating(c *gin.Context) {
+	userID, err := parseID(c, "id")
+	if err != nil || userID == 0 {
+		sendError(c, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+	page, pageSize, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	player := models.RatedPlayer{UserID: &userID}
+	current, err := db.GetRating(player)
+	if errors.Is(err, sql.ErrNoRows) {
+		sendError(c, "User has no rated games", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		sendError(c, "Failed to get rating", http.StatusInternalServerError)
+		return
+	}
+	history, total, err := db.GetRatingHistory(player, pageSize, (page-1)*pageSize)
+	if err != nil {
+		sendError(c, "Failed to get rating history", http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(c, UserRating{Rating: *current, History: history, Page: page, PageSize: pageSize, Total: total}, http.StatusOK)
+}
+
+// lobbyRating returns the rating a ranked user is paired by in the lobby
+func (s *GameServer) lobbyRating(userID int) float64 {
+	if db.DB == nil {
+		return rating.DefaultRating
+	}
+	value, err := db.GetUserRating(userID)
+	if err != nil {
+		log.Printf("Lobby: Failed to get rating of user %d, using the default: %v", userID, err)
+		return rating.DefaultRating
+	}
+	return value
+}
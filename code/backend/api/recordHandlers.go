@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"digital-innovation/stratego/db"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecordSize limits uploaded game records, a classic game of 1000 turns takes about 20 KB
+const maxRecordSize = 1 << 20
+
+// ImportedGame is a game record that was uploaded and stored as a finished game
+type ImportedGame struct {
+	GameID   string `json:"gameId"`
+	Rules    string `json:"rules"`
+	Moves    int    `json:"moves"`
+	WinnerID *int   `json:"winnerId"` // seat of the winner, nil for a draw
+	WinCause string `json:"winCause,omitempty"`
+}
+
+// HandleGetGameRecord handles GET /games/:id/record
+// @Summary Download a game record
+// @Description Download a finished game as a text game record: the setups of both players and one move per line
+// @Description in coordinate notation, e.g. B4-B5 for a move and C7xC6 7x4 for an attack with the ranks of both pieces
+// @Tags games
+// @Produce plain
+// @Param id path string true "Game ID"
+// @Success 200 {string} string "Game record"
+// @Failure 404 {object} map[string]string "Game not found"
+// @Router /games/{id}/record [get]
+func (s *GameServer) HandleGetGameRecord(c *gin.Context) {
+	gameID := c.Param("id")
+	history, err := db.GetGameHistory(gameID)
+	if err != nil {
+		sendError(c, "Game history not found or error retrieving it", http.StatusNotFound)
+		return
+	}
+
+	g, err := game.ReplayWithRules(history.Rules, history.InitialState, history.Moves)
+	if err != nil {
+		sendError(c, "Stored game cannot be replayed", http.StatusInternalServerError)
+		return
+	}
+	// Games that did not end with a captured flag keep only their winner
+	if !g.IsGameOver() {
+		if history.WinnerID != nil && *history.WinnerID >= 0 && *history.WinnerID < len(g.Players) {
+			g.SetWinner(g.Players[*history.WinnerID], "")
+		} else {
+			g.SetDraw("")
+		}
+	}
+
+	record, err := game.ExportRecord(g)
+	if err != nil {
+		log.Printf("Failed to export game %s: %v", gameID, err)
+		sendError(c, "Failed to export game record", http.StatusInternalServerError)
+		return
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": gameID + ".txt"}))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(record))
+}
+
+// HandleImportGameRecord handles POST /games/import
+// @Summary Upload a game record
+// @Description Store a finished game from a text game record, see GET /games/{id}/record.
+// @Description The game is played through the engine, which checks the setups, every move and the result.
+// @Tags games
+// @Accept plain
+// @Produce json
+// @Security ApiKeyAuth
+// @Param record body string true "Game record"
+// @Success 201 {object} api.ImportedGame
+// @Failure 400 {object} map[string]string "Invalid or unfinished game record"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 413 {object} map[string]string "Game record too large"
+// @Failure 503 {object} map[string]string "Database not available"
+// @Router /games/import [post]
+func (s *GameServer) HandleImportGameRecord(c *gin.Context) {
+	if user := ensureAuthenticated(c); user == nil {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRecordSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			sendError(c, fmt.Sprintf("Game record must not be larger than %d bytes", maxRecordSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		sendError(c, "Failed to read game record", http.StatusBadRequest)
+		return
+	}
+	g, err := game.ImportRecord(bytes.NewReader(data))
+	if err != nil {
+		sendError(c, "Invalid game record: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !g.IsGameOver() {
+		sendError(c, "Only finished games can be imported, the game record has no result", http.StatusBadRequest)
+		return
+	}
+	if db.DB == nil {
+		sendError(c, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	imported := ImportedGame{
+		GameID:   fmt.Sprintf("imported-%d-%d", time.Now().Unix(), time.Now().UnixNano()%1000000),
+		Rules:    g.Rules.Name,
+		Moves:    len(g.HistoricalHistory),
+		WinnerID: g.GetWinnerID(),
+		WinCause: string(g.GetWinCause()),
+	}
+
+	if err := db.SaveGame(imported.GameID, nil, nil, models.Imported, g.Rules, g.InitialState, imported.WinnerID); err != nil {
+		log.Printf("Failed to save imported game %s: %v", imported.GameID, err)
+		sendError(c, "Failed to save game", http.StatusInternalServerError)
+		return
+	}
+	for _, m := range g.HistoricalHistory {
+		if err := db.SaveMove(imported.GameID, m); err != nil {
+			log.Printf("Failed to save move %d of imported game %s: %v", m.MoveIndex, imported.GameID, err)
+			sendError(c, "Failed to save game", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	sendJSON(c, imported, http.StatusCreated)
+}
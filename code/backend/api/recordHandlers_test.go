@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"digital-innovation/stratego/api"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// miniatureRecord is a game on a 4x4 board with a flag and a scout per player, won by red in five moves
+const miniatureRecord = `Rules: {"name":"custom","board":{"name":"custom","width":4,"height":4,"lakes":[]},"army":{"0":1,"2":1},"setupRows":1,"repetitionRules":true}
+Red: Alice
+Blue: Bob
+Result: red
+Cause: flag_captured
+
+Setup 4: 0 2 . .
+Setup 1: 0 2 . .
+
+B1-B3
+B4-C4
+B3-A3
+C4-D4
+A3xA4 2x0
+`
+
+func TestImportGameRecord(t *testing.T) {
+	server := api.NewGameServer()
+	server.DisableRateLimit()
+	handler := server.Handler()
+	cookie := loginCookie(t, 1, "alice")
+
+	testCases := []struct {
+		name   string
+		record string
+		login  bool
+		status int
+	}{
+		{"NotLoggedIn", miniatureRecord, false, http.StatusUnauthorized},
+		{"InvalidRecord", strings.Replace(miniatureRecord, "2x0", "2x9", 1), true, http.StatusBadRequest},
+		{"Unfinished", strings.Replace(strings.Replace(miniatureRecord, "A3xA4 2x0\n", "", 1), "Result: red\nCause: flag_captured\n", "", 1), true, http.StatusBadRequest},
+		{"TooLarge", miniatureRecord + strings.Repeat("#", 1<<20), true, http.StatusRequestEntityTooLarge},
+		// The record is valid, but tests run without a database
+		{"NoDatabase", miniatureRecord, true, http.StatusServiceUnavailable},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/games/import", strings.NewReader(tc.record))
+			request.Header.Set("Content-Type", "text/plain")
+			if tc.login {
+				request.AddCookie(cookie)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got: %d %s", tc.status, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
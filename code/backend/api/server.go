@@ -3,14 +3,17 @@ package api
 import (
 	AIhandler "digital-innovation/stratego/ai/handler"
 	"digital-innovation/stratego/auth"
+	"digital-innovation/stratego/db"
 	"digital-innovation/stratego/engine"
 	"digital-innovation/stratego/game"
 	"digital-innovation/stratego/models"
 	"digital-innovation/stratego/utils"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -24,9 +27,27 @@ import (
 
 // GameServer manages HTTP and WebSocket connections
 type GameServer struct {
-	sessions map[string]*GameSessionHandler
-	mutex    sync.RWMutex
-	router   *gin.Engine
+	sessions  map[string]*GameSessionHandler
+	mutex     sync.RWMutex
+	router    *gin.Engine
+	routes    sync.Once
+	rateLimit rate.Limit // requests per second per IP
+	rateBurst int
+
+	abandonTimeout    time.Duration // how long a session without clients is kept
+	finishedRetention time.Duration // how long a finished game is kept so players can see the result
+	finishedSessions  int
+	abandonedSessions int
+	spectatorDelay    time.Duration // see SetSpectatorDelay
+
+	lobby *Lobby // matchmaking for human vs human games
+}
+
+// SessionStats counts the sessions of a server
+type SessionStats struct {
+	Active    int `json:"active"`    // sessions in memory
+	Finished  int `json:"finished"`  // sessions removed after the game was over
+	Abandoned int `json:"abandoned"` // sessions removed after all clients left or the game was stopped
 }
 
 // GameSessionHandler wraps a game session with its WebSocket hub
@@ -34,6 +55,10 @@ type GameSessionHandler struct {
 	Session  *game.GameSession
 	Hub      *WSHub
 	GameType string
+	AI1      string // AIs the game was created with, see CreateGame
+	AI2      string
+
+	AllowSpectators bool // whether spectators can connect and the game is listed in LiveGames
 }
 
 func NewGameServer() *GameServer {
@@ -42,14 +67,57 @@ func NewGameServer() *GameServer {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	return &GameServer{
-		sessions: make(map[string]*GameSessionHandler),
-		router:   gin.New(),
+	s := &GameServer{
+		sessions:  make(map[string]*GameSessionHandler),
+		router:    gin.New(),
+		rateLimit: rate.Limit(5),
+		rateBurst: 10,
+
+		abandonTimeout:    1 * time.Minute,
+		finishedRetention: 30 * time.Second,
+		spectatorDelay:    5 * time.Second,
+	}
+	s.lobby = NewLobby(s.createMatchGame)
+	return s
+}
+
+// DisableRateLimit turns off the per-IP rate limiting, e.g. for load tests where all clients share one IP.
+// It must be called before Handler or StartServer.
+func (s *GameServer) DisableRateLimit() {
+	s.rateLimit = rate.Inf
+}
+
+// SetSessionTimeouts sets how long sessions without clients and finished sessions are kept
+// It only applies to games created afterwards
+func (s *GameServer) SetSessionTimeouts(abandoned, finished time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.abandonTimeout = abandoned
+	s.finishedRetention = finished
+}
+
+// SessionStats returns the number of active sessions and of removed ones
+func (s *GameServer) SessionStats() SessionStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return SessionStats{
+		Active:    len(s.sessions),
+		Finished:  s.finishedSessions,
+		Abandoned: s.abandonedSessions,
 	}
 }
 
 // CreateGame creates a new game session
 func (s *GameServer) CreateGame(gameID string, gameType string, ai1, ai2 string) (*GameSessionHandler, error) {
+	return s.CreateGameWithRules(gameID, gameType, ai1, ai2, models.ClassicRules())
+}
+
+// CreateGameWithRules creates a new game session played with the rule set
+func (s *GameServer) CreateGameWithRules(gameID string, gameType string, ai1, ai2 string, rules models.RuleSet) (*GameSessionHandler, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rule set: %w", err)
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -57,6 +125,17 @@ func (s *GameServer) CreateGame(gameID string, gameType string, ai1, ai2 string)
 		return nil, fmt.Errorf("game %s already exists", gameID)
 	}
 
+	controller1, controller2, err := newControllers(gameType, ai1, ai2)
+	if err != nil {
+		return nil, err
+	}
+
+	session := game.NewGameSessionWithRules(gameID, controller1, controller2, rules)
+	return s.addSession(session, gameType, ai1, ai2), nil
+}
+
+// newControllers creates the controllers of both players for a game type
+func newControllers(gameType string, ai1, ai2 string) (engine.PlayerController, engine.PlayerController, error) {
 	var controller1, controller2 engine.PlayerController
 	switch gameType {
 	case models.HumanVsAi:
@@ -78,28 +157,107 @@ func (s *GameServer) CreateGame(gameID string, gameType string, ai1, ai2 string)
 		controller2 = engine.NewHumanPlayerController(&player2)
 
 	default:
-		return nil, fmt.Errorf("unknown game type: %s", gameType)
+		return nil, nil, fmt.Errorf("unknown game type: %s", gameType)
 	}
+	return controller1, controller2, nil
+}
 
-	session := game.NewGameSession(gameID, controller1, controller2)
-
+// addSession registers a session and starts its hub and monitor, the caller must hold the mutex
+func (s *GameServer) addSession(session *game.GameSession, gameType string, ai1, ai2 string) *GameSessionHandler {
 	hub := NewWSHub(session, gameType)
+	hub.cleanupPeriod = s.abandonTimeout
+	if gameType != models.AiVsAi {
+		// Only games with a human player can be ghosted
+		hub.spectatorDelay = s.spectatorDelay
+	}
 
 	handler := &GameSessionHandler{
-		Session:  session,
-		Hub:      hub,
-		GameType: gameType,
+		Session:         session,
+		Hub:             hub,
+		GameType:        gameType,
+		AI1:             ai1,
+		AI2:             ai2,
+		AllowSpectators: true,
 	}
 
-	s.sessions[gameID] = handler
+	s.sessions[session.ID] = handler
 
 	// Start the hub
 	go hub.Run()
 
-	// Start game monitoring for broadcasting moves
+	// Sessions nobody connects to are removed like abandoned ones, the first client cancels the timer
+	hub.startCleanupTimer()
+
+	// Start game monitoring for broadcasting moves, it removes the session when the game ends
 	go s.monitorGame(handler, gameType)
 
-	return handler, nil
+	return handler
+}
+
+// RestoreGames resumes the in-progress games stored in the database, e.g. after a restart
+// Players can reconnect to a restored game within the abandon timeout, see SetSessionTimeouts
+// It returns the number of restored games, games that cannot be restored are logged and dropped
+func (s *GameServer) RestoreGames() (int, error) {
+	snapshots, err := db.GetGameSnapshots()
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, snapshot := range snapshots {
+		if err := s.restoreGame(snapshot); err != nil {
+			log.Printf("Failed to restore game %s, dropping it: %v", snapshot.GameID, err)
+			if err := db.DeleteGameSnapshot(snapshot.GameID); err != nil {
+				log.Printf("Failed to delete snapshot of game %s: %v", snapshot.GameID, err)
+			}
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+func (s *GameServer) restoreGame(snapshot models.GameSnapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.sessions[snapshot.GameID]; exists {
+		return fmt.Errorf("game %s already exists", snapshot.GameID)
+	}
+	controller1, controller2, err := newControllers(snapshot.GameType, snapshot.AI1, snapshot.AI2)
+	if err != nil {
+		return err
+	}
+	session, err := game.RestoreGameSession(snapshot, controller1, controller2)
+	if err != nil {
+		return err
+	}
+
+	s.addSession(session, snapshot.GameType, snapshot.AI1, snapshot.AI2)
+	log.Printf("Restored game %s at move %d", snapshot.GameID, len(snapshot.Moves))
+	return session.Start()
+}
+
+// endSession removes a session from the server and stops its game and hub
+func (s *GameServer) endSession(handler *GameSessionHandler, finished bool) {
+	s.mutex.Lock()
+	if s.sessions[handler.Session.ID] == handler {
+		delete(s.sessions, handler.Session.ID)
+	}
+	if finished {
+		s.finishedSessions++
+	} else {
+		s.abandonedSessions++
+	}
+	s.mutex.Unlock()
+
+	handler.Session.Stop()
+	handler.Hub.Stop()
+	if !finished {
+		// Only games interrupted by a restart are resumed
+		s.dropSnapshot(handler.Session.ID)
+	}
+	log.Printf("Removed session %s (finished=%v)", handler.Session.ID, finished)
 }
 
 // GetSession returns a game session handler
@@ -116,27 +274,45 @@ func (s *GameServer) PrintRoutes() {
 	for _, route := range s.router.Routes() {
 		fmt.Printf("  %-8s %-30s %s\n", route.Method, route.Path, route.Handler)
 	}
-	fmt.Printf("  %-8s %-30s %s\n", "WS", "/game/:gameID?player={0|1|spec}", "WebSocket connection")
+	fmt.Printf("  %-8s %-30s %s\n", "WS", "/game/:gameID?player={0|1|spec}&view=...", "WebSocket connection")
 	fmt.Println("============================")
 	fmt.Println()
 }
 
 // StartServer starts the HTTP server
 func (s *GameServer) StartServer(addr string) error {
+	s.Handler()
+	s.PrintRoutes()
+
+	log.Printf("Starting game server on %s", addr)
+	return s.router.Run(addr)
+}
+
+// Handler returns the HTTP handler with all middleware and routes, registering them on the first call
+func (s *GameServer) Handler() http.Handler {
+	s.routes.Do(s.registerRoutes)
+	return s.router
+}
+
+func (s *GameServer) registerRoutes() {
 	s.router.Use(gin.Recovery())
 	s.router.Use(gin.LoggerWithConfig(gin.LoggerConfig{
 		SkipPaths: []string{"/health"},
 	}))
 
 	// Configure CORS
-	corsConfig := cors.DefaultConfig()
-	// Whitelist allowed origins
+	// Whitelist allowed origins, without any no cross-origin requests are allowed (e.g. in-process stress tests)
 	allowedOrigins := utils.GetEnv("ALLOWED_ORIGINS", "")
-	corsConfig.AllowOrigins = strings.Split(allowedOrigins, ",")
-	corsConfig.AllowCredentials = true
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Content-Type", "Authorization", "X-Requested-With", "X-XSRF-TOKEN"}
-	s.router.Use(cors.New(corsConfig))
+	if allowedOrigins != "" {
+		corsConfig := cors.DefaultConfig()
+		corsConfig.AllowOrigins = strings.Split(allowedOrigins, ",")
+		corsConfig.AllowCredentials = true
+		corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+		corsConfig.AllowHeaders = []string{"Content-Type", "Authorization", "X-Requested-With", "X-XSRF-TOKEN"}
+		s.router.Use(cors.New(corsConfig))
+	} else {
+		log.Printf("ALLOWED_ORIGINS is not set, cross-origin requests are not allowed")
+	}
 
 	// Security Headers
 	s.router.Use(SecurityMiddleware())
@@ -148,7 +324,7 @@ func (s *GameServer) StartServer(addr string) error {
 	s.router.Use(CSRFMiddleware())
 
 	// Rate Limiting (5 requests per second per IP, burst of 10)
-	limiter := NewIPRateLimiter(rate.Limit(5), 10)
+	limiter := NewIPRateLimiter(s.rateLimit, s.rateBurst)
 	s.router.Use(RateLimitMiddleware(limiter))
 
 	// Health check
@@ -177,6 +353,8 @@ func (s *GameServer) StartServer(addr string) error {
 		// Public info
 		users.GET("/count", s.UserCountHandler)
 		users.GET("/:id", s.GetUserHandler)
+		users.GET("/:id/games", s.HandleGetUserGames)
+		users.GET("/:id/rating", s.HandleGetUserRating)
 		users.GET("/stats", s.GetUserStatsHandler)
 	}
 
@@ -198,13 +376,31 @@ func (s *GameServer) StartServer(addr string) error {
 		games.POST("", s.HandleCreateGame)
 		games.GET("", s.HandleListGames)
 		games.GET("/count", s.GamesPlayedCountHandler)
+		games.GET("/live", s.HandleListLiveGames)
+		games.GET("/sessions", s.HandleSessionStats)
+		games.GET("/:id/history", s.HandleGetGameHistory)
+		games.GET("/:id/replay", s.HandleGetGameReplay)
+		games.GET("/:id/record", s.HandleGetGameRecord)
+		games.POST("/import", s.HandleImportGameRecord)
 	}
 
-	// WebSocket endpoint
-	s.router.GET("/game/:gameID", auth.OptionalAuth(), s.HandleWebSocketConnection)
+	// Ratings
+	leaderboard := s.router.Group("/leaderboard")
+	{
+		leaderboard.GET("", s.HandleGetLeaderboard)
+		leaderboard.GET("/ai", s.HandleGetAILeaderboard)
+	}
 
-	s.PrintRoutes()
+	// Matchmaking for human vs human games
+	lobby := s.router.Group("/lobby")
+	lobby.Use(auth.RequireAuth())
+	{
+		lobby.POST("/queue", s.HandleJoinLobby)
+		lobby.GET("/queue", s.HandleGetLobbyStatus)
+		lobby.DELETE("/queue", s.HandleLeaveLobby)
+		lobby.GET("/ws", s.HandleLobbyWebSocket)
+	}
 
-	log.Printf("Starting game server on %s", addr)
-	return s.router.Run(addr)
+	// WebSocket endpoint
+	s.router.GET("/game/:gameID", auth.OptionalAuth(), s.HandleWebSocketConnection)
 }
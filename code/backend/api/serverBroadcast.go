@@ -2,7 +2,6 @@ package api
 
 import (
 	"digital-innovation/stratego/game"
-	"digital-innovation/stratego/models"
 	"log"
 )
 
@@ -17,8 +16,6 @@ func (s *GameServer) broadcastFullState(hub *WSHub, gameType string) {
 	switch {
 	case state.IsSetupPhase:
 		s.broadcastSetupBoard(hub, gameType)
-	case gameType == models.AiVsAi:
-		s.broadcastBoardStateRevealed(hub)
 	default:
 		s.broadcastBoardStatePerClient(hub)
 	}
@@ -31,11 +28,10 @@ func (s *GameServer) broadcastBoardState(hub *WSHub, viewerID int) {
 	board := hub.session.GetBoard()
 	field := board.GetField()
 
-	boardDTO := make([][]PieceDTO, 10)
-	for y := 0; y < 10; y++ {
-		boardDTO[y] = make([]PieceDTO, 10)
-		for x := 0; x < 10; x++ {
-			piece := field[y][x]
+	boardDTO := make([][]PieceDTO, len(field))
+	for y, row := range field {
+		boardDTO[y] = make([]PieceDTO, len(row))
+		for x, piece := range row {
 			if piece != nil && piece.IsAlive() {
 				dto := PieceToDTO(piece, viewerID)
 				dto.Position = PositionDTO{X: x, Y: y}
@@ -44,11 +40,7 @@ func (s *GameServer) broadcastBoardState(hub *WSHub, viewerID int) {
 		}
 	}
 
-	boardMsg := BoardStateMessage{
-		Board:  boardDTO,
-		Width:  10,
-		Height: 10,
-	}
+	boardMsg := newBoardStateMessage(board, boardDTO)
 
 	hub.BroadcastMessage(MsgTypeBoardState, boardMsg)
 }
@@ -101,35 +93,22 @@ func (s *GameServer) broadcastCombat(hub *WSHub, combat *game.CombatResult, game
 		DefenderDied: !defender.IsAlive(),
 	}
 
-	hub.BroadcastMessage(MsgTypeCombat, combatMsg)
-	log.Printf("Combat message sent: %+v", combatMsg)
-}
-
-// broadcastBoardStateRevealed sends board state with all pieces revealed (for AI vs AI spectating)
-func (s *GameServer) broadcastBoardStateRevealed(hub *WSHub) {
-	board := hub.session.GetBoard()
-	field := board.GetField()
-
-	boardDTO := make([][]PieceDTO, 10)
-	for y := 0; y < 10; y++ {
-		boardDTO[y] = make([]PieceDTO, 10)
-		for x := 0; x < 10; x++ {
-			piece := field[y][x]
-			if piece != nil && piece.IsAlive() {
-				// Force reveal all pieces for spectators
-				dto := PieceToDTO(piece, piece.GetOwner().GetID())
-				dto.Position = PositionDTO{X: x, Y: y}
-				dto.Revealed = true
-				boardDTO[y][x] = dto
+	// Silent defense: only the owner of the winning defender sees its rank, as on the board
+	if combat.DefenderHidden {
+		hiddenMsg := combatMsg
+		hiddenMsg.Defender = PieceToDTO(defender, -1)
+		hiddenMsg.Defender.Position = defenderDTO.Position
+		ownerID := defender.GetOwner().GetID()
+		hub.broadcastPerViewer(MsgTypeCombat, func(client *WSClient) any {
+			if client.viewerID == ownerID || hub.seesAll(client) {
+				return combatMsg
 			}
-		}
-	}
-
-	boardMsg := BoardStateMessage{
-		Board:  boardDTO,
-		Width:  10,
-		Height: 10,
+			return hiddenMsg
+		})
+		log.Printf("Combat message sent with hidden defender: %+v", hiddenMsg)
+		return
 	}
 
-	hub.BroadcastMessage(MsgTypeBoardState, boardMsg)
+	hub.BroadcastMessage(MsgTypeCombat, combatMsg)
+	log.Printf("Combat message sent: %+v", combatMsg)
 }
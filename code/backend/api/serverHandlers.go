@@ -3,7 +3,6 @@ package api
 import (
 	"digital-innovation/stratego/auth"
 	"digital-innovation/stratego/db"
-	"digital-innovation/stratego/game"
 	"digital-innovation/stratego/models"
 	"fmt"
 	"log"
@@ -17,11 +16,15 @@ import (
 
 // HandleCreateGame handles game creation
 // @Summary Create a new game
-// @Description Initialize a new game session with specified type and AIs
+// @Description Initialize a new game session with specified type, AIs and rule set
+// @Description ruleSet is one of classic (default), barrage, duel or duel-small (Duel on the small board); army and setupRows change its army or setup rows
+// @Description boardLayout is one of classic or small (8x8), board sets a custom size and lakes of at most 10x10 squares;
+// @Description both reduce the setup rows to what the board allows
+// @Description silentDefense, aggressorAdvantage and rescue turn on the special rules of the same name
 // @Tags games
 // @Accept json
 // @Produce json
-// @Param request body map[string]string true "Game creation details (id, type, ai1, ai2)"
+// @Param request body map[string]string true "Game creation details (id, type, ai1, ai2, allowSpectators, ruleSet, army, setupRows, boardLayout, board, silentDefense, aggressorAdvantage, rescue)"
 // @Success 201 {object} map[string]string "Game created"
 // @Failure 400 {object} map[string]string "Invalid request body"
 // @Router /games [post]
@@ -31,6 +34,20 @@ func (s *GameServer) HandleCreateGame(c *gin.Context) {
 		GameType string `json:"gameType"`
 		AI1      string `json:"ai1"`
 		AI2      string `json:"ai2"`
+
+		AllowSpectators *bool `json:"allowSpectators"` // defaults to true
+
+		RuleSet   string         `json:"ruleSet"`   // name of a built-in rule set, defaults to classic
+		Army      map[string]int `json:"army"`      // replaces the army of the rule set, pieces per rank
+		SetupRows int            `json:"setupRows"` // replaces the setup rows of the rule set
+
+		BoardLayout string              `json:"boardLayout"` // name of a built-in board layout, replaces the board of the rule set
+		Board       *models.BoardLayout `json:"board"`       // custom board size and lakes, replaces boardLayout
+
+		// Special rules turned on in addition to the rule set, see models.RuleSet
+		SilentDefense      bool `json:"silentDefense"`
+		AggressorAdvantage bool `json:"aggressorAdvantage"`
+		Rescue             bool `json:"rescue"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -53,7 +70,42 @@ func (s *GameServer) HandleCreateGame(c *gin.Context) {
 		req.GameType = models.HumanVsAi
 	}
 
-	handler, err := s.CreateGame(req.GameID, req.GameType, req.AI1, req.AI2)
+	rules, err := models.RuleSetByName(req.RuleSet)
+	if err != nil {
+		sendError(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Board != nil {
+		layout := *req.Board
+		if layout.Name == "" {
+			layout.Name = models.BoardLayoutCustom
+		}
+		rules = rules.WithBoard(layout)
+	} else if req.BoardLayout != "" {
+		layout, err := models.BoardLayoutByName(req.BoardLayout)
+		if err != nil {
+			sendError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rules = rules.WithBoard(layout)
+	}
+	if req.Army != nil || req.SetupRows != 0 {
+		rules.Name = models.RuleSetCustom
+		if req.Army != nil {
+			rules.Army = req.Army
+		}
+		if req.SetupRows != 0 {
+			rules.SetupRows = req.SetupRows
+		}
+	}
+	if req.SilentDefense || req.AggressorAdvantage || req.Rescue {
+		rules.Name = models.RuleSetCustom
+		rules.SilentDefense = rules.SilentDefense || req.SilentDefense
+		rules.AggressorAdvantage = rules.AggressorAdvantage || req.AggressorAdvantage
+		rules.Rescue = rules.Rescue || req.Rescue
+	}
+
+	handler, err := s.CreateGameWithRules(req.GameID, req.GameType, req.AI1, req.AI2, rules)
 	if err != nil {
 		sendError(c, err.Error(), http.StatusBadRequest)
 		return
@@ -63,24 +115,32 @@ func (s *GameServer) HandleCreateGame(c *gin.Context) {
 	if userID != -1 {
 		handler.Session.Player1UserID = &userID
 	}
+	if req.AllowSpectators != nil {
+		handler.AllowSpectators = *req.AllowSpectators
+	}
 
 	response := gin.H{
 		"gameId":   req.GameID,
 		"gameType": req.GameType,
 		"wsUrl":    fmt.Sprintf("/game/%s", req.GameID),
+		"rules":    rules,
 	}
 
 	sendJSON(c, response, http.StatusOK)
 
-	log.Printf("Created game %s (type: %s) by user %d", req.GameID, req.GameType, userID)
+	log.Printf("Created game %s (type: %s, rules: %s) by user %d", req.GameID, req.GameType, rules.Name, userID)
 }
 
 // HandleWebSocketConnection handles WebSocket connections
 // @Summary Game WebSocket
 // @Description Real-time game connection. Use `player` query param to join as 0 (Red), 1 (Blue), or anything else (Spectator)
+// @Description Spectators of games with human players receive the game with a delay
 // @Tags games
 // @Param gameID path string true "Game ID"
 // @Param player query string false "Player role (0, 1, or spec)"
+// @Param view query string false "Spectator view: neutral, red, blue or omniscient, by default omniscient in AI vs AI games and neutral otherwise; games with human players only offer neutral until they are over"
+// @Failure 400 {object} map[string]string "Unknown spectator view"
+// @Failure 403 {object} map[string]string "Not allowed to join as this player or with this view"
 // @Router /game/{gameID} [get]
 func (s *GameServer) HandleWebSocketConnection(c *gin.Context) {
 	gameID := c.Param("gameID")
@@ -136,9 +196,30 @@ func (s *GameServer) HandleWebSocketConnection(c *gin.Context) {
 		}
 	}
 
-	log.Printf("WebSocket connection for game %s (player %d, user %v)", gameID, playerID, currentUserID)
+	var view SpectatorView
+	if playerID < 0 {
+		v, status, err := spectatorView(handler, c.Query("view"))
+		if err != nil {
+			sendError(c, err.Error(), status)
+			return
+		}
+		view = v
+	}
 
-	HandleWebSocket(c.Writer, c.Request, handler.Session, handler.Hub, playerID)
+	log.Printf("WebSocket connection for game %s (player %d, view %s, user %v)", gameID, playerID, view, currentUserID)
+
+	HandleWebSocket(c.Writer, c.Request, handler.Session, handler.Hub, playerID, view)
+}
+
+// HandleSessionStats handles GET /games/sessions
+// @Summary Session counts
+// @Description Number of sessions in memory and of sessions removed since the server started
+// @Tags games
+// @Produce json
+// @Success 200 {object} api.SessionStats "Session counts"
+// @Router /games/sessions [get]
+func (s *GameServer) HandleSessionStats(c *gin.Context) {
+	sendJSON(c, s.SessionStats(), http.StatusOK)
 }
 
 // HandleListGames handles GET /games
@@ -167,7 +248,8 @@ func (s *GameServer) HandleListGames(c *gin.Context) {
 }
 
 // handleGameOver broadcasts final game state and saves stats
-func (s *GameServer) handleGameOver(session *game.GameSession, hub *WSHub) {
+func (s *GameServer) handleGameOver(handler *GameSessionHandler) {
+	session, hub := handler.Session, handler.Hub
 	hub.BroadcastGameState()
 
 	state := session.GetGameState()
@@ -189,19 +271,25 @@ func (s *GameServer) handleGameOver(session *game.GameSession, hub *WSHub) {
 
 	hub.BroadcastMessage(MsgTypeGameOver, gameOverMsg)
 
-	// Broadcast final board state with all pieces revealed
-	s.broadcastBoardStateRevealed(hub)
+	// Broadcast final board state, all pieces are revealed once the game is over
+	s.broadcastBoardStatePerClient(hub)
 	hub.BroadcastMoveHistory()
 
 	// Save game stats to database
-	go s.saveGameStats(session, winnerID, hub.gameType)
+	go s.saveGameStats(handler, winnerID)
 
-	// Wait longer before stopping monitoring so users can see results
-	time.Sleep(30 * time.Second)
+	// Wait longer before removing the session so users can see results
+	time.Sleep(s.finishedRetention)
 }
 
-// saveGameStats saves game statistics to the database
-func (s *GameServer) saveGameStats(session *game.GameSession, winnerID *int, gameType string) {
+// saveGameStats saves game statistics to the database and updates the ratings of the players
+func (s *GameServer) saveGameStats(handler *GameSessionHandler, winnerID *int) {
+	session := handler.Session
+	if db.DB == nil {
+		// e.g. in-process stress tests
+		log.Printf("No database connection, not saving game %s", session.ID)
+		return
+	}
 	duration := time.Since(session.StartTime).Seconds()
 	state := session.GetGameState()
 
@@ -209,7 +297,7 @@ func (s *GameServer) saveGameStats(session *game.GameSession, winnerID *int, gam
 	g := session.GetGame()
 	initialState := g.GetInitialBoardState()
 
-	if err := db.SaveGame(session.ID, session.Player1UserID, session.Player2UserID, gameType, initialState, winnerID); err != nil {
+	if err := db.SaveGame(session.ID, session.Player1UserID, session.Player2UserID, handler.GameType, g.Rules, initialState, winnerID); err != nil {
 		log.Printf("Failed to save game metadata for %s: %v", session.ID, err)
 	} else {
 		for _, m := range g.HistoricalHistory {
@@ -218,28 +306,65 @@ func (s *GameServer) saveGameStats(session *game.GameSession, winnerID *int, gam
 			}
 		}
 		log.Printf("Saved full history for game %s (%d moves)", session.ID, len(g.HistoricalHistory))
+		s.rateGame(handler, winnerID)
 	}
-	// Track stats for player 1 if they have a user ID
-	if session.Player1UserID != nil {
-		userID := *session.Player1UserID
-		won := winnerID != nil && *winnerID == 0
 
-		if err := db.UpdateUserStats(userID, won, state.MoveCount, duration); err != nil {
-			log.Printf("Failed to update stats for user %d: %v", userID, err)
+	// Track stats for the players that have a user ID
+	for seat, userID := range []*int{session.Player1UserID, session.Player2UserID} {
+		if userID == nil {
+			continue
+		}
+		result := models.GameResultFor(seat, winnerID)
+
+		if err := db.UpdateUserStats(*userID, result, state.MoveCount, duration); err != nil {
+			log.Printf("Failed to update stats for user %d: %v", *userID, err)
 		} else {
-			log.Printf("Updated stats for user %d (won=%v)", userID, won)
+			log.Printf("Updated stats for user %d (result=%s)", *userID, result)
 		}
 	}
+}
+
+// rateGame updates the ratings of both players of a stored game
+// Games with a guest and games of a user or an AI against itself are not rated
+func (s *GameServer) rateGame(handler *GameSessionHandler, winnerID *int) {
+	player1, player2 := ratedPlayers(handler)
+	if player1 == nil || player2 == nil || samePlayer(*player1, *player2) {
+		return
+	}
 
-	// Track stats for player 2 if they have a user ID
-	if session.Player2UserID != nil {
-		userID := *session.Player2UserID
-		won := winnerID != nil && *winnerID == 1
+	if err := db.RateGame(handler.Session.ID, *player1, *player2, winnerID); err != nil {
+		log.Printf("Failed to rate game %s: %v", handler.Session.ID, err)
+	}
+}
 
-		if err := db.UpdateUserStats(userID, won, state.MoveCount, duration); err != nil {
-			log.Printf("Failed to update stats for user %d: %v", userID, err)
-		} else {
-			log.Printf("Updated stats for user %d (won=%v)", userID, won)
+// samePlayer reports whether both seats are taken by the same user, or by the same AI
+func samePlayer(player1, player2 models.RatedPlayer) bool {
+	if player1.UserID != nil || player2.UserID != nil {
+		return player1.UserID != nil && player2.UserID != nil && *player1.UserID == *player2.UserID
+	}
+	return player1.AIName == player2.AIName
+}
+
+// ratedPlayers returns the rated players in both seats of a game, nil for a guest
+func ratedPlayers(handler *GameSessionHandler) (*models.RatedPlayer, *models.RatedPlayer) {
+	user := func(userID *int) *models.RatedPlayer {
+		if userID == nil {
+			return nil
 		}
+		return &models.RatedPlayer{UserID: userID}
+	}
+	ai := func(name string) *models.RatedPlayer {
+		return &models.RatedPlayer{AIName: name}
+	}
+
+	session := handler.Session
+	switch handler.GameType {
+	case models.HumanVsAi:
+		// The AI plays Blue, see newControllers
+		return user(session.Player1UserID), ai(handler.AI1)
+	case models.AiVsAi:
+		return ai(handler.AI1), ai(handler.AI2)
+	default:
+		return user(session.Player1UserID), user(session.Player2UserID)
 	}
 }
@@ -6,7 +6,14 @@ import (
 )
 
 // monitorGame watches for game events and broadcasts them
+// When the game is over or was stopped, the session is removed from the server
 func (s *GameServer) monitorGame(handler *GameSessionHandler, gameType string) {
+	finished := s.followGame(handler, gameType)
+	s.endSession(handler, finished)
+}
+
+// followGame broadcasts the game until it ends and reports whether it was played to the end
+func (s *GameServer) followGame(handler *GameSessionHandler, gameType string) bool {
 	session := handler.Session
 	hub := handler.Hub
 	log.Printf("Starting game monitor for %s (type: %s)", handler.Session.ID, gameType)
@@ -17,19 +24,33 @@ func (s *GameServer) monitorGame(handler *GameSessionHandler, gameType string) {
 
 	// WAIT IN SETUP PHASE - WebSocket handlers will broadcast when user acts
 	for session.IsSetupPhase() {
+		if session.IsStopped() {
+			log.Printf("GameMonitor %s: Stopped during setup", session.ID)
+			return false
+		}
 		time.Sleep(100 * time.Millisecond)
 	}
 
 	log.Printf("GameMonitor %s: Exiting setup phase, game starting", session.ID)
 
+	// Simulations are not resumed, a restored game is already stored
+	if !session.IsHeadless() && session.GetGameState().MoveCount == 0 {
+		s.saveSnapshot(handler.snapshot())
+	}
+
 	// NOW we enter the game loop
 	for {
 		// Wait for a move notification with timeout
 		if !session.WaitForMoveNotification(5 * time.Second) {
 			// Timeout - check if game is over
-			if !session.IsRunning() && session.GetGameState().IsGameOver {
-				s.handleGameOver(session, hub)
-				return
+			if session.HasEnded() {
+				if session.IsStopped() {
+					log.Printf("GameMonitor %s: Game was stopped", session.ID)
+					return false
+				}
+				// Also covers draws by the turn limit, which end the game without a final move
+				s.handleGameOver(handler)
+				return true
 			}
 			continue
 		}
@@ -45,8 +66,8 @@ func (s *GameServer) monitorGame(handler *GameSessionHandler, gameType string) {
 			state := session.GetGameState()
 			if state.IsGameOver {
 				time.Sleep(100 * time.Millisecond) // Brief delay to ensure runner completes
-				s.handleGameOver(session, hub)
-				return
+				s.handleGameOver(handler)
+				return true
 			}
 			continue
 		}
@@ -76,15 +97,17 @@ func (s *GameServer) monitorGame(handler *GameSessionHandler, gameType string) {
 			s.broadcastFullState(hub, gameType)
 		}
 
-		// Signal that move has been processed - GameRunner can continue
+		// Snapshot while the runner waits, then signal that move has been processed - GameRunner can continue
+		snapshot := handler.snapshot()
 		session.AckMoveProcessed()
+		s.saveSnapshot(snapshot)
 
 		// Check if game is over
 		state := session.GetGameState()
 		if state.IsGameOver {
 			time.Sleep(500 * time.Millisecond) // Brief delay before game over message
-			s.handleGameOver(session, hub)
-			return
+			s.handleGameOver(handler)
+			return true
 		}
 	}
 }
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"digital-innovation/stratego/db"
+	"digital-innovation/stratego/models"
+	"log"
+)
+
+// snapshot returns the state of the game to store, see game.GameSession.Snapshot
+func (h *GameSessionHandler) snapshot() models.GameSnapshot {
+	snapshot := h.Session.Snapshot()
+	snapshot.GameType = h.GameType
+	snapshot.AI1 = h.AI1
+	snapshot.AI2 = h.AI2
+	return snapshot
+}
+
+// saveSnapshot stores an in-progress game so it can be resumed after a restart
+func (s *GameServer) saveSnapshot(snapshot models.GameSnapshot) {
+	if db.DB == nil {
+		return
+	}
+	if err := db.SaveGameSnapshot(snapshot); err != nil {
+		log.Printf("Failed to save snapshot of game %s: %v", snapshot.GameID, err)
+	}
+}
+
+// dropSnapshot deletes the stored snapshot of a game that will not be resumed
+func (s *GameServer) dropSnapshot(gameID string) {
+	if db.DB == nil {
+		return
+	}
+	if err := db.DeleteGameSnapshot(gameID); err != nil {
+		log.Printf("Failed to delete snapshot of game %s: %v", gameID, err)
+	}
+}
@@ -135,3 +135,31 @@ func TestGetSessionNonExistent(t *testing.T) {
 		t.Error("Expected session to not exist for non-existent game ID")
 	}
 }
+
+func TestCreateGameWithRules(t *testing.T) {
+	server := api.NewGameServer()
+	handler, err := server.CreateGameWithRules("barrage-game", models.HumanVsAi, models.Fafo, "", models.BarrageRules())
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	defer handler.Session.Stop()
+	if rules := handler.Session.GetRules(); rules.Name != models.RuleSetBarrage {
+		t.Errorf("Expected a Barrage game, got rule set %s", rules.Name)
+	}
+
+	invalid := models.ClassicRules()
+	invalid.SetupRows = 2
+	if _, err := server.CreateGameWithRules("invalid-rules-game", models.HumanVsAi, models.Fafo, "", invalid); err == nil {
+		t.Error("Expected an error for an army that does not fit into the setup rows")
+	}
+
+	small, err := server.CreateGameWithRules("small-board-game", models.HumanVsAi, models.Fafo, "",
+		models.BarrageRules().WithBoard(models.SmallBoard()))
+	if err != nil {
+		t.Fatalf("Failed to create game on the small board: %v", err)
+	}
+	defer small.Session.Stop()
+	if board := small.Session.GetBoard(); board.GetWidth() != 8 || board.GetHeight() != 8 || len(board.GetLakes()) != 4 {
+		t.Errorf("Expected an 8x8 board with 4 lakes, got %dx%d with %d lakes", board.GetWidth(), board.GetHeight(), len(board.GetLakes()))
+	}
+}
@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"digital-innovation/stratego/api"
+	"digital-innovation/stratego/models"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// waitFor polls the condition until it holds or the timeout expires.
+func waitFor(timeout time.Duration, condition func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return condition()
+}
+
+// expectNoLeakedGoroutines fails the test if the goroutine count does not drop back to the baseline.
+func expectNoLeakedGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	if !waitFor(5*time.Second, func() bool { return runtime.NumGoroutine() <= baseline }) {
+		buf := make([]byte, 1<<16)
+		t.Errorf("Expected at most %d goroutines after the session was removed, got: %d\n%s",
+			baseline, runtime.NumGoroutine(), buf[:runtime.Stack(buf, true)])
+	}
+}
+
+func TestAbandonedSessionIsRemoved(t *testing.T) {
+	server := api.NewGameServer()
+	server.SetSessionTimeouts(50*time.Millisecond, 10*time.Millisecond)
+	baseline := runtime.NumGoroutine()
+
+	if _, err := server.CreateGame("abandoned-game", models.HumanVsAi, models.Fafo, models.Fafo); err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	if stats := server.SessionStats(); stats.Active != 1 {
+		t.Errorf("Expected 1 active session, got: %+v", stats)
+	}
+
+	// Nobody connects, so the session is abandoned
+	if !waitFor(5*time.Second, func() bool { return server.SessionStats().Active == 0 }) {
+		t.Fatal("Expected the session without clients to be removed")
+	}
+	if stats := server.SessionStats(); stats.Abandoned != 1 || stats.Finished != 0 {
+		t.Errorf("Expected 1 abandoned and 0 finished sessions, got: %+v", stats)
+	}
+	if _, exists := server.GetSession("abandoned-game"); exists {
+		t.Error("Expected GetSession to no longer find the removed session")
+	}
+	expectNoLeakedGoroutines(t, baseline)
+}
+
+func TestRemovedSessionDisconnectsClients(t *testing.T) {
+	server := api.NewGameServer()
+	server.SetSessionTimeouts(time.Minute, 10*time.Millisecond)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+	baseline := runtime.NumGoroutine()
+
+	handler, err := server.CreateGame("connected-game", models.HumanVsAi, models.Fafo, models.Fafo)
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/game/connected-game?player=0"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Expected no error connecting, got: %v", err)
+	}
+	defer conn.Close()
+	if !waitFor(time.Second, func() bool { return handler.Hub.ClientCount() == 1 }) {
+		t.Fatal("Expected the client to be registered")
+	}
+
+	handler.Session.Stop()
+
+	// The server closes the connection once the session is removed
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+				t.Errorf("Expected the server to close the connection, got: %v", err)
+			}
+			break
+		}
+	}
+
+	if stats := server.SessionStats(); stats.Active != 0 || stats.Abandoned != 1 {
+		t.Errorf("Expected the stopped session to be removed as abandoned, got: %+v", stats)
+	}
+	conn.Close()
+	expectNoLeakedGoroutines(t, baseline)
+}
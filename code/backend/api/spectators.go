@@ -0,0 +1,136 @@
+package api
+
+import (
+	"digital-innovation/stratego/models"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpectatorView is the perspective a spectator watches a game from
+type SpectatorView string
+
+const (
+	ViewNeutral    SpectatorView = "neutral"    // only revealed pieces, as neither player
+	ViewRed        SpectatorView = "red"        // the board as player 0 sees it
+	ViewBlue       SpectatorView = "blue"       // the board as player 1 sees it
+	ViewOmniscient SpectatorView = "omniscient" // all pieces, the default of AI vs AI games
+)
+
+// maxDelayedMessages limits the messages a delayed spectator can have pending before it is disconnected
+const maxDelayedMessages = 4096
+
+// ParseSpectatorView parses the view query parameter, empty means neutral
+func ParseSpectatorView(view string) (SpectatorView, error) {
+	switch v := SpectatorView(view); v {
+	case "":
+		return ViewNeutral, nil
+	case ViewNeutral, ViewRed, ViewBlue, ViewOmniscient:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown spectator view: %s", view)
+}
+
+// viewerID returns the player whose hidden pieces the view shows, -1 for none
+// The omniscient view needs no viewer: pieces are revealed to everyone once the game is over or in AI vs AI games
+func (v SpectatorView) viewerID() int {
+	switch v {
+	case ViewRed:
+		return 0
+	case ViewBlue:
+		return 1
+	}
+	return -1
+}
+
+// LiveGame is a game in the list of games spectators can join
+type LiveGame struct {
+	GameID           string `json:"gameId"`
+	GameType         string `json:"gameType"`
+	Round            int    `json:"round"`
+	MoveCount        int    `json:"moveCount"`
+	IsSetupPhase     bool   `json:"isSetupPhase"`
+	Spectators       int    `json:"spectators"`
+	SpectatorDelayMs int64  `json:"spectatorDelayMs"` // how far the broadcast to spectators lags behind the game
+	WsURL            string `json:"wsUrl"`
+}
+
+// SetSpectatorDelay sets how long the broadcast to spectators of games with human players is held back,
+// so spectators cannot pass on what they see to a player (ghosting). AI vs AI games are never delayed.
+// It only applies to games created afterwards
+func (s *GameServer) SetSpectatorDelay(delay time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.spectatorDelay = delay
+}
+
+// LiveGames returns the games that are in progress and open to spectators, most watched first
+func (s *GameServer) LiveGames() []LiveGame {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	games := make([]LiveGame, 0, len(s.sessions))
+	for gameID, handler := range s.sessions {
+		session := handler.Session
+		if !handler.AllowSpectators || session.IsHeadless() || session.HasEnded() {
+			continue
+		}
+		state := session.GetGameState()
+		if state.IsGameOver {
+			continue
+		}
+		games = append(games, LiveGame{
+			GameID:           gameID,
+			GameType:         handler.GameType,
+			Round:            state.Round,
+			MoveCount:        state.MoveCount,
+			IsSetupPhase:     state.IsSetupPhase,
+			Spectators:       handler.Hub.SpectatorCount(),
+			SpectatorDelayMs: handler.Hub.spectatorDelay.Milliseconds(),
+			WsURL:            fmt.Sprintf("/game/%s?player=spec", gameID),
+		})
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].Spectators != games[j].Spectators {
+			return games[i].Spectators > games[j].Spectators
+		}
+		return games[i].GameID < games[j].GameID
+	})
+	return games
+}
+
+// HandleListLiveGames handles GET /games/live
+// @Summary List live games
+// @Description Retrieve the games in progress that are open to spectators, most watched first
+// @Tags games
+// @Produce json
+// @Success 200 {array} api.LiveGame "Live games"
+// @Router /games/live [get]
+func (s *GameServer) HandleListLiveGames(c *gin.Context) {
+	sendJSON(c, s.LiveGames(), http.StatusOK)
+}
+
+// spectatorView checks whether a spectator may watch a game from a view, AI vs AI games are watched
+// with all pieces unless another view is asked for
+// The views other than neutral show hidden pieces, so in games with human players they are only available
+// once the game is over: the spectator delay does not keep a player from watching the pieces of the opponent
+func spectatorView(handler *GameSessionHandler, view string) (SpectatorView, int, error) {
+	if !handler.AllowSpectators {
+		return "", http.StatusForbidden, fmt.Errorf("game is not open to spectators")
+	}
+	if view == "" && handler.GameType == models.AiVsAi {
+		return ViewOmniscient, http.StatusOK, nil
+	}
+	v, err := ParseSpectatorView(view)
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+	if v != ViewNeutral && handler.GameType != models.AiVsAi && !handler.Session.GetGameState().IsGameOver {
+		return "", http.StatusForbidden, fmt.Errorf("the %s view is only available once the game is over", v)
+	}
+	return v, http.StatusOK, nil
+}
@@ -0,0 +1,252 @@
+package api_test
+
+import (
+	"digital-innovation/stratego/api"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestParseSpectatorView(t *testing.T) {
+	testCases := []struct {
+		view     string
+		expected api.SpectatorView
+		valid    bool
+	}{
+		{"", api.ViewNeutral, true},
+		{"neutral", api.ViewNeutral, true},
+		{"red", api.ViewRed, true},
+		{"blue", api.ViewBlue, true},
+		{"omniscient", api.ViewOmniscient, true},
+		{"green", "", false},
+	}
+
+	for _, tc := range testCases {
+		view, err := api.ParseSpectatorView(tc.view)
+		if (err == nil) != tc.valid || view != tc.expected {
+			t.Errorf("Expected %q to parse as %q (valid: %v), got: %q, %v", tc.view, tc.expected, tc.valid, view, err)
+		}
+	}
+}
+
+func TestLiveGamesListsGamesOpenToSpectators(t *testing.T) {
+	server := api.NewGameServer()
+	server.SetSpectatorDelay(2 * time.Second)
+	server.DisableRateLimit()
+
+	open, err := server.CreateGame("open-game", models.HumanVsAi, models.Fafo, "")
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	defer open.Session.Stop()
+	closed, err := server.CreateGame("closed-game", models.HumanVsAi, models.Fafo, "")
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	defer closed.Session.Stop()
+	closed.AllowSpectators = false
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/games/live", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got: %d %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+	var games []api.LiveGame
+	if err := json.Unmarshal(recorder.Body.Bytes(), &games); err != nil {
+		t.Fatalf("Expected a list of live games, got: %v", err)
+	}
+	if len(games) != 1 || games[0].GameID != "open-game" {
+		t.Fatalf("Expected only the open game to be listed, got: %+v", games)
+	}
+	if games[0].SpectatorDelayMs != 2000 || !games[0].IsSetupPhase || games[0].WsURL != "/game/open-game?player=spec" {
+		t.Errorf("Expected the open game in setup with a 2s delay, got: %+v", games[0])
+	}
+}
+
+// readMessage reads messages until one of the type arrives and decodes its data into v
+func readMessage(t *testing.T, conn *websocket.Conn, msgType string, v any) {
+	t.Helper()
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var msg struct {
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("Expected a %s message, got: %v", msgType, err)
+		}
+		if msg.Type == msgType {
+			if err := json.Unmarshal(msg.Data, v); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+	}
+}
+
+// readGameState reads messages until a game state arrives
+func readGameState(t *testing.T, conn *websocket.Conn) api.GameStateMessage {
+	t.Helper()
+	var state api.GameStateMessage
+	readMessage(t, conn, api.MsgTypeGameState, &state)
+	return state
+}
+
+func TestSpectatorsAreDelayedAndCounted(t *testing.T) {
+	server := api.NewGameServer()
+	server.SetSpectatorDelay(300 * time.Millisecond)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	handler, err := server.CreateGame("watched-game", models.HumanVsAi, models.Fafo, "")
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	defer handler.Session.Stop()
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/game/watched-game"
+
+	player, _, err := websocket.DefaultDialer.Dial(url+"?player=0", nil)
+	if err != nil {
+		t.Fatalf("Expected no error connecting as player, got: %v", err)
+	}
+	defer player.Close()
+	if state := readGameState(t, player); state.Spectators != 0 {
+		t.Errorf("Expected no spectators, got: %d", state.Spectators)
+	}
+
+	// These views would show the hidden pieces of a game in progress, also to a player watching the opponent
+	for _, view := range []string{"omniscient", "red", "blue"} {
+		_, resp, err := websocket.DefaultDialer.Dial(url+"?player=spec&view="+view, nil)
+		if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected the %s view to be forbidden during the game, got: %v", view, err)
+		}
+	}
+
+	start := time.Now()
+	spectator, _, err := websocket.DefaultDialer.Dial(url+"?player=spec&view=neutral", nil)
+	if err != nil {
+		t.Fatalf("Expected no error connecting as spectator, got: %v", err)
+	}
+	defer spectator.Close()
+
+	// Players see the spectator right away, the spectator only after the delay
+	if !waitFor(5*time.Second, func() bool { return readGameState(t, player).Spectators == 1 }) {
+		t.Error("Expected the player to see 1 spectator")
+	}
+	if state := readGameState(t, spectator); state.Spectators != 1 {
+		t.Errorf("Expected the spectator to see 1 spectator, got: %d", state.Spectators)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("Expected the spectator to receive the game after 300ms, got it after %v", elapsed)
+	}
+}
+
+func TestSpectatorViewsOfHiddenPieces(t *testing.T) {
+	server := api.NewGameServer()
+	server.SetSpectatorDelay(0)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	aiGame, err := server.CreateGame("ai-game", models.AiVsAi, models.Fafo, models.Fafo)
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	defer aiGame.Session.Stop()
+	humanGame, err := server.CreateGame("human-game", models.HumanVsHuman, "", "")
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	defer humanGame.Session.Stop()
+	g := humanGame.Session.GetGame()
+	g.SetWinner(g.Players[0], game.WinCauseFlagCaptured)
+
+	// AI vs AI games and games with human players that are over can be watched from every view
+	for _, gameID := range []string{"ai-game", "human-game"} {
+		for _, view := range []string{"red", "blue", "omniscient"} {
+			url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/game/" + gameID + "?player=spec&view=" + view
+			conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+			if err != nil {
+				t.Errorf("Expected the %s view of %s to be allowed, got: %v", view, gameID, err)
+				continue
+			}
+			conn.Close()
+		}
+	}
+}
+
+// hiddenRanks counts the pieces of a player on the board that are sent without their rank
+func hiddenRanks(board api.BoardStateMessage, playerID int) (hidden, total int) {
+	for _, row := range board.Board {
+		for _, piece := range row {
+			if piece.OwnerID != playerID {
+				continue
+			}
+			total++
+			if piece.Rank == "" {
+				hidden++
+			}
+		}
+	}
+	return hidden, total
+}
+
+func TestSpectatorViewsOfAIGame(t *testing.T) {
+	server := api.NewGameServer()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	handler, err := server.CreateGame("ai-view-game", models.AiVsAi, models.Fafo, models.Fafo)
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got: %v", err)
+	}
+	defer handler.Session.Stop()
+	if err := handler.Session.StartGameFromSetup(false); err != nil {
+		t.Fatalf("Expected no error starting game, got: %v", err)
+	}
+	handler.Session.Pause()
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/game/ai-view-game?player=spec"
+
+	testCases := []struct {
+		name                  string
+		query                 string
+		redHidden, blueHidden bool
+	}{
+		{"Default", "", false, false},
+		{"Omniscient", "&view=omniscient", false, false},
+		{"Red", "&view=red", false, true},
+		{"Blue", "&view=blue", true, false},
+		{"Neutral", "&view=neutral", true, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, _, err := websocket.DefaultDialer.Dial(url+tc.query, nil)
+			if err != nil {
+				t.Fatalf("Expected no error connecting as spectator, got: %v", err)
+			}
+			defer conn.Close()
+
+			var board api.BoardStateMessage
+			readMessage(t, conn, api.MsgTypeBoardState, &board)
+			// No piece was revealed by an attack yet, a hidden player has all ranks hidden
+			for playerID, expectHidden := range []bool{tc.redHidden, tc.blueHidden} {
+				hidden, total := hiddenRanks(board, playerID)
+				expected := 0
+				if expectHidden {
+					expected = total
+				}
+				if total == 0 || hidden != expected {
+					t.Errorf("Expected the ranks of player %d to be hidden: %v, got %d of %d hidden", playerID, expectHidden, hidden, total)
+				}
+			}
+		})
+	}
+}
@@ -33,7 +33,8 @@ var upgrader = websocket.Upgrader{
 }
 
 // HandleWebSocket handles WebSocket connections
-func HandleWebSocket(w http.ResponseWriter, r *http.Request, session *game.GameSession, hub *WSHub, seatIndex int) {
+// The view is only used for spectators, players see the board from their own seat
+func HandleWebSocket(w http.ResponseWriter, r *http.Request, session *game.GameSession, hub *WSHub, seatIndex int, view SpectatorView) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
@@ -45,10 +46,22 @@ func HandleWebSocket(w http.ResponseWriter, r *http.Request, session *game.GameS
 		send:      make(chan []byte, 256),
 		session:   session,
 		seatIndex: seatIndex,
+		viewerID:  seatIndex,
 		hub:       hub,
 	}
+	if seatIndex < 0 {
+		client.viewerID = view.viewerID()
+		client.allPieces = view == ViewOmniscient
+		client.delay = hub.spectatorDelay
+	}
 
-	hub.register <- client
+	select {
+	case hub.register <- client:
+	case <-hub.done:
+		// The session was removed while connecting
+		conn.Close()
+		return
+	}
 
 	go client.writePump()
 	go client.readPump()
@@ -13,14 +13,26 @@ type WSClient struct {
 	conn      *websocket.Conn
 	send      chan []byte
 	session   *game.GameSession
-	seatIndex int // -1 for spectator, 0 or 1 for player
+	seatIndex int  // -1 for spectator, 0 or 1 for player
+	viewerID  int  // player whose hidden pieces the client sees, the seat of a player or -1, see SpectatorView
+	allPieces bool // the client sees the hidden pieces of both players, see ViewOmniscient
 	hub       *WSHub
+	delay     time.Duration // how long messages are held back, only for spectators of games with human players
+}
+
+// delayedMessage is a message of a delayed client that is written once it is due
+type delayedMessage struct {
+	data []byte
+	due  time.Time
 }
 
 // readPump pumps messages from the websocket connection to the hub
 func (c *WSClient) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
 		c.conn.Close()
 	}()
 
@@ -51,33 +63,77 @@ func (c *WSClient) readPump() {
 }
 
 // writePump pumps messages from the hub to the websocket connection
+// Messages of a delayed client are queued in order and written once their delay has passed
 func (c *WSClient) writePump() {
 	ticker := time.NewTicker(54 * time.Second)
+	var pending []delayedMessage
+	delayTimer := time.NewTimer(0)
+	if !delayTimer.Stop() {
+		<-delayTimer.C
+	}
 	defer func() {
 		ticker.Stop()
+		delayTimer.Stop()
 		c.conn.Close()
 	}()
 
 	for {
 		select {
 		case message, ok := <-c.send:
-			err := c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err != nil {
-				log.Printf("Error setting write deadline: %v", err)
-				return
-			}
 			if !ok {
-				err := c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				err := c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+				if err != nil {
+					log.Printf("Error setting write deadline: %v", err)
+					return
+				}
+				err = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				if err != nil {
 					log.Printf("Error writing close message: %v", err)
 				}
 				return
 			}
 
-			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			if c.delay > 0 {
+				if len(pending) >= maxDelayedMessages {
+					log.Printf("WebSocket: Too many delayed messages for spectator, disconnecting")
+					return
+				}
+				pending = append(pending, delayedMessage{data: message, due: time.Now().Add(c.delay)})
+				if len(pending) == 1 {
+					delayTimer.Reset(c.delay)
+				}
+				continue
+			}
+
+			if err := c.write(message); err != nil {
 				return
 			}
 
+		case <-delayTimer.C:
+			for len(pending) > 0 && !time.Now().Before(pending[0].due) {
+				if err := c.write(pending[0].data); err != nil {
+					return
+				}
+				pending[0] = delayedMessage{} // release the message
+				pending = pending[1:]
+			}
+			if len(pending) > 0 {
+				delayTimer.Reset(time.Until(pending[0].due))
+			}
+
+		case <-c.hub.done:
+			// The session was removed from the server, closing the connection also ends readPump
+			err := c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err != nil {
+				log.Printf("Error setting write deadline: %v", err)
+				return
+			}
+			err = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "game closed"))
+			if err != nil {
+				log.Printf("Error writing close message: %v", err)
+			}
+			return
+
 		case <-ticker.C:
 			err := c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if err != nil {
@@ -90,3 +146,12 @@ func (c *WSClient) writePump() {
 		}
 	}
 }
+
+// write writes a text message to the connection
+func (c *WSClient) write(message []byte) error {
+	if err := c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		log.Printf("Error setting write deadline: %v", err)
+		return err
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, message)
+}
@@ -134,6 +134,10 @@ func (c *WSClient) handleGetValidMoves(data any) {
 
 // handleAnimationComplete processes animation complete message from client
 func (c *WSClient) handleAnimationComplete() {
+	if c.delay > 0 {
+		// A delayed spectator would cut the animation of a later combat short
+		return
+	}
 	log.Printf("Animation complete received from client %d", c.seatIndex)
 	c.session.SignalAnimationComplete()
 }
@@ -271,14 +275,16 @@ func (c *WSClient) handleLoadSetup(data interface{}) {
 		return
 	}
 
+	// One rank character per setup square, or those bytes in base64
+	cells := c.session.GetRules().SetupCells()
 	var setupData []byte
-	if len(loadMsg.SetupData) == 40 {
+	if len(loadMsg.SetupData) == cells {
 		setupData = []byte(loadMsg.SetupData)
 	} else {
 		var err error
 		setupData, err = base64.StdEncoding.DecodeString(loadMsg.SetupData)
 		if err != nil {
-			c.sendError(fmt.Sprintf("Invalid setup data (expected 40 chars or base64): %v", err))
+			c.sendError(fmt.Sprintf("Invalid setup data (expected %d chars or base64): %v", cells, err))
 			return
 		}
 	}
@@ -20,6 +20,10 @@ type WSHub struct {
 	cleanupTimer  *time.Timer
 	timerMutex    sync.Mutex
 	cleanupPeriod time.Duration
+	done          chan struct{} // closed by Stop
+	stopOnce      sync.Once
+
+	spectatorDelay time.Duration // how long messages to spectators are held back, see GameServer.SetSpectatorDelay
 }
 
 func NewWSHub(session *game.GameSession, gameType string) *WSHub {
@@ -31,14 +35,22 @@ func NewWSHub(session *game.GameSession, gameType string) *WSHub {
 		session:       session,
 		gameType:      gameType,
 		cleanupPeriod: 1 * time.Minute, // 1 minute grace period for reconnection
+		done:          make(chan struct{}),
 	}
 }
 
 // Run starts the hub's main loop
 // If user disconnects, the hub will stop the game after 1 minute, if human is playing
+// Run returns after Stop, the client pumps exit on their own
 func (h *WSHub) Run() {
 	for {
 		select {
+		case <-h.done:
+			h.mutex.Lock()
+			clear(h.clients)
+			h.mutex.Unlock()
+			return
+
 		case client := <-h.register:
 			h.mutex.Lock()
 			h.clients[client] = true
@@ -50,6 +62,10 @@ func (h *WSHub) Run() {
 			}
 
 			go h.sendGameState(client)
+			if client.seatIndex < 0 {
+				// Update the spectator count of everyone else
+				go h.BroadcastGameState()
+			}
 
 		case client := <-h.unregister:
 			h.mutex.Lock()
@@ -60,9 +76,19 @@ func (h *WSHub) Run() {
 			clientCount := len(h.clients)
 			h.mutex.Unlock()
 
+			if client.seatIndex < 0 && clientCount > 0 {
+				go h.BroadcastGameState()
+			}
+
 			if clientCount == 0 {
 				switch h.gameType {
 				case models.AiVsAi:
+					if h.session.IsSetupPhase() {
+						// Nothing runs yet, allow a reconnect as for human games
+						log.Printf("WSHub: All clients disconnected from AI vs AI game in setup, starting cleanup timer")
+						h.startCleanupTimer()
+						break
+					}
 					// Stop AI vs AI games immediately - no point running without observers
 					log.Printf("WSHub: All clients disconnected from AI vs AI game, stopping game immediately")
 					h.session.Stop()
@@ -80,7 +106,8 @@ func (h *WSHub) Run() {
 			}
 
 		case message := <-h.broadcast:
-			h.mutex.RLock()
+			// Slow clients are removed, which needs the write lock
+			h.mutex.Lock()
 			for client := range h.clients {
 				select {
 				case client.send <- message:
@@ -90,11 +117,45 @@ func (h *WSHub) Run() {
 					delete(h.clients, client)
 				}
 			}
-			h.mutex.RUnlock()
+			h.mutex.Unlock()
 		}
 	}
 }
 
+// seesAll reports whether the client sees the hidden pieces of both players:
+// omniscient spectators, and everyone once the game is over
+func (h *WSHub) seesAll(client *WSClient) bool {
+	return client.allPieces || h.session.GetGameState().IsGameOver
+}
+
+// Stop ends the hub loop and disconnects all clients, it is safe to call more than once
+func (h *WSHub) Stop() {
+	h.stopOnce.Do(func() {
+		h.cancelCleanupTimer()
+		close(h.done)
+	})
+}
+
+// ClientCount returns the number of connected clients
+func (h *WSHub) ClientCount() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.clients)
+}
+
+// SpectatorCount returns the number of connected spectators
+func (h *WSHub) SpectatorCount() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	count := 0
+	for client := range h.clients {
+		if client.seatIndex < 0 {
+			count++
+		}
+	}
+	return count
+}
+
 // startCleanupTimer starts a timer to stop the game after the cleanup period
 func (h *WSHub) startCleanupTimer() {
 	h.timerMutex.Lock()
@@ -126,58 +187,43 @@ func (h *WSHub) cancelCleanupTimer() {
 	}
 }
 
-func (h *WSHub) setupBoard() BoardStateMessage {
+// setupBoard returns the setups of both players as the client sees them
+func (h *WSHub) setupBoard(client *WSClient) BoardStateMessage {
 	session := h.session
+	board := session.GetBoard()
 
-	boardDTO := make([][]PieceDTO, 10)
-	for y := range 10 {
-		boardDTO[y] = make([]PieceDTO, 10)
+	boardDTO := make([][]PieceDTO, board.GetHeight())
+	for y := range boardDTO {
+		boardDTO[y] = make([]PieceDTO, board.GetWidth())
 	}
 
-	// Place player 1 pieces in setup area (rows 6-9)
-	player1Pieces := session.GetSetupPieces(0)
-	idx := 0
-	for y := 6; y <= 9; y++ {
-		for x := range 10 {
-			if idx < len(player1Pieces) {
-				piece := player1Pieces[idx]
-				dto := PieceToDTO(piece, 0) // Player 0 can see their own pieces
-				if h.gameType == models.AiVsAi {
-					dto.Revealed = true // Force visibility for spectators during setup
-				}
-				dto.Position = PositionDTO{X: x, Y: y}
-				boardDTO[y][x] = dto
-				idx++
-			}
+	// Place the setups in the setup rows of the rule set, rows 6-9 and 0-3 in the classic game
+	// Hide opponent pieces during setup
+	rules := session.GetRules()
+	for playerID := range 2 {
+		viewerID := client.viewerID
+		if client.allPieces {
+			viewerID = playerID // Omniscient spectators of AI vs AI games see and edit both setups
 		}
-	}
 
-	// Place player 2 pieces in setup area (rows 0-3)
-	// Hide opponent pieces during setup
-	player2Pieces := session.GetSetupPieces(1)
-	idx = 0
-	for y := 0; y <= 3; y++ {
-		for x := range 10 {
-			if idx < len(player2Pieces) {
-				piece := player2Pieces[idx]
-				viewerID := -1
-				if h.gameType == models.AiVsAi {
-					viewerID = 1 // Show all pieces in AI vs AI
-				}
-				dto := PieceToDTO(piece, viewerID) // Hide pieces
-				if h.gameType == models.AiVsAi {
-					dto.Revealed = true // Force visibility for spectators during setup
+		pieces := session.GetSetupPieces(playerID)
+		startRow, endRow := game.SetupRowRange(rules, playerID)
+		idx := 0
+		for y := startRow; y <= endRow; y++ {
+			for x := range board.GetWidth() {
+				if idx < len(pieces) {
+					piece := pieces[idx]
+					dto := PieceToDTO(piece, viewerID)
+					if client.allPieces && piece != nil {
+						dto.Revealed = true // Force visibility for spectators during setup
+					}
+					dto.Position = PositionDTO{X: x, Y: y}
+					boardDTO[y][x] = dto
+					idx++
 				}
-				dto.Position = PositionDTO{X: x, Y: y}
-				boardDTO[y][x] = dto
-				idx++
 			}
 		}
 	}
 
-	return BoardStateMessage{
-		Board:  boardDTO,
-		Width:  10,
-		Height: 10,
-	}
+	return newBoardStateMessage(board, boardDTO)
 }
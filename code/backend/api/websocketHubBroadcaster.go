@@ -1,9 +1,9 @@
 package api
 
 import (
-	"digital-innovation/stratego/models"
 	"encoding/json"
 	"log"
+	"time"
 )
 
 // BroadcastMessage sends a message to all connected clients
@@ -19,18 +19,52 @@ func (h *WSHub) BroadcastMessage(msgType string, data any) {
 		return
 	}
 
-	h.broadcast <- jsonData
+	select {
+	case h.broadcast <- jsonData:
+	case <-h.done:
+		// Nobody is listening anymore
+	}
+}
+
+// broadcastPerViewer sends every client the message as it sees the game,
+// for messages that must not reveal a piece to everyone
+func (h *WSHub) broadcastPerViewer(msgType string, dataFor func(client *WSClient) any) {
+	h.mutex.RLock()
+	clients := make([]*WSClient, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mutex.RUnlock()
+
+	for _, client := range clients {
+		jsonData, err := json.Marshal(WSMessage{Type: msgType, Data: dataFor(client)})
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			return
+		}
+
+		select {
+		case client.send <- jsonData:
+		case <-time.After(time.Second):
+			log.Printf("Timeout sending %s to client", msgType)
+		}
+	}
 }
 
 // BroadcastSetupBoard sends the setup board state to all clients
 func (h *WSHub) BroadcastSetupBoard() {
-	boardMsg := h.setupBoard()
-
-	h.BroadcastMessage(MsgTypeBoardState, boardMsg)
+	h.broadcastPerViewer(MsgTypeBoardState, func(client *WSClient) any {
+		return h.setupBoard(client)
+	})
 }
 
 // BroadcastGameState broadcasts the current game state to all clients
 func (h *WSHub) BroadcastGameState() {
+	h.BroadcastMessage(MsgTypeGameState, h.gameStateMessage())
+}
+
+// gameStateMessage returns the current game state, which is the same for all clients
+func (h *WSHub) gameStateMessage() GameStateMessage {
 	state := h.session.GetGameState()
 
 	var winnerName string
@@ -43,7 +77,7 @@ func (h *WSHub) BroadcastGameState() {
 		winCause = string(h.session.GetWinCause())
 	}
 
-	h.BroadcastMessage(MsgTypeGameState, GameStateMessage{
+	return GameStateMessage{
 		Round:              state.Round,
 		CurrentPlayerID:    state.CurrentPlayerID,
 		CurrentPlayerName:  state.CurrentPlayerName,
@@ -60,7 +94,8 @@ func (h *WSHub) BroadcastGameState() {
 		Player2AlivePieces: state.Player2AlivePieces,
 		IsSetupPhase:       state.IsSetupPhase,
 		Headless:           state.Headless,
-	})
+		Spectators:         h.SpectatorCount(),
+	}
 }
 
 // BroadcastGameTransition broadcasts complete state after setup phase ends
@@ -69,11 +104,7 @@ func (h *WSHub) BroadcastGameTransition() {
 	h.BroadcastGameState()
 
 	// Broadcast board state (pieces are now on the board)
-	if h.gameType == models.AiVsAi {
-		h.broadcastBoardStateRevealed()
-	} else {
-		h.broadcastBoardStatePerClient()
-	}
+	h.broadcastBoardStatePerClient()
 }
 
 // broadcastBoardStatePerClient sends personalized board to each client
@@ -90,34 +121,6 @@ func (h *WSHub) broadcastBoardStatePerClient() {
 	}
 }
 
-// broadcastBoardStateRevealed sends board with all pieces revealed
-func (h *WSHub) broadcastBoardStateRevealed() {
-	board := h.session.GetBoard()
-	field := board.GetField()
-
-	boardDTO := make([][]PieceDTO, 10)
-	for y := 0; y < 10; y++ {
-		boardDTO[y] = make([]PieceDTO, 10)
-		for x := 0; x < 10; x++ {
-			piece := field[y][x]
-			if piece != nil && piece.IsAlive() {
-				dto := PieceToDTO(piece, piece.GetOwner().GetID())
-				dto.Position = PositionDTO{X: x, Y: y}
-				dto.Revealed = true
-				boardDTO[y][x] = dto
-			}
-		}
-	}
-
-	boardMsg := BoardStateMessage{
-		Board:  boardDTO,
-		Width:  10,
-		Height: 10,
-	}
-
-	h.BroadcastMessage(MsgTypeBoardState, boardMsg)
-}
-
 // BroadcastMoveHistory sends personalized move history to each client
 func (h *WSHub) BroadcastMoveHistory() {
 	h.mutex.RLock()
@@ -9,35 +9,7 @@ import (
 
 // sendGameState sends the current game state to a specific client
 func (h *WSHub) sendGameState(client *WSClient) {
-	state := h.session.GetGameState()
-
-	var winnerName string
-	var winCause string
-	if state.WinnerID != nil {
-		winner := h.session.GetWinner()
-		if winner != nil {
-			winnerName = winner.GetName()
-		}
-		winCause = string(h.session.GetWinCause())
-	}
-
-	stateMsg := GameStateMessage{
-		Round:              state.Round,
-		CurrentPlayerID:    state.CurrentPlayerID,
-		CurrentPlayerName:  state.CurrentPlayerName,
-		IsGameOver:         state.IsGameOver,
-		WinnerID:           state.WinnerID,
-		WinnerName:         winnerName,
-		WinCause:           winCause,
-		Player1Score:       state.Player1Score,
-		Player2Score:       state.Player2Score,
-		WaitingForInput:    state.WaitingForInput,
-		Paused:             state.Paused,
-		MoveCount:          state.MoveCount,
-		Player1AlivePieces: state.Player1AlivePieces,
-		Player2AlivePieces: state.Player2AlivePieces,
-		IsSetupPhase:       state.IsSetupPhase,
-	}
+	stateMsg := h.gameStateMessage()
 
 	msg := WSMessage{
 		Type: MsgTypeGameState,
@@ -58,7 +30,7 @@ func (h *WSHub) sendGameState(client *WSClient) {
 
 	h.sendBoardState(client)
 
-	if !state.IsSetupPhase {
+	if !stateMsg.IsSetupPhase {
 		h.sendMoveHistory(client)
 	}
 }
@@ -72,17 +44,17 @@ func (h *WSHub) sendBoardState(client *WSClient) {
 
 	board := h.session.GetBoard()
 	field := board.GetField()
+	seesAll := h.seesAll(client)
 
-	boardDTO := make([][]PieceDTO, 10)
-	for y := range 10 {
-		boardDTO[y] = make([]PieceDTO, 10)
-		for x := range 10 {
+	boardDTO := make([][]PieceDTO, len(field))
+	for y, row := range field {
+		boardDTO[y] = make([]PieceDTO, len(row))
+		for x, piece := range row {
 			boardDTO[y][x] = PieceDTO{OwnerID: -1}
-			piece := field[y][x]
 			if piece != nil {
-				dto := PieceToDTO(piece, client.seatIndex)
-				// Force reveal all pieces for AI vs AI spectators or when game is over
-				if h.gameType == models.AiVsAi || h.session.GetGameState().IsGameOver {
+				dto := PieceToDTO(piece, client.viewerID)
+				// Force reveal all pieces for omniscient spectators or when game is over
+				if seesAll {
 					pieceType := piece.GetType()
 					dto.Type = pieceType.GetName()
 					dto.Rank = string(pieceType.GetRank())
@@ -99,16 +71,12 @@ func (h *WSHub) sendBoardState(client *WSClient) {
 	var filteredLastMove *models.HistoricalMove
 	if lastMove != nil {
 		// For the board state's LastMove, we don't force filter combat so the visualization works
-		fm := h.filterHistoricalMove(*lastMove, client.seatIndex, false)
+		fm := h.filterHistoricalMove(*lastMove, client, false)
 		filteredLastMove = &fm
 	}
 
-	boardMsg := BoardStateMessage{
-		Board:    boardDTO,
-		Width:    10,
-		Height:   10,
-		LastMove: filteredLastMove,
-	}
+	boardMsg := newBoardStateMessage(board, boardDTO)
+	boardMsg.LastMove = filteredLastMove
 
 	msg := WSMessage{
 		Type: MsgTypeBoardState,
@@ -130,7 +98,7 @@ func (h *WSHub) sendBoardState(client *WSClient) {
 
 // sendSetupBoard sends the setup board state to a specific client
 func (h *WSHub) sendSetupBoard(client *WSClient) {
-	boardMsg := h.setupBoard()
+	boardMsg := h.setupBoard(client)
 	msg := WSMessage{
 		Type: MsgTypeBoardState,
 		Data: boardMsg,
@@ -159,18 +127,18 @@ func (h *WSHub) sendMoveHistory(client *WSClient) {
 		moveDTOs[i] = MoveToDTO(move)
 	}
 
-	// Filter history if not AI vs AI and game is not over
+	// Filter history unless the client sees all pieces
 	fullHistory := g.HistoricalHistory
 	initialState := g.InitialState
 
-	if h.gameType != models.AiVsAi && !g.IsGameOver() {
+	if !h.seesAll(client) {
 		// Filter initial state
 		initialState = make([][]models.PieceData, len(g.InitialState))
 		for y, row := range g.InitialState {
 			initialState[y] = make([]models.PieceData, len(row))
 			for x, piece := range row {
 				p := piece
-				if p.OwnerID != client.seatIndex && p.OwnerID != -1 && p.Type != "" {
+				if p.OwnerID != client.viewerID && p.OwnerID != -1 && p.Type != "" {
 					p.Type = ""
 					p.Rank = ""
 				}
@@ -181,7 +149,7 @@ func (h *WSHub) sendMoveHistory(client *WSClient) {
 		fullHistory = make([]models.HistoricalMove, len(g.HistoricalHistory))
 		for i, m := range g.HistoricalHistory {
 			// For history, we force filter combat to prevent leaking piece ranks in a live game
-			fullHistory[i] = h.filterHistoricalMove(m, client.seatIndex, true)
+			fullHistory[i] = h.filterHistoricalMove(m, client, true)
 		}
 	}
 
@@ -209,13 +177,14 @@ func (h *WSHub) sendMoveHistory(client *WSClient) {
 	}
 }
 
-func (h *WSHub) filterHistoricalMove(m models.HistoricalMove, seatIndex int, forceFilterCombat bool) models.HistoricalMove {
-	if h.gameType == models.AiVsAi || h.session.GetGameState().IsGameOver {
+func (h *WSHub) filterHistoricalMove(m models.HistoricalMove, client *WSClient, forceFilterCombat bool) models.HistoricalMove {
+	if h.seesAll(client) {
 		return m
 	}
+	viewerID := client.viewerID
 
 	move := m
-	if move.Attacker != nil && move.Attacker.OwnerID != seatIndex {
+	if move.Attacker != nil && move.Attacker.OwnerID != viewerID {
 		if move.Result == models.ResultMove || forceFilterCombat {
 			move.Attacker = &models.PieceData{
 				OwnerID: move.Attacker.OwnerID,
@@ -224,8 +193,9 @@ func (h *WSHub) filterHistoricalMove(m models.HistoricalMove, seatIndex int, for
 			}
 		}
 	}
-	if move.Defender != nil && move.Defender.OwnerID != seatIndex {
-		if move.Result == models.ResultMove || forceFilterCombat {
+	if move.Defender != nil && move.Defender.OwnerID != viewerID {
+		// A defender that won under silent defense stays hidden from everyone but its owner
+		if move.Result == models.ResultMove || forceFilterCombat || move.DefenderHidden {
 			move.Defender = &models.PieceData{
 				OwnerID: move.Defender.OwnerID,
 				Type:    "",
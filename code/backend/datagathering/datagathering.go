@@ -1 +1,203 @@
+// Package datagathering turns finished games, from AI vs AI runs, the database or game archives, into training samples.
+// Every move of a game gives one sample: the board as the player to move sees it, the move they chose
+// and how the game ended for them.
 package datagathering
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"fmt"
+)
+
+// Sample is one position of a game from the view of the player to move.
+//
+// Board uses the cell layout of engine.CompactBoard, indexed y*10+x also on smaller boards,
+// with color 0 for player 0 and 1 for player 1.
+// Enemy pieces keep the occupied, color and moved bits, but their piece type is 0 unless it was revealed in combat.
+// A revealed rank stays known for the rest of the game, like a player who remembers it.
+// A defender that won under silent defense is not revealed, and a piece brought back by the rescue rule is.
+type Sample struct {
+	Board     [100]byte `json:"board"`
+	Player    int       `json:"player"` // 0 or 1, the player to move
+	MoveIndex int       `json:"moveIndex"`
+	From      uint8     `json:"from"`    // square index y*10+x
+	To        uint8     `json:"to"`      // square index y*10+x
+	Outcome   int8      `json:"outcome"` // 1 if the player won the game, -1 if they lost, 0 for a draw
+}
+
+// Game is a finished game with everything needed to replay it.
+// Player IDs in the initial state and the moves are 0 and 1.
+type Game struct {
+	InitialState [][]models.PieceData
+	Moves        []models.HistoricalMove
+	WinnerID     *int           // nil for a draw
+	Rules        models.RuleSet // only the special rules are used to replay the moves, the zero value plays classic combat
+}
+
+// FromRecord returns the game of an AI vs AI record.
+func FromRecord(record models.AiGameRecord) Game {
+	return Game{
+		InitialState: record.InitialState,
+		Moves:        record.History,
+		WinnerID:     record.WinnerID,
+		Rules:        record.Rules,
+	}
+}
+
+// FromHistory returns the game of a history stored in the database.
+func FromHistory(history *models.GameHistory) Game {
+	return Game{
+		InitialState: history.InitialState,
+		Moves:        history.Moves,
+		WinnerID:     history.WinnerID,
+		Rules:        history.Rules,
+	}
+}
+
+// FromGame returns a game played or replayed by the engine, which must know its initial state.
+func FromGame(g *game.Game) Game {
+	return Game{
+		InitialState: g.InitialState,
+		Moves:        g.HistoricalHistory,
+		WinnerID:     g.GetWinnerID(),
+		Rules:        g.Rules,
+	}
+}
+
+// moveOutcomes maps the recorded result of a move to the combat outcome of the replay.
+var moveOutcomes = map[models.MoveResultType]engine.CombatOutcome{
+	models.ResultMove:    engine.CombatNone,
+	models.ResultWin:     engine.CombatAttackerWon,
+	models.ResultLoss:    engine.CombatDefenderWon,
+	models.ResultTie:     engine.CombatBothLost,
+	models.ResultCapture: engine.CombatFlagCaptured,
+}
+
+// Samples replays the game and returns a sample for every move.
+// It returns an error if the initial state or a move does not fit the replayed board.
+func (g Game) Samples() ([]Sample, error) {
+	board, err := initialBoard(g.InitialState)
+	if err != nil {
+		return nil, err
+	}
+
+	var known [100]bool // squares whose piece has been revealed in combat
+	samples := make([]Sample, 0, len(g.Moves))
+	for i, m := range g.Moves {
+		if m.PlayerID != 0 && m.PlayerID != 1 {
+			return nil, fmt.Errorf("move %d: invalid player %d", i, m.PlayerID)
+		}
+		from, err := square(m.FromX, m.FromY)
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", i, err)
+		}
+		to, err := square(m.ToX, m.ToY)
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", i, err)
+		}
+		color := byte(m.PlayerID)
+		if board.PieceID(from) == 0 || board.Color(from) != color {
+			return nil, fmt.Errorf("move %d: no piece of player %d at (%d,%d)", i, m.PlayerID, m.FromX, m.FromY)
+		}
+
+		samples = append(samples, Sample{
+			Board:     visibleBoard(&board, &known, color),
+			Player:    m.PlayerID,
+			MoveIndex: i,
+			From:      from,
+			To:        to,
+			Outcome:   outcome(g.WinnerID, m.PlayerID),
+		})
+
+		result := board.ApplyWithRules(engine.CompactMove{From: from, To: to}, g.Rules)
+		if expected, ok := moveOutcomes[m.Result]; ok && expected != result {
+			return nil, fmt.Errorf("move %d: recorded result %s does not match the replay", i, m.Result)
+		}
+		switch result {
+		case engine.CombatNone:
+			known[to] = known[from]
+		case engine.CombatBothLost:
+			known[to] = false
+		case engine.CombatDefenderWon:
+			known[to] = known[to] || !m.DefenderHidden
+		default:
+			known[to] = true // the survivor of a combat is revealed
+		}
+		known[from] = false
+
+		if m.Rescue != nil {
+			rescued, err := square(m.Rescue.X, m.Rescue.Y)
+			if err != nil {
+				return nil, fmt.Errorf("move %d: %w", i, err)
+			}
+			rank := m.Rescue.Piece.Rank
+			id, ok := byte(0), false
+			if len(rank) == 1 {
+				id, ok = engine.GetPieceIDFromRank(rank[0])
+			}
+			if !ok || board.PieceID(rescued) != 0 {
+				return nil, fmt.Errorf("move %d: rescue of %q at (%d,%d) does not fit the replay", i, m.Rescue.Piece.Rank, m.Rescue.X, m.Rescue.Y)
+			}
+			board.SetPiece(rescued, id, color, false)
+			known[rescued] = true
+		}
+	}
+	return samples, nil
+}
+
+// initialBoard converts an initial state to a compact board.
+func initialBoard(state [][]models.PieceData) (engine.CompactBoard, error) {
+	var board engine.CompactBoard
+	if len(state) == 0 || len(state) > models.MaxBoardSize {
+		return board, fmt.Errorf("initial state must have 1 to %d rows, got %d", models.MaxBoardSize, len(state))
+	}
+	for y, row := range state {
+		if len(row) > models.MaxBoardSize {
+			return board, fmt.Errorf("row %d of the initial state must have at most %d squares, got %d", y, models.MaxBoardSize, len(row))
+		}
+		for x, piece := range row {
+			if piece.Rank == "" {
+				continue
+			}
+			id, ok := engine.GetPieceIDFromRank(piece.Rank[0])
+			if !ok {
+				return board, fmt.Errorf("invalid rank %q at (%d,%d)", piece.Rank, x, y)
+			}
+			if piece.OwnerID != 0 && piece.OwnerID != 1 {
+				return board, fmt.Errorf("invalid owner %d at (%d,%d)", piece.OwnerID, x, y)
+			}
+			board.SetPiece(engine.SquareIndex(engine.NewPosition(x, y)), id, byte(piece.OwnerID), false)
+		}
+	}
+	return board, nil
+}
+
+// visibleBoard returns the board as the player with the given color sees it.
+func visibleBoard(board *engine.CompactBoard, known *[100]bool, color byte) [100]byte {
+	visible := [100]byte(*board)
+	for i, cell := range visible {
+		if cell&engine.BitOccupied != 0 && board.Color(uint8(i)) != color && !known[i] {
+			visible[i] = cell &^ engine.MaskPieceType
+		}
+	}
+	return visible
+}
+
+func square(x, y int) (uint8, error) {
+	if x < 0 || x >= models.MaxBoardSize || y < 0 || y >= models.MaxBoardSize {
+		return 0, fmt.Errorf("position (%d,%d) is out of bounds", x, y)
+	}
+	return engine.SquareIndex(engine.NewPosition(x, y)), nil
+}
+
+func outcome(winnerID *int, player int) int8 {
+	switch {
+	case winnerID == nil:
+		return 0
+	case *winnerID == player:
+		return 1
+	default:
+		return -1
+	}
+}
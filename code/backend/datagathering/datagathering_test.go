@@ -0,0 +1,187 @@
+package datagathering_test
+
+import (
+	"bytes"
+	AIhandler "digital-innovation/stratego/ai/handler"
+	"digital-innovation/stratego/datagathering"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// smallGame is a game with a few pieces in which player 1's scout attacks player 0's marshal and loses.
+func smallGame() datagathering.Game {
+	state := make([][]models.PieceData, 10)
+	for y := range state {
+		state[y] = make([]models.PieceData, 10)
+		for x := range state[y] {
+			state[y][x] = models.PieceData{OwnerID: -1}
+		}
+	}
+	state[6][0] = models.PieceData{Type: "Marshal", Rank: "M", OwnerID: 0}
+	state[9][9] = models.PieceData{Type: "Flag", Rank: "0", OwnerID: 0}
+	state[4][0] = models.PieceData{Type: "Scout", Rank: "2", OwnerID: 1}
+	state[0][5] = models.PieceData{Type: "Sergeant", Rank: "4", OwnerID: 1}
+	state[0][9] = models.PieceData{Type: "Flag", Rank: "0", OwnerID: 1}
+
+	moves := []models.HistoricalMove{
+		{PlayerID: 0, FromX: 0, FromY: 6, ToX: 0, ToY: 5, Result: models.ResultMove},
+		{PlayerID: 1, FromX: 0, FromY: 4, ToX: 1, ToY: 4, Result: models.ResultMove},
+		{PlayerID: 0, FromX: 0, FromY: 5, ToX: 0, ToY: 4, Result: models.ResultMove},
+		{PlayerID: 1, FromX: 1, FromY: 4, ToX: 0, ToY: 4, Result: models.ResultLoss},
+		{PlayerID: 0, FromX: 0, FromY: 4, ToX: 0, ToY: 3, Result: models.ResultMove},
+		{PlayerID: 1, FromX: 5, FromY: 0, ToX: 5, ToY: 1, Result: models.ResultMove},
+	}
+	winner := 0
+	return datagathering.Game{InitialState: state, Moves: moves, WinnerID: &winner}
+}
+
+func pieceID(cell byte) byte {
+	return (cell & engine.MaskPieceType) >> engine.ShiftPieceType
+}
+
+func TestSamplesHideUnknownEnemyPieces(t *testing.T) {
+	samples, err := smallGame().Samples()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(samples) != 6 {
+		t.Fatalf("Expected 6 samples, got: %d", len(samples))
+	}
+
+	first := samples[0]
+	if pieceID(first.Board[60]) != engine.PieceIDMarshal {
+		t.Errorf("Expected the own marshal to be visible, got cell %08b", first.Board[60])
+	}
+	if first.Board[40]&engine.BitOccupied == 0 || pieceID(first.Board[40]) != 0 {
+		t.Errorf("Expected the enemy scout to be occupied but hidden, got cell %08b", first.Board[40])
+	}
+	if first.From != 60 || first.To != 50 || first.Player != 0 || first.Outcome != 1 {
+		t.Errorf("Expected move 60 to 50 of the winning player 0, got: %+v", first)
+	}
+
+	second := samples[1]
+	if second.Outcome != -1 || second.Player != 1 {
+		t.Errorf("Expected a sample of the losing player 1, got: %+v", second)
+	}
+	if pieceID(second.Board[50]) != 0 || second.Board[50]&engine.BitMoved == 0 {
+		t.Errorf("Expected the enemy marshal to be hidden and moved, got cell %08b", second.Board[50])
+	}
+
+	// The marshal won the combat of move 3, so player 1 knows it from then on
+	last := samples[5]
+	if pieceID(last.Board[30]) != engine.PieceIDMarshal {
+		t.Errorf("Expected the revealed marshal to stay visible after moving, got cell %08b", last.Board[30])
+	}
+	if last.Board[40] != 0 || last.Board[41] != 0 {
+		t.Errorf("Expected the scout to be removed, got cells %08b and %08b", last.Board[40], last.Board[41])
+	}
+}
+
+func TestSamplesWithSpecialRules(t *testing.T) {
+	g := smallGame()
+	g.Moves[3].DefenderHidden = true // silent defense: the marshal beats the scout without being revealed
+	g.Moves[5].Rescue = &models.Rescue{Piece: models.PieceData{Type: "Scout", Rank: "2", OwnerID: 1}, X: 0, Y: 0}
+	g.Moves = append(g.Moves, models.HistoricalMove{PlayerID: 0, FromX: 0, FromY: 3, ToX: 0, ToY: 2, Result: models.ResultMove})
+
+	samples, err := g.Samples()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cell := samples[5].Board[30]; pieceID(cell) != 0 || cell&engine.BitOccupied == 0 {
+		t.Errorf("Expected the silently defending marshal to stay hidden, got cell %08b", cell)
+	}
+	if cell := samples[6].Board[0]; pieceID(cell) != engine.PieceIDScout {
+		t.Errorf("Expected the rescued scout to be visible, got cell %08b", cell)
+	}
+
+	g.Moves[5].Rescue.X = 9 // the square of the flag
+	if _, err := g.Samples(); err == nil {
+		t.Errorf("Expected an error for a rescue onto an occupied square")
+	}
+}
+
+func TestSamplesRejectInconsistentGames(t *testing.T) {
+	g := smallGame()
+	g.Moves[3].Result = models.ResultWin
+	if _, err := g.Samples(); err == nil {
+		t.Errorf("Expected an error for a result that does not match the replay")
+	}
+
+	g = smallGame()
+	g.Moves[1].PlayerID = 0
+	if _, err := g.Samples(); err == nil {
+		t.Errorf("Expected an error for a move of an enemy piece")
+	}
+}
+
+func TestSamplesReplayFullGame(t *testing.T) {
+	player1 := engine.NewPlayer(0, "player 1", "red")
+	player2 := engine.NewPlayer(1, "player 2", "blue")
+	g := game.QuickStart(AIhandler.CreateAI(models.Fafo, &player1), AIhandler.CreateAI(models.Fafo, &player2))
+	initial := g.GetInitialBoardState()
+	winner := game.NewGameRunner(g, 0, 1000).RunToCompletion(false)
+
+	record := datagathering.Game{InitialState: initial, Moves: g.HistoricalHistory}
+	if winner != nil {
+		winnerID := winner.GetID()
+		record.WinnerID = &winnerID
+	}
+	samples, err := record.Samples()
+	if err != nil {
+		t.Fatalf("Expected the game to replay, got: %v", err)
+	}
+	if len(samples) != len(g.HistoricalHistory) {
+		t.Errorf("Expected a sample for each of the %d moves, got: %d", len(g.HistoricalHistory), len(samples))
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	samples, err := smallGame().Samples()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	writer := datagathering.NewBinaryWriter(&buffer)
+	if err := datagathering.WriteGame(writer, smallGame()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	read, err := datagathering.ReadBinary(&buffer)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(read) != len(samples) {
+		t.Fatalf("Expected %d samples, got: %d", len(samples), len(read))
+	}
+	for i := range samples {
+		if read[i] != samples[i] {
+			t.Errorf("Expected sample %d to be %+v, got: %+v", i, samples[i], read[i])
+		}
+	}
+
+	if _, err := datagathering.ReadBinary(strings.NewReader("nope")); !errors.Is(err, datagathering.ErrInvalidDataset) {
+		t.Errorf("Expected ErrInvalidDataset, got: %v", err)
+	}
+}
+
+func TestNDJSONWritesOneSamplePerLine(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := datagathering.NewNDJSONWriter(&buffer)
+	if err := datagathering.WriteGame(writer, smallGame()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if lines := strings.Count(buffer.String(), "\n"); lines != 6 {
+		t.Errorf("Expected 6 lines, got: %d", lines)
+	}
+}
@@ -0,0 +1,37 @@
+package datagathering
+
+import (
+	"digital-innovation/stratego/db"
+	"log"
+)
+
+// ExportStoredGames writes the samples of all finished games in the database.
+// Games that cannot be loaded or replayed are logged and skipped.
+// It returns the number of exported games.
+func ExportStoredGames(w Writer) (int, error) {
+	ids, err := db.GetFinishedGameIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	for _, id := range ids {
+		history, err := db.GetGameHistory(id)
+		if err != nil {
+			log.Printf("Skipping game %s: %v", id, err)
+			continue
+		}
+		samples, err := FromHistory(history).Samples()
+		if err != nil {
+			log.Printf("Skipping game %s: %v", id, err)
+			continue
+		}
+		for _, sample := range samples {
+			if err := w.Write(sample); err != nil {
+				return exported, err
+			}
+		}
+		exported++
+	}
+	return exported, w.Flush()
+}
@@ -0,0 +1,128 @@
+package datagathering
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Writer writes samples in one of the dataset formats.
+type Writer interface {
+	Write(sample Sample) error
+	// Flush writes any buffered samples to the underlying writer.
+	Flush() error
+}
+
+// WriteGame writes a sample for every move of the game.
+func WriteGame(w Writer, g Game) error {
+	samples, err := g.Samples()
+	if err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		if err := w.Write(sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter returns a writer of one JSON sample per line.
+func NewNDJSONWriter(w io.Writer) Writer {
+	buffer := bufio.NewWriter(w)
+	return &ndjsonWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (w *ndjsonWriter) Write(sample Sample) error {
+	return w.encoder.Encode(sample)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return w.buffer.Flush()
+}
+
+// Binary format: a header of binaryMagic and binaryVersion, followed by fixed-size records of
+// 100 board bytes | player | from | to | outcome (int8) | move index (uint16, little endian)
+var binaryMagic = [4]byte{'S', 'T', 'R', 'S'}
+
+const (
+	binaryVersion    = 1
+	binaryRecordSize = 106
+)
+
+var ErrInvalidDataset = errors.New("invalid binary dataset")
+
+type binaryWriter struct {
+	buffer *bufio.Writer
+	record [binaryRecordSize]byte
+}
+
+// NewBinaryWriter returns a writer of the compact binary format. The header is written right away,
+// so a dataset without samples is still valid.
+func NewBinaryWriter(w io.Writer) Writer {
+	buffer := bufio.NewWriter(w)
+	// Errors of a bufio.Writer are sticky, a failed header is returned by the next Write or Flush
+	_, _ = buffer.Write(binaryMagic[:])
+	_ = buffer.WriteByte(binaryVersion)
+	return &binaryWriter{buffer: buffer}
+}
+
+func (w *binaryWriter) Write(sample Sample) error {
+	if sample.MoveIndex < 0 || sample.MoveIndex > 0xFFFF {
+		return fmt.Errorf("move index %d does not fit the binary format", sample.MoveIndex)
+	}
+	copy(w.record[:100], sample.Board[:])
+	w.record[100] = byte(sample.Player)
+	w.record[101] = sample.From
+	w.record[102] = sample.To
+	w.record[103] = byte(sample.Outcome)
+	binary.LittleEndian.PutUint16(w.record[104:], uint16(sample.MoveIndex))
+	_, err := w.buffer.Write(w.record[:])
+	return err
+}
+
+func (w *binaryWriter) Flush() error {
+	return w.buffer.Flush()
+}
+
+// ReadBinary reads all samples of a dataset in the binary format.
+func ReadBinary(r io.Reader) ([]Sample, error) {
+	reader := bufio.NewReader(r)
+	var header [5]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidDataset)
+	}
+	if !bytes.Equal(header[:4], binaryMagic[:]) || header[4] != binaryVersion {
+		return nil, fmt.Errorf("%w: unknown header %q", ErrInvalidDataset, header)
+	}
+
+	var samples []Sample
+	var record [binaryRecordSize]byte
+	for {
+		_, err := io.ReadFull(reader, record[:])
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: truncated record %d", ErrInvalidDataset, len(samples))
+		}
+
+		var sample Sample
+		copy(sample.Board[:], record[:100])
+		sample.Player = int(record[100])
+		sample.From = record[101]
+		sample.To = record[102]
+		sample.Outcome = int8(record[103])
+		sample.MoveIndex = int(binary.LittleEndian.Uint16(record[104:]))
+		samples = append(samples, sample)
+	}
+}
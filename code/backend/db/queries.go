@@ -5,6 +5,7 @@ import (
 	"digital-innovation/stratego/models"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -108,26 +109,31 @@ func GetUserStats(userID int) (*models.UserStats, error) {
 	return &stats, nil
 }
 
-// UpdateUserStats updates game statistics for a user
-func UpdateUserStats(userID int, won bool, moveCount int, durationSecs float64) error {
+// UpdateUserStats updates game statistics for a user, result is models.GameResultWin, GameResultLoss or GameResultDraw
+func UpdateUserStats(userID int, result string, moveCount int, durationSecs float64) error {
 	query := `
 		UPDATE user_stats
 		SET total_games = total_games + 1,
 		    wins = wins + $1,
 		    losses = losses + $2,
-		    total_moves = total_moves + $3,
-		    avg_game_duration_seconds = (avg_game_duration_seconds * total_games + $4) / (total_games + 1)
-		WHERE user_id = $5
+		    draws = draws + $3,
+		    total_moves = total_moves + $4,
+		    avg_game_duration_seconds = (avg_game_duration_seconds * total_games + $5) / (total_games + 1)
+		WHERE user_id = $6
 	`
 	winsInc := 0
 	lossesInc := 0
-	if won {
+	drawsInc := 0
+	switch result {
+	case models.GameResultWin:
 		winsInc = 1
-	} else {
+	case models.GameResultLoss:
 		lossesInc = 1
+	default:
+		drawsInc = 1
 	}
 
-	_, err := DB.Exec(query, winsInc, lossesInc, moveCount, durationSecs, userID)
+	_, err := DB.Exec(query, winsInc, lossesInc, drawsInc, moveCount, durationSecs, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update user stats: %w", err)
 	}
@@ -247,26 +253,53 @@ func DeleteBoardSetup(setupID, userID int) error {
 	return nil
 }
 
-// SaveGame persists the game metadata and initial state
-func SaveGame(gameID string, p1ID, p2ID *int, gameType string, initialState interface{}, winnerID *int) error {
+// SaveGame persists the game metadata, rule set and initial state of a finished game
+// A game that was snapshotted while in progress is marked finished and its snapshot is dropped
+func SaveGame(gameID string, p1ID, p2ID *int, gameType string, rules models.RuleSet, initialState interface{}, winnerID *int) error {
 	stateJSON, err := json.Marshal(initialState)
 	if err != nil {
 		return fmt.Errorf("failed to marshal initial state: %w", err)
 	}
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule set: %w", err)
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
 
 	query := `
-		INSERT INTO games (id, player1_user_id, player2_user_id, winner_id, game_type, initial_state, finished_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO games (id, player1_user_id, player2_user_id, winner_id, game_type, rules, initial_state, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE
+		SET player1_user_id = EXCLUDED.player1_user_id,
+		    player2_user_id = EXCLUDED.player2_user_id,
+		    winner_id = EXCLUDED.winner_id,
+		    finished_at = EXCLUDED.finished_at
 	`
-	_, err = DB.Exec(query, gameID, p1ID, p2ID, winnerID, gameType, stateJSON, time.Now())
-	if err != nil {
+	if _, err := tx.Exec(query, gameID, p1ID, p2ID, winnerID, gameType, rulesJSON, stateJSON, time.Now()); err != nil {
 		return fmt.Errorf("failed to save game: %w", err)
 	}
-	return nil
+	if _, err := tx.Exec(`DELETE FROM game_snapshots WHERE game_id = $1`, gameID); err != nil {
+		return fmt.Errorf("failed to delete game snapshot: %w", err)
+	}
+	return tx.Commit()
+}
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
 }
 
-// SaveMove persists a single move in a game's history
+// SaveMove persists a single move in a game's history, a move that is already stored is skipped
 func SaveMove(gameID string, move models.HistoricalMove) error {
+	return insertMove(DB, gameID, move)
+}
+
+func insertMove(exec execer, gameID string, move models.HistoricalMove) error {
 	attackerJSON, err := json.Marshal(move.Attacker)
 	if err != nil {
 		return fmt.Errorf("failed to marshal attacker data: %w", err)
@@ -275,40 +308,199 @@ func SaveMove(gameID string, move models.HistoricalMove) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal defender data: %w", err)
 	}
+	var rescueJSON []byte // NULL without a rescue
+	if move.Rescue != nil {
+		rescueJSON, err = json.Marshal(move.Rescue)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rescue data: %w", err)
+		}
+	}
 
 	query := `
-		INSERT INTO game_moves (game_id, move_index, player_id, from_x, from_y, to_x, to_y, attacker_data, defender_data, result)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO game_moves (game_id, move_index, player_id, from_x, from_y, to_x, to_y, attacker_data, defender_data, result,
+		                        defender_hidden, rescue_data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (game_id, move_index) DO NOTHING
 	`
-	_, err = DB.Exec(query, gameID, move.MoveIndex, move.PlayerID,
+	_, err = exec.Exec(query, gameID, move.MoveIndex, move.PlayerID,
 		move.FromX, move.FromY, move.ToX, move.ToY,
-		attackerJSON, defenderJSON, move.Result)
+		attackerJSON, defenderJSON, move.Result, move.DefenderHidden, rescueJSON)
 	if err != nil {
 		return fmt.Errorf("failed to save move: %w", err)
 	}
 	return nil
 }
+
+// SaveGameSnapshot persists an in-progress game: the game row without finished_at, the moves that are not stored yet
+// and the state after the last move
+func SaveGameSnapshot(snapshot models.GameSnapshot) error {
+	stateJSON, err := json.Marshal(snapshot.InitialState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal initial state: %w", err)
+	}
+	revealedJSON, err := json.Marshal(snapshot.Revealed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revealed squares: %w", err)
+	}
+	rulesJSON, err := json.Marshal(snapshot.Rules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule set: %w", err)
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	query := `
+		INSERT INTO games (id, player1_user_id, player2_user_id, game_type, rules, initial_state, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE
+		SET player1_user_id = EXCLUDED.player1_user_id,
+		    player2_user_id = EXCLUDED.player2_user_id
+	`
+	_, err = tx.Exec(query, snapshot.GameID, snapshot.Player1UserID, snapshot.Player2UserID,
+		snapshot.GameType, rulesJSON, stateJSON, snapshot.StartTime)
+	if err != nil {
+		return fmt.Errorf("failed to save game: %w", err)
+	}
+
+	var stored int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM game_moves WHERE game_id = $1`, snapshot.GameID).Scan(&stored)
+	if err != nil {
+		return fmt.Errorf("failed to count stored moves: %w", err)
+	}
+	for _, move := range snapshot.Moves[min(stored, len(snapshot.Moves)):] {
+		if err := insertMove(tx, snapshot.GameID, move); err != nil {
+			return err
+		}
+	}
+
+	query = `
+		INSERT INTO game_snapshots (game_id, ai1, ai2, board, current_player_id, round, revealed, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (game_id) DO UPDATE
+		SET board = EXCLUDED.board,
+		    current_player_id = EXCLUDED.current_player_id,
+		    round = EXCLUDED.round,
+		    revealed = EXCLUDED.revealed,
+		    updated_at = EXCLUDED.updated_at
+	`
+	_, err = tx.Exec(query, snapshot.GameID, snapshot.AI1, snapshot.AI2, snapshot.Board,
+		snapshot.CurrentPlayerID, snapshot.Round, revealedJSON, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save game snapshot: %w", err)
+	}
+	return tx.Commit()
+}
+
+// DeleteGameSnapshot drops the snapshot of a game so it is not resumed, the game and its moves stay as abandoned
+func DeleteGameSnapshot(gameID string) error {
+	if _, err := DB.Exec(`DELETE FROM game_snapshots WHERE game_id = $1`, gameID); err != nil {
+		return fmt.Errorf("failed to delete game snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetGameSnapshots returns the snapshots of all games that are still in progress, with their moves
+func GetGameSnapshots() ([]models.GameSnapshot, error) {
+	query := `
+		SELECT g.id, g.game_type, g.rules, g.player1_user_id, g.player2_user_id, g.initial_state, g.created_at,
+		       s.ai1, s.ai2, s.board, s.current_player_id, s.round, s.revealed
+		FROM game_snapshots s
+		JOIN games g ON g.id = s.game_id
+		WHERE g.finished_at IS NULL
+		ORDER BY g.created_at ASC
+	`
+	rows, err := DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query game snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.GameSnapshot
+	for rows.Next() {
+		var s models.GameSnapshot
+		var ai1, ai2 sql.NullString
+		var rulesJSON, initialStateJSON, revealedJSON []byte
+		err := rows.Scan(&s.GameID, &s.GameType, &rulesJSON, &s.Player1UserID, &s.Player2UserID, &initialStateJSON, &s.StartTime,
+			&ai1, &ai2, &s.Board, &s.CurrentPlayerID, &s.Round, &revealedJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game snapshot: %w", err)
+		}
+		s.AI1, s.AI2 = ai1.String, ai2.String
+		if s.Rules, err = unmarshalRules(rulesJSON); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rule set of %s: %w", s.GameID, err)
+		}
+		if err := json.Unmarshal(initialStateJSON, &s.InitialState); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal initial state of %s: %w", s.GameID, err)
+		}
+		if err := json.Unmarshal(revealedJSON, &s.Revealed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal revealed squares of %s: %w", s.GameID, err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range snapshots {
+		if snapshots[i].Moves, err = getGameMoves(snapshots[i].GameID); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+// GetGameHistory returns the initial state and moves of a finished game
+// Games in progress are left out, their history would give away the setups
 func GetGameHistory(gameID string) (*models.GameHistory, error) {
 	var history models.GameHistory
 	history.GameID = gameID
 
-	var initialStateJSON []byte
+	var rulesJSON, initialStateJSON []byte
 	query := `
-		SELECT initial_state, winner_id
+		SELECT rules, initial_state, winner_id
 		FROM games
-		WHERE id = $1
+		WHERE id = $1 AND finished_at IS NOT NULL
 	`
-	err := DB.QueryRow(query, gameID).Scan(&initialStateJSON, &history.WinnerID)
+	err := DB.QueryRow(query, gameID).Scan(&rulesJSON, &initialStateJSON, &history.WinnerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get game history metadata: %w", err)
 	}
 
+	if history.Rules, err = unmarshalRules(rulesJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule set: %w", err)
+	}
+
 	if err := json.Unmarshal(initialStateJSON, &history.InitialState); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal initial state: %w", err)
 	}
 
-	query = `
-		SELECT move_index, player_id, from_x, from_y, to_x, to_y, attacker_data, defender_data, result
+	history.Moves, err = getGameMoves(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// unmarshalRules returns the stored rule set of a game, the classic rules for games stored before rule sets existed
+func unmarshalRules(data []byte) (models.RuleSet, error) {
+	if data == nil {
+		return models.ClassicRules(), nil
+	}
+	var rules models.RuleSet
+	err := json.Unmarshal(data, &rules)
+	return rules, err
+}
+
+// getGameMoves returns the stored moves of a game in order
+func getGameMoves(gameID string) ([]models.HistoricalMove, error) {
+	query := `
+		SELECT move_index, player_id, from_x, from_y, to_x, to_y, attacker_data, defender_data, result,
+		       defender_hidden, rescue_data
 		FROM game_moves
 		WHERE game_id = $1
 		ORDER BY move_index ASC
@@ -319,10 +511,12 @@ func GetGameHistory(gameID string) (*models.GameHistory, error) {
 	}
 	defer rows.Close()
 
+	var moves []models.HistoricalMove
 	for rows.Next() {
 		var m models.HistoricalMove
-		var attackerJSON, defenderJSON []byte
-		err = rows.Scan(&m.MoveIndex, &m.PlayerID, &m.FromX, &m.FromY, &m.ToX, &m.ToY, &attackerJSON, &defenderJSON, &m.Result)
+		var attackerJSON, defenderJSON, rescueJSON []byte
+		err = rows.Scan(&m.MoveIndex, &m.PlayerID, &m.FromX, &m.FromY, &m.ToX, &m.ToY, &attackerJSON, &defenderJSON, &m.Result,
+			&m.DefenderHidden, &rescueJSON)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan historical move: %w", err)
 		}
@@ -337,9 +531,108 @@ func GetGameHistory(gameID string) (*models.GameHistory, error) {
 				return nil, fmt.Errorf("failed to unmarshal defender data: %w", err)
 			}
 		}
+		if len(rescueJSON) > 0 {
+			if err := json.Unmarshal(rescueJSON, &m.Rescue); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal rescue data: %w", err)
+			}
+		}
 
-		history.Moves = append(history.Moves, m)
+		moves = append(moves, m)
 	}
+	return moves, rows.Err()
+}
 
-	return &history, nil
+// GetUserGames returns a page of the finished games of a user, newest first, and the number of games matching the filter
+func GetUserGames(userID int, filter models.UserGamesFilter) ([]models.UserGame, int, error) {
+	// The seat of the user, also used to derive the result
+	seat := `CASE WHEN g.player1_user_id = $1 THEN 0 ELSE 1 END`
+	conditions := []string{
+		"g.finished_at IS NOT NULL",
+		"(g.player1_user_id = $1 OR g.player2_user_id = $1)",
+	}
+	args := []any{userID}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.GameType != "" {
+		addCondition("g.game_type = $%d", filter.GameType)
+	}
+	switch filter.Result {
+	case models.GameResultWin:
+		conditions = append(conditions, "g.winner_id = "+seat)
+	case models.GameResultLoss:
+		conditions = append(conditions, "g.winner_id <> "+seat)
+	case models.GameResultDraw:
+		conditions = append(conditions, "g.winner_id IS NULL")
+	}
+	if filter.From != nil {
+		addCondition("g.finished_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("g.finished_at < $%d", *filter.To)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := DB.QueryRow(`SELECT COUNT(*) FROM games g WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count user games: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT g.id, g.game_type, %[1]s, o.id, o.username, g.winner_id,
+		       (SELECT COUNT(*) FROM game_moves m WHERE m.game_id = g.id), g.created_at, g.finished_at
+		FROM games g
+		LEFT JOIN users o ON o.id = CASE WHEN g.player1_user_id = $1 THEN g.player2_user_id ELSE g.player1_user_id END
+		WHERE %[2]s
+		ORDER BY g.finished_at DESC, g.id
+		LIMIT $%[3]d OFFSET $%[4]d
+	`, seat, where, len(args)-1, len(args))
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query user games: %w", err)
+	}
+	defer rows.Close()
+
+	games := []models.UserGame{}
+	for rows.Next() {
+		var game models.UserGame
+		var opponentName sql.NullString
+		err := rows.Scan(&game.GameID, &game.GameType, &game.PlayerID, &game.OpponentID, &opponentName, &game.WinnerID,
+			&game.MoveCount, &game.CreatedAt, &game.FinishedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user game: %w", err)
+		}
+		game.OpponentName = opponentName.String
+		game.Result = models.GameResultFor(game.PlayerID, game.WinnerID)
+		games = append(games, game)
+	}
+	return games, total, rows.Err()
+}
+
+// GetFinishedGameIDs returns the IDs of all finished games, oldest first
+func GetFinishedGameIDs() ([]string, error) {
+	query := `
+		SELECT id
+		FROM games
+		WHERE finished_at IS NOT NULL
+		ORDER BY finished_at ASC
+	`
+	rows, err := DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query finished games: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan game id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
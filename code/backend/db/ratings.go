@@ -0,0 +1,239 @@
+package db
+
+import (
+	"database/sql"
+	"digital-innovation/stratego/models"
+	"digital-innovation/stratego/rating"
+	"errors"
+	"fmt"
+)
+
+// RateGame updates the ratings of both players of a finished game and records the changes in the rating history
+// Players without a rating start at the default rating, a game that was already rated is skipped
+func RateGame(gameID string, player1, player2 models.RatedPlayer, winnerID *int) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	var rated bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM rating_history WHERE game_id = $1)`, gameID).Scan(&rated); err != nil {
+		return fmt.Errorf("failed to check rating history: %w", err)
+	}
+	if rated {
+		return nil
+	}
+
+	id1, err := ensureRating(tx, player1)
+	if err != nil {
+		return err
+	}
+	id2, err := ensureRating(tx, player2)
+	if err != nil {
+		return err
+	}
+
+	// Lock in ID order, so games of the same players rated at the same time cannot deadlock
+	rows, err := tx.Query(`SELECT id, rating, deviation FROM ratings WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`, id1, id2)
+	if err != nil {
+		return fmt.Errorf("failed to lock ratings: %w", err)
+	}
+	before := make(map[int]rating.Rating, 2)
+	for rows.Next() {
+		var id int
+		var r rating.Rating
+		if err := rows.Scan(&id, &r.Value, &r.Deviation); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan rating: %w", err)
+		}
+		before[id] = r
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read ratings: %w", err)
+	}
+
+	score := rating.Draw
+	if winnerID != nil {
+		score = rating.Win
+		if *winnerID == 1 {
+			score = rating.Loss
+		}
+	}
+	after1, after2 := rating.Update(before[id1], before[id2], score)
+
+	if err := saveRatingChange(tx, gameID, id1, id2, models.GameResultFor(0, winnerID), before[id1], after1); err != nil {
+		return err
+	}
+	if err := saveRatingChange(tx, gameID, id2, id1, models.GameResultFor(1, winnerID), before[id2], after2); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensureRating returns the ID of the rating of a player, creating it with the default rating if it does not exist
+func ensureRating(tx *sql.Tx, player models.RatedPlayer) (int, error) {
+	var aiName *string
+	if player.UserID == nil {
+		aiName = &player.AIName
+	}
+
+	query := `
+		INSERT INTO ratings (user_id, ai_name, rating, deviation)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(query, player.UserID, aiName, rating.DefaultRating, rating.DefaultDeviation); err != nil {
+		return 0, fmt.Errorf("failed to create rating: %w", err)
+	}
+
+	var id int
+	if err := tx.QueryRow(`SELECT id FROM ratings WHERE user_id = $1 OR ai_name = $2`, player.UserID, aiName).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get rating: %w", err)
+	}
+	return id, nil
+}
+
+// saveRatingChange stores the new rating of a player and records the change
+func saveRatingChange(tx *sql.Tx, gameID string, ratingID, opponentRatingID int, result string, before, after rating.Rating) error {
+	query := `
+		UPDATE ratings
+		SET rating = $1, deviation = $2, games_played = games_played + 1, updated_at = now()
+		WHERE id = $3
+	`
+	if _, err := tx.Exec(query, after.Value, after.Deviation, ratingID); err != nil {
+		return fmt.Errorf("failed to update rating: %w", err)
+	}
+
+	query = `
+		INSERT INTO rating_history (rating_id, opponent_rating_id, game_id, result,
+		                            rating_before, rating_after, deviation_before, deviation_after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.Exec(query, ratingID, opponentRatingID, gameID, result, before.Value, after.Value, before.Deviation, after.Deviation)
+	if err != nil {
+		return fmt.Errorf("failed to save rating history: %w", err)
+	}
+	return nil
+}
+
+// GetUserRating returns the rating of a user, the default rating if they have not played a rated game
+func GetUserRating(userID int) (float64, error) {
+	var value float64
+	err := DB.QueryRow(`SELECT rating FROM ratings WHERE user_id = $1`, userID).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rating.DefaultRating, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user rating: %w", err)
+	}
+	return value, nil
+}
+
+// ratingColumns selects a models.Rating from ratings r joined with users u, see scanRating
+const ratingColumns = `
+	CASE WHEN r.user_id IS NULL THEN 'ai' ELSE 'user' END, r.user_id, COALESCE(u.username, r.ai_name),
+	r.rating, r.deviation, r.games_played, r.updated_at`
+
+func scanRating(row interface{ Scan(dest ...any) error }, r *models.Rating) error {
+	return row.Scan(&r.PlayerType, &r.UserID, &r.Name, &r.Rating, &r.Deviation, &r.GamesPlayed, &r.UpdatedAt)
+}
+
+// GetRating returns the rating of a player, an error wrapping sql.ErrNoRows if they have not played a rated game
+func GetRating(player models.RatedPlayer) (*models.Rating, error) {
+	condition, arg := ratedPlayerCondition(player)
+	query := `SELECT ` + ratingColumns + ` FROM ratings r LEFT JOIN users u ON u.id = r.user_id WHERE ` + condition
+
+	var r models.Rating
+	if err := scanRating(DB.QueryRow(query, arg), &r); err != nil {
+		return nil, fmt.Errorf("failed to get rating: %w", err)
+	}
+	return &r, nil
+}
+
+// GetLeaderboard returns a page of the ratings of users or AIs (models.RatedUser or models.RatedAI), highest first,
+// and the number of rated players of that kind
+func GetLeaderboard(playerType string, limit, offset int) ([]models.Rating, int, error) {
+	condition := "r.user_id IS NOT NULL"
+	if playerType == models.RatedAI {
+		condition = "r.ai_name IS NOT NULL"
+	}
+
+	var total int
+	if err := DB.QueryRow(`SELECT COUNT(*) FROM ratings r WHERE ` + condition).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count ratings: %w", err)
+	}
+
+	query := `
+		SELECT ` + ratingColumns + `
+		FROM ratings r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE ` + condition + `
+		ORDER BY r.rating DESC, r.id
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := DB.Query(query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := []models.Rating{}
+	for rows.Next() {
+		r := models.Rating{Rank: offset + len(ratings) + 1}
+		if err := scanRating(rows, &r); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings = append(ratings, r)
+	}
+	return ratings, total, rows.Err()
+}
+
+// GetRatingHistory returns a page of the rating changes of a player, newest first, and the number of changes
+func GetRatingHistory(player models.RatedPlayer, limit, offset int) ([]models.RatingChange, int, error) {
+	condition, arg := ratedPlayerCondition(player)
+
+	var total int
+	query := `SELECT COUNT(*) FROM rating_history h JOIN ratings r ON r.id = h.rating_id WHERE ` + condition
+	if err := DB.QueryRow(query, arg).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count rating history: %w", err)
+	}
+
+	query = `
+		SELECT h.game_id, COALESCE(u.username, o.ai_name, ''), h.result,
+		       h.rating_before, h.rating_after, h.deviation_before, h.deviation_after, h.created_at
+		FROM rating_history h
+		JOIN ratings r ON r.id = h.rating_id
+		LEFT JOIN ratings o ON o.id = h.opponent_rating_id
+		LEFT JOIN users u ON u.id = o.user_id
+		WHERE ` + condition + `
+		ORDER BY h.created_at DESC, h.id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := DB.Query(query, arg, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query rating history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.RatingChange{}
+	for rows.Next() {
+		var c models.RatingChange
+		err := rows.Scan(&c.GameID, &c.OpponentName, &c.Result,
+			&c.RatingBefore, &c.RatingAfter, &c.DeviationBefore, &c.DeviationAfter, &c.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan rating change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, total, rows.Err()
+}
+
+// ratedPlayerCondition returns the condition on ratings r that selects a player, with $1 as its argument
+func ratedPlayerCondition(player models.RatedPlayer) (string, any) {
+	if player.UserID != nil {
+		return "r.user_id = $1", *player.UserID
+	}
+	return "r.ai_name = $1", player.AIName
+}
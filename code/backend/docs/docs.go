@@ -267,7 +267,7 @@ const docTemplate = `{
         },
         "/game/{gameID}": {
             "get": {
-                "description": "Real-time game connection. Use ` + "`" + `player` + "`" + ` query param to join as 0 (Red), 1 (Blue), or anything else (Spectator)",
+                "description": "Real-time game connection. Use ` + "`" + `player` + "`" + ` query param to join as 0 (Red), 1 (Blue), or anything else (Spectator)\nSpectators of games with human players receive the game with a delay",
                 "tags": [
                     "games"
                 ],
@@ -285,9 +285,34 @@ const docTemplate = `{
                         "description": "Player role (0, 1, or spec)",
                         "name": "player",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Spectator view: neutral, red, blue or omniscient, by default omniscient in AI vs AI games and neutral otherwise; games with human players only offer neutral until they are over",
+                        "name": "view",
+                        "in": "query"
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "400": {
+                        "description": "Unknown spectator view",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to join as this player or with this view",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
             }
         },
         "/games": {
@@ -314,7 +339,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Initialize a new game session with specified type and AIs",
+                "description": "Initialize a new game session with specified type, AIs and rule set\nruleSet is one of classic (default), barrage, duel or duel-small (Duel on the small board); army and setupRows change its army or setup rows\nboardLayout is one of classic or small (8x8), board sets a custom size and lakes of at most 10x10 squares;\nboth reduce the setup rows to what the board allows\nsilentDefense, aggressorAdvantage and rescue turn on the special rules of the same name",
                 "consumes": [
                     "application/json"
                 ],
@@ -327,7 +352,7 @@ const docTemplate = `{
                 "summary": "Create a new game",
                 "parameters": [
                     {
-                        "description": "Game creation details (id, type, ai1, ai2)",
+                        "description": "Game creation details (id, type, ai1, ai2, allowSpectators, ruleSet, army, setupRows, boardLayout, board, silentDefense, aggressorAdvantage, rescue)",
                         "name": "request",
                         "in": "body",
                         "required": true,
@@ -384,85 +409,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Confirm the server is running",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "monitoring"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "Status OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+        "/games/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
-            }
-        },
-        "/users/count": {
-            "get": {
-                "description": "Get the total number of users",
-                "produces": [
-                    "application/json"
                 ],
-                "tags": [
-                    "monitoring"
-                ],
-                "summary": "User count",
-                "responses": {
-                    "200": {
-                        "description": "User count",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/users/login": {
-            "post": {
-                "description": "Authenticate user and create session",
+                "description": "Store a finished game from a text game record, see GET /games/{id}/record.\nThe game is played through the engine, which checks the setups, every move and the result.",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "games"
                 ],
-                "summary": "User login",
+                "summary": "Upload a game record",
                 "parameters": [
                     {
-                        "description": "Login details",
-                        "name": "request",
+                        "description": "Game record",
+                        "name": "record",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
+                            "type": "string"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/api.ImportedGame"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid or unfinished game record",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -471,7 +455,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid username or password",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -479,8 +463,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "413": {
+                        "description": "Game record too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Database not available",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -491,57 +484,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/logout": {
-            "post": {
-                "description": "Delete user session and clear cookie",
+        "/games/live": {
+            "get": {
+                "description": "Retrieve the games in progress that are open to spectators, most watched first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "games"
                 ],
-                "summary": "User logout",
+                "summary": "List live games",
                 "responses": {
                     "200": {
-                        "description": "Logged out successfully",
+                        "description": "Live games",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.LiveGame"
                             }
                         }
                     }
                 }
             }
         },
-        "/users/me": {
+        "/games/sessions": {
             "get": {
-                "description": "Retrieve profile of the authenticated user",
+                "description": "Number of sessions in memory and of sessions removed since the server started",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "games"
                 ],
-                "summary": "Get current user",
+                "summary": "Session counts",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Session counts",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/api.SessionStats"
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    }
+                }
+            }
+        },
+        "/games/{id}/history": {
+            "get": {
+                "description": "Retrieve the full move history and initial setup of a finished game",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get game history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.GameHistory"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Game not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -552,34 +565,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me/stats": {
+        "/games/{id}/record": {
             "get": {
-                "description": "Retrieve game statistics for the authenticated user",
+                "description": "Download a finished game as a text game record: the setups of both players and one move per line\nin coordinate notation, e.g. B4-B5 for a move and C7xC6 7x4 for an attack with the ranks of both pieces",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "users"
+                    "games"
+                ],
+                "summary": "Download a game record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user statistics",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.UserStats"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Game record",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Stats not found",
+                        "description": "Game not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -590,39 +603,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/register": {
-            "post": {
-                "description": "Create a new user with username and password",
-                "consumes": [
-                    "application/json"
-                ],
+        "/games/{id}/replay": {
+            "get": {
+                "description": "Reconstruct the board of a finished game after a number of moves, with all pieces revealed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "games"
                 ],
-                "summary": "Register a new user",
+                "summary": "Replay a game",
                 "parameters": [
                     {
-                        "description": "User registration details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of moves to play, 0 for the initial setup, all moves if omitted",
+                        "name": "move",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/api.GameReplay"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid move number",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -630,17 +644,31 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Username already exists",
+                    "404": {
+                        "description": "Game not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Confirm the server is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Status OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -651,21 +679,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/stats": {
+        "/leaderboard": {
             "get": {
-                "description": "Retrieve game statistics for a specific user or the user specified by \"id\" query/path param",
+                "description": "Retrieve a page of the ratings of users, highest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Get user statistics",
+                "summary": "User leaderboard",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ratings per page, at most 100",
+                        "name": "pageSize",
                         "in": "query"
                     }
                 ],
@@ -673,20 +707,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserStats"
+                            "$ref": "#/definitions/api.LeaderboardPage"
                         }
                     },
                     "400": {
-                        "description": "Invalid or missing user ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Stats not found",
+                        "description": "Invalid parameters",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -697,55 +722,952 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/leaderboard/ai": {
             "get": {
-                "description": "Retrieve profile of a user by ID",
+                "description": "Retrieve a page of the ratings of AIs, highest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "leaderboard"
                 ],
-                "summary": "Get user profile",
+                "summary": "AI leaderboard",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ratings per page, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.LeaderboardPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/lobby/queue": {
+            "get": {
+                "description": "Whether the authenticated user is queued or matched, with the game to join once matched",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lobby"
+                ],
+                "summary": "Matchmaking status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.LobbyStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Queue the authenticated user for a human vs human game, poll GET /lobby/queue until matched",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lobby"
+                ],
+                "summary": "Join the matchmaking queue",
+                "parameters": [
+                    {
+                        "description": "Queue options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.JoinLobbyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.LobbyStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already in the queue",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the authenticated user from the queue",
+                "tags": [
+                    "lobby"
+                ],
+                "summary": "Leave the matchmaking queue",
+                "responses": {
+                    "204": {
+                        "description": "Left the queue"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/lobby/ws": {
+            "get": {
+                "description": "Queue the authenticated user while connected. The server sends lobbyStatus messages while waiting and a matchFound message with the seat and game to join, then closes the connection",
+                "tags": [
+                    "lobby"
+                ],
+                "summary": "Matchmaking WebSocket",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only pair with ranked players of a similar rating",
+                        "name": "ranked",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already in the queue",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/count": {
+            "get": {
+                "description": "Get the total number of users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "User count",
+                "responses": {
+                    "200": {
+                        "description": "User count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "Authenticate user and create session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "description": "Delete user session and clear cookie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "User logout",
+                "responses": {
+                    "200": {
+                        "description": "Logged out successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "description": "Retrieve profile of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/stats": {
+            "get": {
+                "description": "Retrieve game statistics for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Stats not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "Create a new user with username and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Username already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/stats": {
+            "get": {
+                "description": "Retrieve game statistics for a specific user or the user specified by \"id\" query/path param",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing user ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Stats not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Retrieve profile of a user by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing user ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/games": {
+            "get": {
+                "description": "Retrieve a page of the finished games of a user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List games of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Games per page, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game type: human_vs_ai, human_vs_human or ai_vs_ai",
+                        "name": "gameType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Result for the user: win, loss or draw",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Finished on or after this date (YYYY-MM-DD or RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Finished before this time, or on or before this date (YYYY-MM-DD or RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserGamesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/rating": {
+            "get": {
+                "description": "Retrieve the rating of a user with a page of its history, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rating changes per page, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserRating"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User has no rated games",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.GameReplay": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/api.PieceDTO"
+                        }
+                    }
+                },
+                "currentPlayerId": {
+                    "type": "integer"
+                },
+                "gameId": {
+                    "type": "string"
+                },
+                "lakes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PositionDTO"
+                    }
+                },
+                "lastMove": {
+                    "$ref": "#/definitions/models.HistoricalMove"
+                },
+                "move": {
+                    "description": "moves played on the board, 0 for the initial setup",
+                    "type": "integer"
+                },
+                "round": {
+                    "type": "integer"
+                },
+                "totalMoves": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.ImportedGame": {
+            "type": "object",
+            "properties": {
+                "gameId": {
+                    "type": "string"
+                },
+                "moves": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "string"
+                },
+                "winCause": {
+                    "type": "string"
+                },
+                "winnerId": {
+                    "description": "seat of the winner, nil for a draw",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.JoinLobbyRequest": {
+            "type": "object",
+            "properties": {
+                "ranked": {
+                    "description": "only pair with ranked players of a similar rating",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.LeaderboardPage": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "ratings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Rating"
+                    }
+                },
+                "total": {
+                    "description": "rated players on all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.LiveGame": {
+            "type": "object",
+            "properties": {
+                "gameId": {
+                    "type": "string"
+                },
+                "gameType": {
+                    "type": "string"
+                },
+                "isSetupPhase": {
+                    "type": "boolean"
+                },
+                "moveCount": {
+                    "type": "integer"
+                },
+                "round": {
+                    "type": "integer"
+                },
+                "spectatorDelayMs": {
+                    "description": "how far the broadcast to spectators lags behind the game",
+                    "type": "integer"
+                },
+                "spectators": {
+                    "type": "integer"
+                },
+                "wsUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LobbyMatch": {
+            "type": "object",
+            "properties": {
+                "gameId": {
+                    "type": "string"
+                },
+                "matchedAt": {
+                    "type": "string"
+                },
+                "opponentId": {
+                    "type": "integer"
+                },
+                "opponentName": {
+                    "type": "string"
+                },
+                "seat": {
+                    "description": "player to connect as, 0 (Red) or 1 (Blue)",
+                    "type": "integer"
+                },
+                "wsUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LobbyStatus": {
+            "type": "object",
+            "properties": {
+                "match": {
+                    "$ref": "#/definitions/api.LobbyMatch"
+                },
+                "position": {
+                    "description": "place in the queue, starting at 1",
+                    "type": "integer"
+                },
+                "queueLength": {
+                    "description": "players waiting for an opponent",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "LobbyStatusIdle, LobbyStatusQueued or LobbyStatusMatched",
+                    "type": "string"
+                },
+                "waitingSeconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.PieceDTO": {
+            "type": "object",
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "ownerName": {
+                    "type": "string"
+                },
+                "position": {
+                    "$ref": "#/definitions/api.PositionDTO"
+                },
+                "rank": {
+                    "type": "string"
+                },
+                "revealed": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.PositionDTO": {
+            "type": "object",
+            "properties": {
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.SessionStats": {
+            "type": "object",
+            "properties": {
+                "abandoned": {
+                    "description": "sessions removed after all clients left or the game was stopped",
+                    "type": "integer"
+                },
+                "active": {
+                    "description": "sessions in memory",
+                    "type": "integer"
+                },
+                "finished": {
+                    "description": "sessions removed after the game was over",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.UserGamesPage": {
+            "type": "object",
+            "properties": {
+                "games": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserGame"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid or missing user ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "games matching the filters on all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.UserRating": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RatingChange"
                     }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "$ref": "#/definitions/models.Rating"
+                },
+                "total": {
+                    "description": "rated games on all pages",
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "models.BoardLayout": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "lakes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Square"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.BoardSetup": {
             "type": "object",
             "properties": {
@@ -807,6 +1729,79 @@ const docTemplate = `{
                 }
             }
         },
+        "models.GameHistory": {
+            "type": "object",
+            "properties": {
+                "gameId": {
+                    "type": "string"
+                },
+                "initialState": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/models.PieceData"
+                        }
+                    }
+                },
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HistoricalMove"
+                    }
+                },
+                "rules": {
+                    "$ref": "#/definitions/models.RuleSet"
+                },
+                "winnerId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HistoricalMove": {
+            "type": "object",
+            "properties": {
+                "attacker": {
+                    "$ref": "#/definitions/models.PieceData"
+                },
+                "defender": {
+                    "$ref": "#/definitions/models.PieceData"
+                },
+                "defenderHidden": {
+                    "description": "silent defense: the defender won without being revealed to the attacker",
+                    "type": "boolean"
+                },
+                "fromX": {
+                    "type": "integer"
+                },
+                "fromY": {
+                    "type": "integer"
+                },
+                "moveIndex": {
+                    "type": "integer"
+                },
+                "playerId": {
+                    "type": "integer"
+                },
+                "rescue": {
+                    "description": "piece brought back by the rescue rule after the move",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Rescue"
+                        }
+                    ]
+                },
+                "result": {
+                    "$ref": "#/definitions/models.MoveResultType"
+                },
+                "toX": {
+                    "type": "integer"
+                },
+                "toY": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.LoginRequest": {
             "type": "object",
             "properties": {
@@ -818,6 +1813,171 @@ const docTemplate = `{
                 }
             }
         },
+        "models.MoveResultType": {
+            "type": "string",
+            "enum": [
+                "move",
+                "win",
+                "loss",
+                "tie",
+                "capture"
+            ],
+            "x-enum-comments": {
+                "ResultCapture": "Flag captured (game over)",
+                "ResultLoss": "Attacker lost combat",
+                "ResultMove": "Normal move to empty cell",
+                "ResultTie": "Both pieces died",
+                "ResultWin": "Attacker won combat"
+            },
+            "x-enum-varnames": [
+                "ResultMove",
+                "ResultWin",
+                "ResultLoss",
+                "ResultTie",
+                "ResultCapture"
+            ]
+        },
+        "models.PieceData": {
+            "type": "object",
+            "properties": {
+                "ownerId": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Rating": {
+            "type": "object",
+            "properties": {
+                "deviation": {
+                    "description": "uncertainty of the rating, lower after more games",
+                    "type": "number"
+                },
+                "games_played": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "username or AI name",
+                    "type": "string"
+                },
+                "player_type": {
+                    "description": "RatedUser or RatedAI",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "place on the leaderboard, starting at 1",
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RatingChange": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deviation_after": {
+                    "type": "number"
+                },
+                "deviation_before": {
+                    "type": "number"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "opponent_name": {
+                    "description": "username or AI name",
+                    "type": "string"
+                },
+                "rating_after": {
+                    "type": "number"
+                },
+                "rating_before": {
+                    "type": "number"
+                },
+                "result": {
+                    "description": "GameResultWin, GameResultLoss or GameResultDraw",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Rescue": {
+            "type": "object",
+            "properties": {
+                "piece": {
+                    "$ref": "#/definitions/models.PieceData"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RuleSet": {
+            "type": "object",
+            "properties": {
+                "aggressorAdvantage": {
+                    "description": "the attacker wins against a piece of equal rank instead of both being lost",
+                    "type": "boolean"
+                },
+                "army": {
+                    "description": "pieces per rank, e.g. \"B\" for bombs, ranks not listed are not in the army",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "board": {
+                    "$ref": "#/definitions/models.BoardLayout"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "repetitionRules": {
+                    "description": "enforce the two-square and more-squares rules",
+                    "type": "boolean"
+                },
+                "rescue": {
+                    "description": "a piece reaching the enemy's back row brings back the strongest captured piece, once",
+                    "type": "boolean"
+                },
+                "setupRows": {
+                    "description": "rows at each player's side of the board the army is set up in",
+                    "type": "integer"
+                },
+                "silentDefense": {
+                    "description": "a defender that wins stays hidden, only the attacker's rank is revealed",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Square": {
+            "type": "object",
+            "properties": {
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.UpdateBoardSetupRequest": {
             "type": "object",
             "properties": {
@@ -855,6 +2015,46 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UserGame": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "gameId": {
+                    "type": "string"
+                },
+                "gameType": {
+                    "type": "string"
+                },
+                "moveCount": {
+                    "type": "integer"
+                },
+                "opponentId": {
+                    "description": "nil for AIs and guests",
+                    "type": "integer"
+                },
+                "opponentName": {
+                    "description": "username of the opponent",
+                    "type": "string"
+                },
+                "playerId": {
+                    "description": "seat of the user, 0 or 1",
+                    "type": "integer"
+                },
+                "result": {
+                    "description": "GameResultWin, GameResultLoss or GameResultDraw",
+                    "type": "string"
+                },
+                "winnerId": {
+                    "description": "0 or 1, nil for a draw",
+                    "type": "integer"
+                }
+            }
+        },
         "models.UserStats": {
             "type": "object",
             "properties": {
@@ -1,35 +1,96 @@
 package engine
 
-import "errors"
+import (
+	"digital-innovation/stratego/models"
+	"errors"
+)
 
 type Board struct {
-	field [10][10]*Piece
-	lakes [8]Position
+	field [][]*Piece
+	lakes []Position
 }
 
+// NewBoard returns an empty classic 10x10 board with eight lake squares.
 func NewBoard() *Board {
-	return &Board{
-		field: [10][10]*Piece{},
-		lakes: [8]Position{
-			NewPosition(2, 4), NewPosition(3, 4), NewPosition(2, 5), NewPosition(3, 5),
-			NewPosition(6, 4), NewPosition(7, 4), NewPosition(6, 5), NewPosition(7, 5),
-		},
+	return NewBoardWithLayout(models.ClassicBoard())
+}
+
+// NewBoardWithLayout returns an empty board with the size and lakes of the layout.
+// The layout is not validated, see models.BoardLayout.Validate.
+func NewBoardWithLayout(layout models.BoardLayout) *Board {
+	lakes := make([]Position, len(layout.Lakes))
+	for i, lake := range layout.Lakes {
+		lakes[i] = NewPosition(lake.X, lake.Y)
+	}
+	return &Board{field: newField(layout.Width, layout.Height), lakes: lakes}
+}
+
+// newField allocates the rows of a field in one block.
+func newField(width, height int) [][]*Piece {
+	squares := make([]*Piece, width*height)
+	field := make([][]*Piece, height)
+	for y := range field {
+		field[y] = squares[y*width : (y+1)*width : (y+1)*width]
+	}
+	return field
+}
+
+// Clone returns a deep copy of the board. Every piece is copied and keeps its owner.
+func (b *Board) Clone() *Board {
+	return b.CloneWith(func(piece *Piece) *Piece {
+		return piece.Clone(nil)
+	})
+}
+
+// CloneWith returns a copy of the board in which every piece is replaced by clonePiece(piece).
+// This lets the caller choose the copies, e.g. to hand them to copied players.
+func (b *Board) CloneWith(clonePiece func(*Piece) *Piece) *Board {
+	clone := &Board{field: newField(b.GetWidth(), b.GetHeight()), lakes: b.lakes}
+	for y := range b.field {
+		for x, piece := range b.field[y] {
+			if piece != nil {
+				clone.field[y][x] = clonePiece(piece)
+			}
+		}
 	}
+	return clone
+}
+
+// GetWidth returns the number of columns of the board.
+func (b *Board) GetWidth() int {
+	if len(b.field) == 0 {
+		return 0
+	}
+	return len(b.field[0])
+}
+
+// GetHeight returns the number of rows of the board.
+func (b *Board) GetHeight() int {
+	return len(b.field)
+}
+
+// GetLakes returns the lake squares of the board. The slice is shared and must not be modified.
+func (b *Board) GetLakes() []Position {
+	return b.lakes
 }
 
 // SetPieceAt sets the piece at the given position on the board.
-// The piece is updated in the board's internal field, which is a 10x10 2D slice of pointers to Piece.
+// The piece is updated in the board's internal field, which is a 2D slice of pointers to Piece, indexed [y][x].
 // The function does not check if the move is valid, it simply updates the board state.
 // The function is O(1) and updates the board state.
 func (b *Board) SetPieceAt(pos Position, piece *Piece) {
 	b.field[pos.Y][pos.X] = piece
 }
 
-// GetField returns the board's internal field, which is a 10x10 2D slice of pointers to Piece.
-// The field is used to store the pieces on the board and is updated by the game engine.
-// The function is O(1) and does not modify the board state.
-func (b *Board) GetField() [10][10]*Piece {
-	return b.field
+// GetField returns a copy of the board's field, a 2D slice of pointers to Piece indexed [y][x]
+// with GetHeight rows of GetWidth squares.
+// Changing the copy does not change the board, use SetPieceAt for that.
+func (b *Board) GetField() [][]*Piece {
+	field := newField(b.GetWidth(), b.GetHeight())
+	for y := range b.field {
+		copy(field[y], b.field[y])
+	}
+	return field
 }
 
 // GetPieceAt returns the piece at the given position on the board.
@@ -52,14 +113,20 @@ func (b *Board) IsLake(pos Position) bool {
 	return false
 }
 
+// IsInBounds returns a boolean indicating whether the given position lies on the board.
+func (b *Board) IsInBounds(pos Position) bool {
+	return pos.X >= 0 && pos.X < b.GetWidth() && pos.Y >= 0 && pos.Y < b.GetHeight()
+}
+
 // IsValidMove returns a boolean indicating whether a move is valid on the board.
 // It checks if the move is within the bounds of the board, if the destination is a lake,
 // and if the destination is occupied by a piece of the same owner as the piece being moved.
 // It does not check if the piece can move to the destination (e.g. if the piece is a scout, it does
-// not check if the destination is more than one space away).
+// not check if the destination is more than one space away), it is only used for move generation.
+// Use ValidateMove to check moves coming from players.
 // It returns false if any of these conditions are not met, and true otherwise.
 func (b *Board) IsValidMove(move *Move) bool {
-	if move.GetTo().X < 0 || move.GetTo().X >= len(b.field[0]) || move.GetTo().Y < 0 || move.GetTo().Y >= len(b.field) {
+	if !b.IsInBounds(move.GetTo()) {
 		return false
 	}
 	if b.IsLake(move.GetTo()) {
@@ -137,7 +204,7 @@ func (b *Board) handleScoutMoves(pos Position, moves *[]Move) {
 	}
 
 	for _, dir := range directions {
-		for step := 1; step < 10; step++ {
+		for step := 1; step < max(b.GetWidth(), b.GetHeight()); step++ {
 			newPos := NewPosition(pos.X+dir.X*step, pos.Y+dir.Y*step)
 			move := NewMove(pos, newPos, nil)
 			if b.IsValidMove(&move) {
@@ -173,12 +240,11 @@ func (b *Board) handleStandardMoves(pos Position, moves *[]Move) {
 
 // String returns a string representation of the board.
 // Each piece is represented by its icon, and empty squares are represented by "..".
-// Lakes are represented by "~~". The string is formatted as a 10x10 grid, with each row on a new line.
+// Lakes are represented by "~~". The string is formatted as a grid of the board's size, with each row on a new line.
 func (b *Board) String() string {
 	result := ""
-	for y := range 10 {
-		for x := range 10 {
-			piece := b.field[y][x]
+	for y := range b.field {
+		for x, piece := range b.field[y] {
 			if piece == nil {
 				if b.IsLake(NewPosition(x, y)) {
 					result += " ~~ "
@@ -69,14 +69,13 @@ func GetPieceTypeFromCell(cell byte) *models.PieceType {
 	return idToPieceType[pieceID]
 }
 
-// Encode full 10x10 board to 100 bytes
+// Encode full board to one byte per square, indexed y*width+x (100 bytes for the classic 10x10 board)
 func EncodeBoard(board *Board, movedPositions map[Position]bool) []byte {
-	data := make([]byte, 100)
-	field := board.GetField()
+	width := board.GetWidth()
+	data := make([]byte, width*board.GetHeight())
 
-	for y := range 10 {
-		for x := range 10 {
-			piece := field[y][x]
+	for y := range board.field {
+		for x, piece := range board.field[y] {
 			if piece == nil {
 				continue
 			}
@@ -96,21 +95,26 @@ func EncodeBoard(board *Board, movedPositions map[Position]bool) []byte {
 				cell |= BitMoved
 			}
 
-			data[y*10+x] = cell
+			data[y*width+x] = cell
 		}
 	}
 
 	return data
 }
 
-// Decode 100 bytes to full 10x10 board
+// Decode 100 bytes to full classic 10x10 board
 func DecodeBoard(data []byte, player1, player2 *Player) (*Board, map[Position]bool) {
-	board := NewBoard()
+	return DecodeBoardWithLayout(data, models.ClassicBoard(), player1, player2)
+}
+
+// Decode width*height bytes to a full board with the given layout
+func DecodeBoardWithLayout(data []byte, layout models.BoardLayout, player1, player2 *Player) (*Board, map[Position]bool) {
+	board := NewBoardWithLayout(layout)
 	movedPositions := make(map[Position]bool)
 
-	for y := range 10 {
-		for x := range 10 {
-			cell := data[y*10+x]
+	for y := range layout.Height {
+		for x := range layout.Width {
+			cell := data[y*layout.Width+x]
 			if cell&BitOccupied == 0 {
 				continue
 			}
@@ -215,14 +219,20 @@ func DecodeSetup(data []byte) ([]string, int, error) {
 
 // Validate setup has correct piece counts
 func ValidateSetup(rows []string) error {
-	if len(rows) != 4 {
-		return fmt.Errorf("setup must have 4 rows, got %d", len(rows))
+	return ValidateSetupWithRules(rows, models.ClassicRules())
+}
+
+// ValidateSetupWithRules checks that a setup fills the setup rows of the rule set with exactly its army
+func ValidateSetupWithRules(rows []string, rules models.RuleSet) error {
+	if len(rows) != rules.SetupRows {
+		return fmt.Errorf("setup must have %d rows, got %d", rules.SetupRows, len(rows))
 	}
 
 	counts := make(map[byte]int)
+	width := rules.Layout().Width
 	for _, row := range rows {
-		if len(row) != 10 {
-			return fmt.Errorf("each row must be 10 chars")
+		if len(row) != width {
+			return fmt.Errorf("each row must be %d chars", width)
 		}
 		for _, c := range row {
 			if c != '.' && c != ' ' {
@@ -231,16 +241,5 @@ func ValidateSetup(rows []string) error {
 		}
 	}
 
-	expected := map[byte]int{
-		'0': 1, 'B': 6, '1': 1, '2': 8, '3': 5, '4': 4,
-		'5': 4, '6': 4, '7': 3, '8': 2, '9': 1, 'M': 1,
-	}
-
-	for piece, count := range expected {
-		if counts[piece] != count {
-			return fmt.Errorf("piece %c: expected %d, got %d", piece, count, counts[piece])
-		}
-	}
-
-	return nil
+	return rules.ValidateArmy(counts)
 }
@@ -51,6 +51,28 @@ func TestEncodeBoard(t *testing.T) {
 	}
 }
 
+func TestEncodeBoardWithLayout(t *testing.T) {
+	layout := models.SmallBoard()
+	board := NewBoardWithLayout(layout)
+	player1 := NewPlayer(1, "Player1", "red")
+	player2 := NewPlayer(2, "Player2", "blue")
+	board.SetPieceAt(NewPosition(7, 7), NewPiece(models.Flag, &player1))
+	board.SetPieceAt(NewPosition(0, 1), NewPiece(models.Marshal, &player2))
+
+	data := EncodeBoard(board, nil)
+	if len(data) != 64 {
+		t.Fatalf("Expected 64 bytes for an 8x8 board, got %d", len(data))
+	}
+	if (data[8]&MaskPieceType)>>ShiftPieceType != PieceIDMarshal || (data[63]&MaskPieceType)>>ShiftPieceType != PieceIDFlag {
+		t.Error("Expected squares to be indexed y*8+x")
+	}
+
+	decoded, _ := DecodeBoardWithLayout(data, layout, &player1, &player2)
+	if decoded.String() != board.String() {
+		t.Errorf("Expected the decoded board to equal the original\n%s\ngot\n%s", board, decoded)
+	}
+}
+
 func TestDecodeBoard(t *testing.T) {
 	player1 := NewPlayer(1, "Player1", "red")
 	player2 := NewPlayer(2, "Player2", "blue")
@@ -174,3 +196,18 @@ func TestValidateSetup(t *testing.T) {
 		t.Error("Invalid setup accepted")
 	}
 }
+
+func TestValidateSetupWithRules(t *testing.T) {
+	duel := []string{"0BBB123333", "2223445678"} // wrong: one scout short, one miner too many
+	if err := ValidateSetupWithRules(duel, models.DuelRules()); err == nil {
+		t.Error("Invalid Duel setup accepted")
+	}
+
+	duel = []string{"0BBB122223", "334456789M"}
+	if err := ValidateSetupWithRules(duel, models.DuelRules()); err != nil {
+		t.Errorf("Valid Duel setup rejected: %v", err)
+	}
+	if err := ValidateSetup(duel); err == nil {
+		t.Error("Duel setup accepted as a classic setup")
+	}
+}
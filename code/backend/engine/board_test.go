@@ -35,6 +35,30 @@ func TestNewBoard(t *testing.T) {
 	}
 }
 
+func TestNewBoardWithLayout(t *testing.T) {
+	board := engine.NewBoardWithLayout(models.SmallBoard())
+	if board.GetWidth() != 8 || board.GetHeight() != 8 || len(board.GetField()) != 8 || len(board.GetField()[7]) != 8 {
+		t.Fatalf("Expected an 8x8 board, got %dx%d", board.GetWidth(), board.GetHeight())
+	}
+	if !board.IsLake(engine.NewPosition(5, 4)) || board.IsLake(engine.NewPosition(3, 4)) {
+		t.Error("Expected the lakes of the small board")
+	}
+	if board.IsInBounds(engine.NewPosition(8, 0)) || board.IsInBounds(engine.NewPosition(0, 8)) || !board.IsInBounds(engine.NewPosition(7, 7)) {
+		t.Error("Expected the bounds of an 8x8 board")
+	}
+
+	// A scout in the corner is stopped by the edge of the board and a lake
+	player := engine.NewPlayer(0, "Alice", "red")
+	board.SetPieceAt(engine.NewPosition(2, 7), engine.NewPiece(models.Scout, &player))
+	moves, err := board.ListMoves(engine.NewPosition(2, 7))
+	if err != nil {
+		t.Fatalf("Expected moves for the scout, got: %v", err)
+	}
+	if len(moves) != 9 {
+		t.Errorf("Expected 2 moves up to the lake, 2 to the left and 5 to the right, got %d", len(moves))
+	}
+}
+
 func TestSetAndGetPieceAt(t *testing.T) {
 	board := engine.NewBoard()
 	player := engine.NewPlayer(1, "Alice", "red")
@@ -334,3 +358,28 @@ func TestListMovesNoPiece(t *testing.T) {
 		t.Errorf("Expected no moves for empty position, but got %d", len(moves))
 	}
 }
+
+func TestBoardClone(t *testing.T) {
+	board := engine.NewBoard()
+	player := engine.NewPlayer(1, "Alice", "red")
+	piece := engine.NewPiece(models.Scout, &player)
+	board.SetPieceAt(engine.NewPosition(0, 0), piece)
+
+	clone := board.Clone()
+	copied := clone.GetPieceAt(engine.NewPosition(0, 0))
+	if copied == nil || copied == piece {
+		t.Fatalf("Expected the clone to hold a copy of the piece")
+	}
+	if copied.GetOwner() != &player || copied.GetRank() != piece.GetRank() {
+		t.Errorf("Expected the copy to keep rank and owner")
+	}
+
+	copied.Reveal()
+	clone.SetPieceAt(engine.NewPosition(1, 0), copied)
+	if piece.IsRevealed() || board.GetPieceAt(engine.NewPosition(1, 0)) != nil {
+		t.Errorf("Expected changes to the clone not to affect the original board")
+	}
+	if !clone.IsLake(engine.NewPosition(2, 4)) {
+		t.Errorf("Expected the clone to keep the lakes")
+	}
+}
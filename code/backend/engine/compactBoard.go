@@ -0,0 +1,301 @@
+package engine
+
+import (
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+)
+
+// CompactBoard is an allocation-free board representation for high-throughput simulation,
+// e.g. random playouts or generating training games. Each of the 100 squares is one byte
+// in the same cell encoding as EncodeBoard (occupied, piece ID, color, moved), indexed y*10+x.
+// Color 0 is the first player, color 1 (BitColor set) the second.
+// Lakes and, on boards smaller than 10x10, the squares outside the board hold CellBlocked.
+// The zero value is a 10x10 board without lakes, start from NewEmptyCompactBoard to get the lakes of a board.
+// The compact board knows the full information of both players and does not track
+// revealed pieces or the repetition rules.
+type CompactBoard [models.MaxBoardSize * models.MaxBoardSize]byte
+
+// CellBlocked marks a square of a CompactBoard no piece can enter. It is not occupied
+// and has all piece type bits set, so it cannot be mistaken for a piece or an empty square.
+const CellBlocked byte = MaskPieceType
+
+// CompactMove is a move on a CompactBoard between two square indexes.
+type CompactMove struct {
+	From uint8
+	To   uint8
+}
+
+// CombatOutcome is the result of a move, as returned by CompactBoard.Apply and PredictCombat.
+type CombatOutcome int
+
+const (
+	CombatNone         CombatOutcome = iota // no combat
+	CombatAttackerWon                       // defender removed, attacker moved
+	CombatDefenderWon                       // attacker removed
+	CombatBothLost                          // both pieces removed
+	CombatFlagCaptured                      // defender was the flag, the attacker won the game
+)
+
+// compactDirections are the square index offsets for up, down, left and right.
+var compactDirections = [4]struct{ dx, dy int }{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+// SquareIndex returns the index of a position on a CompactBoard.
+func SquareIndex(pos Position) uint8 {
+	return uint8(pos.Y*models.MaxBoardSize + pos.X)
+}
+
+// SquarePosition returns the position of a square index on a CompactBoard.
+func SquarePosition(square uint8) Position {
+	return NewPosition(int(square%models.MaxBoardSize), int(square/models.MaxBoardSize))
+}
+
+// NewEmptyCompactBoard returns a compact board without pieces in which the lakes of the board
+// and the squares outside of it are blocked. The board must not be larger than 10x10.
+func NewEmptyCompactBoard(board *Board) CompactBoard {
+	var c CompactBoard
+	width, height := board.GetWidth(), board.GetHeight()
+	if width < models.MaxBoardSize || height < models.MaxBoardSize {
+		for square := range c {
+			if pos := SquarePosition(uint8(square)); pos.X >= width || pos.Y >= height {
+				c[square] = CellBlocked
+			}
+		}
+	}
+	for _, lake := range board.lakes {
+		c[SquareIndex(lake)] = CellBlocked
+	}
+	return c
+}
+
+// NewCompactBoard converts a board to its compact representation.
+// Pieces owned by second (compared by ID) get color 1, all others color 0.
+// Squares in movedPositions get the moved bit, like in EncodeBoard.
+func NewCompactBoard(board *Board, second *Player, movedPositions map[Position]bool) CompactBoard {
+	c := NewEmptyCompactBoard(board)
+	for y := range board.field {
+		for x, piece := range board.field[y] {
+			if piece == nil {
+				continue
+			}
+
+			cell := byte(BitOccupied) | (rankToPieceID[piece.GetRank()]<<ShiftPieceType)&MaskPieceType
+			if samePlayer(piece.GetOwner(), second) {
+				cell |= BitColor
+			}
+			if movedPositions[NewPosition(x, y)] {
+				cell |= BitMoved
+			}
+			c[SquareIndex(NewPosition(x, y))] = cell
+		}
+	}
+	return c
+}
+
+// ToBoard converts the compact board back to a Board with new pieces for the given players.
+// The board covers every square that is not blocked, and the blocked squares on it become lakes.
+// It also returns the squares whose pieces have moved, like DecodeBoard.
+func (c *CompactBoard) ToBoard(first, second *Player) (*Board, map[Position]bool) {
+	board := NewBoardWithLayout(c.layout())
+	movedPositions := make(map[Position]bool)
+	for square, cell := range c {
+		if cell&BitOccupied == 0 {
+			continue
+		}
+
+		owner := first
+		if cell&BitColor != 0 {
+			owner = second
+		}
+		pos := SquarePosition(uint8(square))
+		board.SetPieceAt(pos, NewPiece(*GetPieceTypeFromCell(cell), owner))
+		if cell&BitMoved != 0 {
+			movedPositions[pos] = true
+		}
+	}
+	return board, movedPositions
+}
+
+// SetPiece puts a piece with the given piece ID and color on the square.
+func (c *CompactBoard) SetPiece(square uint8, pieceID, color byte, moved bool) {
+	cell := byte(BitOccupied) | (pieceID<<ShiftPieceType)&MaskPieceType | color<<1
+	if moved {
+		cell |= BitMoved
+	}
+	c[square] = cell
+}
+
+// PieceID returns the piece ID on the square, or 0 if it is empty.
+func (c *CompactBoard) PieceID(square uint8) byte {
+	return (c[square] & MaskPieceType) >> ShiftPieceType
+}
+
+// Color returns the color of the piece on the square: 0 for the first player, 1 for the second.
+func (c *CompactBoard) Color(square uint8) byte {
+	return (c[square] & BitColor) >> 1
+}
+
+// layout returns the smallest board that covers all squares that are not blocked,
+// with the blocked squares on it as lakes.
+func (c *CompactBoard) layout() models.BoardLayout {
+	var layout models.BoardLayout
+	for square, cell := range c {
+		if cell != CellBlocked {
+			pos := SquarePosition(uint8(square))
+			layout.Width = max(layout.Width, pos.X+1)
+			layout.Height = max(layout.Height, pos.Y+1)
+		}
+	}
+	for y := range layout.Height {
+		for x := range layout.Width {
+			if c.IsBlocked(SquareIndex(NewPosition(x, y))) {
+				layout.Lakes = append(layout.Lakes, models.Square{X: x, Y: y})
+			}
+		}
+	}
+	return layout
+}
+
+// IsBlocked reports whether the square is a lake or outside the board.
+func (c *CompactBoard) IsBlocked(square uint8) bool {
+	return c[square] == CellBlocked
+}
+
+// GenerateMoves appends all valid moves of the given color to moves and returns the extended slice.
+// Passing a reused slice with enough capacity (e.g. moves[:0]) keeps move generation allocation-free.
+func (c *CompactBoard) GenerateMoves(color byte, moves []CompactMove) []CompactMove {
+	for square := range uint8(len(c)) {
+		cell := c[square]
+		if cell&BitOccupied == 0 || (cell&BitColor)>>1 != color {
+			continue
+		}
+		id := (cell & MaskPieceType) >> ShiftPieceType
+		if id == PieceIDFlag || id == PieceIDBomb {
+			continue
+		}
+
+		x, y := int(square%models.MaxBoardSize), int(square/models.MaxBoardSize)
+		for _, dir := range compactDirections {
+			nx, ny := x+dir.dx, y+dir.dy
+			for nx >= 0 && nx < models.MaxBoardSize && ny >= 0 && ny < models.MaxBoardSize {
+				to := uint8(ny*models.MaxBoardSize + nx)
+				target := c[to]
+				if target == CellBlocked {
+					break
+				}
+				if target&BitOccupied != 0 {
+					if (target&BitColor)>>1 != color {
+						moves = append(moves, CompactMove{From: square, To: to})
+					}
+					break
+				}
+				moves = append(moves, CompactMove{From: square, To: to})
+				if id != PieceIDScout {
+					break
+				}
+				nx, ny = nx+dir.dx, ny+dir.dy
+			}
+		}
+	}
+	return moves
+}
+
+// Apply makes a move generated by GenerateMoves and resolves any combat with the same rules as Piece.Attack.
+func (c *CompactBoard) Apply(move CompactMove) CombatOutcome {
+	return c.apply(move, false)
+}
+
+// ApplyWithRules makes the move like Apply, resolving combat with the rule set, see PredictCombatWithRules.
+func (c *CompactBoard) ApplyWithRules(move CompactMove, rules models.RuleSet) CombatOutcome {
+	return c.apply(move, rules.AggressorAdvantage)
+}
+
+func (c *CompactBoard) apply(move CompactMove, aggressorAdvantage bool) CombatOutcome {
+	attacker := c[move.From] | BitMoved
+	defender := c[move.To]
+	c[move.From] = 0
+
+	if defender&BitOccupied == 0 {
+		c[move.To] = attacker
+		return CombatNone
+	}
+
+	outcome := compactCombat((attacker&MaskPieceType)>>ShiftPieceType, (defender&MaskPieceType)>>ShiftPieceType, aggressorAdvantage)
+	switch outcome {
+	case CombatAttackerWon, CombatFlagCaptured:
+		c[move.To] = attacker
+	case CombatBothLost:
+		c[move.To] = 0
+	}
+	return outcome
+}
+
+// compactCombat resolves an attack between two piece IDs.
+// Piece IDs of movable pieces are ordered by strength, so they can be compared directly.
+func compactCombat(attacker, defender byte, aggressorAdvantage bool) CombatOutcome {
+	switch {
+	case defender == PieceIDFlag:
+		return CombatFlagCaptured
+	case attacker == PieceIDSpy && defender == PieceIDMarshal:
+		return CombatAttackerWon
+	case defender == PieceIDBomb:
+		if attacker == PieceIDMiner {
+			return CombatAttackerWon
+		}
+		return CombatDefenderWon
+	case attacker > defender, attacker == defender && aggressorAdvantage:
+		return CombatAttackerWon
+	case attacker < defender:
+		return CombatDefenderWon
+	default:
+		return CombatBothLost
+	}
+}
+
+// PredictCombat returns the outcome of an attack between two piece types, without changing any pieces.
+// AIs use it to judge attacks on pieces they know or guess.
+func PredictCombat(attacker, defender *models.PieceType) CombatOutcome {
+	return compactCombat(rankToPieceID[attacker.GetRank()], rankToPieceID[defender.GetRank()], false)
+}
+
+// PredictCombatWithRules returns the outcome of an attack in a game played with the rule set.
+// With aggressor advantage the attacker wins between equal ranks.
+func PredictCombatWithRules(attacker, defender *models.PieceType, rules models.RuleSet) CombatOutcome {
+	return compactCombat(rankToPieceID[attacker.GetRank()], rankToPieceID[defender.GetRank()], rules.AggressorAdvantage)
+}
+
+// RandomPlayout plays uniformly random moves, starting with the given color, until a flag is captured,
+// a player has no moves left or maxMoves moves were made. It changes the board in place.
+// It returns the winning color, or -1 if the game was not decided within maxMoves.
+// moves is a reusable buffer for move generation; pass nil to let the playout allocate one.
+func (c *CompactBoard) RandomPlayout(rng *rand.Rand, color byte, maxMoves int, moves []CompactMove) int {
+	return c.randomPlayout(rng, color, maxMoves, moves, false)
+}
+
+// RandomPlayoutWithRules plays random moves like RandomPlayout, resolving combat with the rule set.
+func (c *CompactBoard) RandomPlayoutWithRules(rng *rand.Rand, color byte, maxMoves int, moves []CompactMove, rules models.RuleSet) int {
+	return c.randomPlayout(rng, color, maxMoves, moves, rules.AggressorAdvantage)
+}
+
+func (c *CompactBoard) randomPlayout(rng *rand.Rand, color byte, maxMoves int, moves []CompactMove, aggressorAdvantage bool) int {
+	for range maxMoves {
+		moves = c.GenerateMoves(color, moves[:0])
+		if len(moves) == 0 {
+			return int(1 - color)
+		}
+		if c.apply(moves[rng.IntN(len(moves))], aggressorAdvantage) == CombatFlagCaptured {
+			return int(color)
+		}
+		color = 1 - color
+	}
+	return -1
+}
+
+// ToMove converts the compact move to a Move of the given player.
+func (m CompactMove) ToMove(player *Player) Move {
+	return NewMove(SquarePosition(m.From), SquarePosition(m.To), player)
+}
+
+// NewCompactMove converts a Move to a CompactMove.
+func NewCompactMove(move Move) CompactMove {
+	return CompactMove{From: SquareIndex(move.GetFrom()), To: SquareIndex(move.GetTo())}
+}
@@ -0,0 +1,211 @@
+package engine_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+	"testing"
+)
+
+func quickStartBoard() (*engine.Board, *engine.Player, *engine.Player) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+	return g.Board, &player1, &player2
+}
+
+func TestCompactBoardRoundTrip(t *testing.T) {
+	board, player1, player2 := quickStartBoard()
+	moved := map[engine.Position]bool{engine.NewPosition(0, 6): true}
+
+	compact := engine.NewCompactBoard(board, player2, moved)
+	decoded, decodedMoved := compact.ToBoard(player1, player2)
+
+	if decoded.String() != board.String() {
+		t.Errorf("Expected the decoded board to equal the original\n%s\ngot\n%s", board, decoded)
+	}
+	if !decodedMoved[engine.NewPosition(0, 6)] || len(decodedMoved) != 1 {
+		t.Errorf("Expected only (0,6) to be marked as moved, got %v", decodedMoved)
+	}
+	if again := engine.NewCompactBoard(decoded, player2, decodedMoved); again != compact {
+		t.Errorf("Expected encoding the decoded board to give the same compact board")
+	}
+
+	for y := range 10 {
+		for x := range 10 {
+			pos := engine.NewPosition(x, y)
+			piece := board.GetPieceAt(pos)
+			if piece != nil && piece.GetOwner() == player2 && compact.Color(engine.SquareIndex(pos)) != 1 {
+				t.Errorf("Expected pieces of the second player to have color 1 at %v", pos)
+			}
+		}
+	}
+}
+
+func TestCompactBoardGenerateMovesMatchesBoard(t *testing.T) {
+	board, _, player2 := quickStartBoard()
+	board.SetPieceAt(engine.NewPosition(0, 5), engine.NewPiece(models.Scout, player2))
+	compact := engine.NewCompactBoard(board, player2, nil)
+
+	for color, player := range []*engine.Player{nil, player2} {
+		expected := make(map[engine.CompactMove]bool)
+		for y := range 10 {
+			for x := range 10 {
+				pos := engine.NewPosition(x, y)
+				piece := board.GetPieceAt(pos)
+				if piece == nil || (piece.GetOwner() == player2) != (player == player2) {
+					continue
+				}
+				moves, _ := board.ListMoves(pos)
+				for _, m := range moves {
+					expected[engine.NewCompactMove(m)] = true
+				}
+			}
+		}
+
+		generated := compact.GenerateMoves(byte(color), nil)
+		if len(generated) != len(expected) {
+			t.Errorf("Expected %d moves for color %d, got %d", len(expected), color, len(generated))
+		}
+		for _, m := range generated {
+			if !expected[m] {
+				t.Errorf("Unexpected move %v for color %d", m, color)
+			}
+		}
+	}
+}
+
+func TestCompactBoardWithLayout(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	rules := models.BarrageRules().WithBoard(models.SmallBoard())
+	g := game.QuickStartWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2),
+		rules, rand.New(rand.NewPCG(1, 2)))
+	compact := engine.NewCompactBoard(g.Board, &player2, nil)
+
+	if !compact.IsBlocked(engine.SquareIndex(engine.NewPosition(2, 3))) || !compact.IsBlocked(engine.SquareIndex(engine.NewPosition(8, 0))) ||
+		compact.IsBlocked(engine.SquareIndex(engine.NewPosition(3, 3))) {
+		t.Error("Expected the lakes and the squares outside the 8x8 board to be blocked")
+	}
+
+	decoded, _ := compact.ToBoard(&player1, &player2)
+	if decoded.String() != g.Board.String() {
+		t.Errorf("Expected the decoded board to equal the original\n%s\ngot\n%s", g.Board, decoded)
+	}
+
+	for color, player := range []*engine.Player{&player1, &player2} {
+		expected := 0
+		for _, piece := range player.GetAlivePieces() {
+			pos, _ := player.GetPiecePosition(piece)
+			if moves, err := g.Board.ListMoves(pos); err == nil {
+				expected += len(moves)
+			}
+		}
+		if generated := compact.GenerateMoves(byte(color), nil); len(generated) != expected {
+			t.Errorf("Expected %d moves for color %d on the small board, got %d", expected, color, len(generated))
+		}
+	}
+}
+
+func TestCompactBoardCombatMatchesAttack(t *testing.T) {
+	types := []models.PieceType{
+		models.Flag, models.Bomb, models.Spy, models.Scout, models.Miner, models.Sergeant,
+		models.Lieutenant, models.Captain, models.Major, models.Colonel, models.General, models.Marshal,
+	}
+
+	for _, attackerType := range types {
+		if !attackerType.IsMovable() {
+			continue
+		}
+		for _, defenderType := range types {
+			alice := engine.NewPlayer(0, "Alice", "red")
+			bob := engine.NewPlayer(1, "Bob", "blue")
+			attacker := engine.NewPiece(attackerType, &alice)
+			defender := engine.NewPiece(defenderType, &bob)
+
+			board := engine.NewBoard()
+			board.SetPieceAt(engine.NewPosition(0, 0), attacker)
+			board.SetPieceAt(engine.NewPosition(0, 1), defender)
+			compact := engine.NewCompactBoard(board, &bob, nil)
+			outcome := compact.Apply(engine.CompactMove{From: 0, To: 10})
+
+			attacker.Attack(defender)
+			var expected engine.CombatOutcome
+			switch {
+			case defenderType.GetRank() == models.Flag.GetRank():
+				expected = engine.CombatFlagCaptured
+			case attacker.IsAlive() && !defender.IsAlive():
+				expected = engine.CombatAttackerWon
+			case !attacker.IsAlive() && defender.IsAlive():
+				expected = engine.CombatDefenderWon
+			default:
+				expected = engine.CombatBothLost
+			}
+
+			if outcome != expected {
+				t.Errorf("%s attacking %s: expected outcome %d, got %d",
+					attackerType.GetName(), defenderType.GetName(), expected, outcome)
+			}
+		}
+	}
+}
+
+func TestCompactBoardCombatWithAggressorAdvantage(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.AggressorAdvantage = true
+
+	for _, pieceType := range models.PieceTypes {
+		if !pieceType.IsMovable() {
+			continue
+		}
+		alice := engine.NewPlayer(0, "Alice", "red")
+		bob := engine.NewPlayer(1, "Bob", "blue")
+		attacker := engine.NewPiece(pieceType, &alice)
+		defender := engine.NewPiece(pieceType, &bob)
+
+		board := engine.NewBoard()
+		board.SetPieceAt(engine.NewPosition(0, 0), attacker)
+		board.SetPieceAt(engine.NewPosition(0, 1), defender)
+		compact := engine.NewCompactBoard(board, &bob, nil)
+		if outcome := compact.ApplyWithRules(engine.CompactMove{From: 0, To: 10}, rules); outcome != engine.CombatAttackerWon {
+			t.Errorf("%s attacking %s: expected the attacker to win, got %d", pieceType.GetName(), pieceType.GetName(), outcome)
+		}
+		if outcome := engine.PredictCombatWithRules(&pieceType, &pieceType, rules); outcome != engine.CombatAttackerWon {
+			t.Errorf("Expected the predicted tie of two %s to go to the attacker, got %d", pieceType.GetName(), outcome)
+		}
+		if outcome := engine.PredictCombat(&pieceType, &pieceType); outcome != engine.CombatBothLost {
+			t.Errorf("Expected two %s to trade under the classic rules, got %d", pieceType.GetName(), outcome)
+		}
+
+		attacker.AttackWithRules(defender, rules)
+		if !attacker.IsAlive() || defender.IsAlive() {
+			t.Errorf("Expected the attacking %s to win with aggressor advantage", pieceType.GetName())
+		}
+	}
+}
+
+func TestCompactBoardRandomPlayout(t *testing.T) {
+	board, _, player2 := quickStartBoard()
+	compact := engine.NewCompactBoard(board, player2, nil)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	winner := compact.RandomPlayout(rng, 0, 2000, nil)
+	if winner < -1 || winner > 1 {
+		t.Errorf("Expected winner to be -1, 0 or 1, got %d", winner)
+	}
+}
+
+func BenchmarkCompactBoardRandomPlayout(b *testing.B) {
+	board, _, player2 := quickStartBoard()
+	start := engine.NewCompactBoard(board, player2, nil)
+	rng := rand.New(rand.NewPCG(1, 2))
+	moves := make([]engine.CompactMove, 0, 256)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		compact := start
+		compact.RandomPlayout(rng, 0, 2000, moves)
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+}
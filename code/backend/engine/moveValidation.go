@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"digital-innovation/stratego/models"
+	"errors"
+)
+
+var (
+	ErrOutOfBounds      = errors.New("move is out of bounds")
+	ErrNoPiece          = errors.New("no piece at the starting position")
+	ErrNotYourPiece     = errors.New("piece does not belong to the moving player")
+	ErrNotYourTurn      = errors.New("it is not this player's turn")
+	ErrImmovablePiece   = errors.New("piece cannot move")
+	ErrNotStraightLine  = errors.New("pieces can only move in a straight orthogonal line")
+	ErrTooFar           = errors.New("only scouts can move more than one square")
+	ErrPathBlocked      = errors.New("path to the destination is blocked")
+	ErrLakeDestination  = errors.New("cannot move into a lake")
+	ErrFriendlyOccupied = errors.New("destination is occupied by a friendly piece")
+)
+
+// ValidateMove is the authoritative check whether a move is allowed on the board.
+// It checks that both positions are on the board, that the moving player owns a movable piece
+// at the starting position and that the piece can actually reach the destination:
+// one square orthogonally, or any number of free squares in a straight line for scouts.
+// The destination must not be a lake or hold a piece of the same player.
+// It returns one of the typed errors above, or nil if the move is valid.
+// Turn order and repetition rules depend on the game and are checked by the caller.
+func ValidateMove(board *Board, move *Move) error {
+	from, to := move.GetFrom(), move.GetTo()
+	if !board.IsInBounds(from) || !board.IsInBounds(to) {
+		return ErrOutOfBounds
+	}
+
+	piece := board.GetPieceAt(from)
+	if piece == nil {
+		return ErrNoPiece
+	}
+	if !samePlayer(piece.GetOwner(), move.GetPlayer()) || move.GetPlayer() == nil {
+		return ErrNotYourPiece
+	}
+	if !piece.CanMove() {
+		return ErrImmovablePiece
+	}
+
+	dx, dy := to.X-from.X, to.Y-from.Y
+	if (dx != 0) == (dy != 0) {
+		return ErrNotStraightLine // diagonal, or not moving at all
+	}
+	if abs(dx)+abs(dy) > 1 && piece.GetRank() != models.Scout.GetRank() {
+		return ErrTooFar
+	}
+
+	step := NewPosition(sign(dx), sign(dy))
+	for pos := NewPosition(from.X+step.X, from.Y+step.Y); pos != to; pos = NewPosition(pos.X+step.X, pos.Y+step.Y) {
+		if board.IsLake(pos) || board.GetPieceAt(pos) != nil {
+			return ErrPathBlocked
+		}
+	}
+
+	if board.IsLake(to) {
+		return ErrLakeDestination
+	}
+	if target := board.GetPieceAt(to); target != nil && samePlayer(target.GetOwner(), piece.GetOwner()) {
+		return ErrFriendlyOccupied
+	}
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,135 @@
+package engine_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"errors"
+	"testing"
+)
+
+func TestValidateMoveStandardPiece(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	board.SetPieceAt(engine.NewPosition(0, 6), engine.NewPiece(models.Major, &alice))
+
+	valid := testMove(0, 6, 0, 5, &alice)
+	if err := engine.ValidateMove(board, &valid); err != nil {
+		t.Errorf("Expected move to be valid, got: %v", err)
+	}
+
+	tooFar := testMove(0, 6, 0, 4, &alice)
+	if err := engine.ValidateMove(board, &tooFar); !errors.Is(err, engine.ErrTooFar) {
+		t.Errorf("Expected ErrTooFar, got: %v", err)
+	}
+
+	diagonal := testMove(0, 6, 1, 5, &alice)
+	if err := engine.ValidateMove(board, &diagonal); !errors.Is(err, engine.ErrNotStraightLine) {
+		t.Errorf("Expected ErrNotStraightLine, got: %v", err)
+	}
+
+	standStill := testMove(0, 6, 0, 6, &alice)
+	if err := engine.ValidateMove(board, &standStill); !errors.Is(err, engine.ErrNotStraightLine) {
+		t.Errorf("Expected ErrNotStraightLine when not moving, got: %v", err)
+	}
+}
+
+func TestValidateMoveOutOfBounds(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Major, &alice))
+
+	move := testMove(0, 9, 0, 10, &alice)
+	if err := engine.ValidateMove(board, &move); !errors.Is(err, engine.ErrOutOfBounds) {
+		t.Errorf("Expected ErrOutOfBounds, got: %v", err)
+	}
+
+	fromOutside := testMove(-1, 9, 0, 9, &alice)
+	if err := engine.ValidateMove(board, &fromOutside); !errors.Is(err, engine.ErrOutOfBounds) {
+		t.Errorf("Expected ErrOutOfBounds for starting position, got: %v", err)
+	}
+}
+
+func TestValidateMoveOwnership(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+	board.SetPieceAt(engine.NewPosition(0, 6), engine.NewPiece(models.Major, &alice))
+
+	empty := testMove(5, 6, 5, 5, &alice)
+	if err := engine.ValidateMove(board, &empty); !errors.Is(err, engine.ErrNoPiece) {
+		t.Errorf("Expected ErrNoPiece, got: %v", err)
+	}
+
+	stolen := testMove(0, 6, 0, 5, &bob)
+	if err := engine.ValidateMove(board, &stolen); !errors.Is(err, engine.ErrNotYourPiece) {
+		t.Errorf("Expected ErrNotYourPiece, got: %v", err)
+	}
+
+	noPlayer := testMove(0, 6, 0, 5, nil)
+	if err := engine.ValidateMove(board, &noPlayer); !errors.Is(err, engine.ErrNotYourPiece) {
+		t.Errorf("Expected ErrNotYourPiece for a move without player, got: %v", err)
+	}
+}
+
+func TestValidateMoveImmovablePieces(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	board.SetPieceAt(engine.NewPosition(0, 6), engine.NewPiece(models.Bomb, &alice))
+	board.SetPieceAt(engine.NewPosition(1, 6), engine.NewPiece(models.Flag, &alice))
+
+	bomb := testMove(0, 6, 0, 5, &alice)
+	if err := engine.ValidateMove(board, &bomb); !errors.Is(err, engine.ErrImmovablePiece) {
+		t.Errorf("Expected ErrImmovablePiece for bomb, got: %v", err)
+	}
+
+	flag := testMove(1, 6, 1, 5, &alice)
+	if err := engine.ValidateMove(board, &flag); !errors.Is(err, engine.ErrImmovablePiece) {
+		t.Errorf("Expected ErrImmovablePiece for flag, got: %v", err)
+	}
+}
+
+func TestValidateMoveScout(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+	board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Scout, &alice))
+	board.SetPieceAt(engine.NewPosition(0, 3), engine.NewPiece(models.Major, &bob))
+	board.SetPieceAt(engine.NewPosition(2, 6), engine.NewPiece(models.Scout, &alice))
+
+	long := testMove(0, 9, 0, 4, &alice)
+	if err := engine.ValidateMove(board, &long); err != nil {
+		t.Errorf("Expected scout to move several squares, got: %v", err)
+	}
+
+	attack := testMove(0, 9, 0, 3, &alice)
+	if err := engine.ValidateMove(board, &attack); err != nil {
+		t.Errorf("Expected scout to attack at distance, got: %v", err)
+	}
+
+	overPiece := testMove(0, 9, 0, 2, &alice)
+	if err := engine.ValidateMove(board, &overPiece); !errors.Is(err, engine.ErrPathBlocked) {
+		t.Errorf("Expected ErrPathBlocked when jumping over a piece, got: %v", err)
+	}
+
+	overLake := testMove(2, 6, 2, 3, &alice)
+	if err := engine.ValidateMove(board, &overLake); !errors.Is(err, engine.ErrPathBlocked) {
+		t.Errorf("Expected ErrPathBlocked when jumping over a lake, got: %v", err)
+	}
+
+	intoLake := testMove(2, 6, 2, 5, &alice)
+	if err := engine.ValidateMove(board, &intoLake); !errors.Is(err, engine.ErrLakeDestination) {
+		t.Errorf("Expected ErrLakeDestination, got: %v", err)
+	}
+}
+
+func TestValidateMoveFriendlyOccupied(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	board.SetPieceAt(engine.NewPosition(0, 6), engine.NewPiece(models.Major, &alice))
+	board.SetPieceAt(engine.NewPosition(0, 5), engine.NewPiece(models.Captain, &alice))
+
+	move := testMove(0, 6, 0, 5, &alice)
+	if err := engine.ValidateMove(board, &move); !errors.Is(err, engine.ErrFriendlyOccupied) {
+		t.Errorf("Expected ErrFriendlyOccupied, got: %v", err)
+	}
+}
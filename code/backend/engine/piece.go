@@ -9,6 +9,8 @@ type Piece struct {
 	player    *Player
 	alive     bool
 	revealed  bool
+	moved     bool
+	rescued   bool
 }
 
 // NewPiece creates a new Piece with the given pieceType and player.
@@ -54,7 +56,7 @@ func (p *Piece) IsRevealed() bool {
 // The rank is a byte value that indicates the piece's strength in battle.
 // The rank is used by the game engine to determine the outcome of a battle.
 // A piece with a higher rank will always win against a piece with a lower rank.
-// If the ranks are equal, both pieces are eliminated from the game.
+// If the ranks are equal, both pieces are eliminated from the game, unless the rule set gives the attacker the advantage.
 func (p *Piece) GetRank() byte {
 	return p.pieceType.GetRank()
 }
@@ -81,12 +83,52 @@ func (p *Piece) Reveal() {
 	p.revealed = true
 }
 
+// HasMoved returns a boolean indicating whether the piece has moved during the game.
+// A piece that has moved cannot be a bomb or a flag, which the opponent may use as information.
+func (p *Piece) HasMoved() bool {
+	return p.moved
+}
+
+// SetMoved marks whether the piece has moved. The game engine marks a piece after each of its moves.
+func (p *Piece) SetMoved(moved bool) {
+	p.moved = moved
+}
+
+// HasRescued returns a boolean indicating whether the piece has brought back a captured piece with the rescue rule.
+// Each piece can rescue only once.
+func (p *Piece) HasRescued() bool {
+	return p.rescued
+}
+
+// SetRescued marks whether the piece has rescued a captured piece.
+func (p *Piece) SetRescued(rescued bool) {
+	p.rescued = rescued
+}
+
 // Hide sets the Revealed field of the piece to false, hiding it from opponents.
 // This is used to temporarily hide pieces after combat reveals them.
 func (p *Piece) Hide() {
 	p.revealed = false
 }
 
+// Clone returns a copy of the piece owned by the given player.
+// If owner is nil, the copy keeps the owner of the original piece.
+// Search-based AIs use this to copy a game without touching the original pieces.
+func (p *Piece) Clone(owner *Player) *Piece {
+	clone := *p
+	if owner != nil {
+		clone.player = owner
+	}
+	return &clone
+}
+
+// Revive brings an eliminated piece back into the game, undoing Eliminate.
+// The piece is tracked again by its owner at the given position and index of the alive pieces.
+func (p *Piece) Revive(pos Position, index int) {
+	p.alive = true
+	p.GetOwner().RestorePiece(p, pos, index)
+}
+
 // Eliminate marks the piece as eliminated from the game.
 // It is used by the game engine to update the state of the pieces after a battle.
 func (p *Piece) Eliminate() {
@@ -105,6 +147,16 @@ func (p *Piece) Eliminate() {
 //
 // returns an array of two pieces: the attacking piece and the target piece
 func (p *Piece) Attack(target *Piece) [2]*Piece {
+	return p.attack(target, false)
+}
+
+// AttackWithRules resolves an attack like Attack in a game played with the rule set.
+// With aggressor advantage the attacking piece wins against a piece of equal rank.
+func (p *Piece) AttackWithRules(target *Piece, rules models.RuleSet) [2]*Piece {
+	return p.attack(target, rules.AggressorAdvantage)
+}
+
+func (p *Piece) attack(target *Piece, aggressorAdvantage bool) [2]*Piece {
 	switch {
 	case target.GetRank() == models.Flag.GetRank():
 		p.resolveFlagCapture(target)
@@ -113,7 +165,7 @@ func (p *Piece) Attack(target *Piece) [2]*Piece {
 	case target.GetRank() == models.Bomb.GetRank():
 		p.resolveBombAttack(target)
 	default:
-		p.resolveStandardAttack(target)
+		p.resolveStandardAttack(target, aggressorAdvantage)
 	}
 	return [2]*Piece{p, target}
 }
@@ -136,9 +188,9 @@ func (p *Piece) resolveBombAttack(target *Piece) {
 	}
 }
 
-func (p *Piece) resolveStandardAttack(target *Piece) {
+func (p *Piece) resolveStandardAttack(target *Piece, aggressorAdvantage bool) {
 	switch {
-	case p.GetRank() > target.GetRank():
+	case p.GetRank() > target.GetRank(), p.GetRank() == target.GetRank() && aggressorAdvantage:
 		target.Eliminate()
 	case p.GetRank() < target.GetRank():
 		p.Eliminate()
@@ -274,3 +274,35 @@ func TestStandardAttackEqualRank(t *testing.T) {
 		t.Errorf("Expected player2 piece score to be %d, got %d", expectedScore2, player2.GetPieceScore())
 	}
 }
+
+func TestStandardAttackAggressorAdvantage(t *testing.T) {
+	// setup
+	player1 := engine.NewPlayer(1, "player1", "avatar1")
+	sergeant1 := engine.NewPiece(models.Sergeant, &player1)
+	player1.InitializePieceScore(sergeant1.GetStrategicValue())
+
+	player2 := engine.NewPlayer(2, "player2", "avatar2")
+	sergeant2 := engine.NewPiece(models.Sergeant, &player2)
+	player2.InitializePieceScore(sergeant2.GetStrategicValue())
+
+	rules := models.ClassicRules()
+	rules.AggressorAdvantage = true
+
+	// execute
+	result := sergeant1.AttackWithRules(sergeant2, rules)
+	attacker, target := result[0], result[1]
+
+	// verify
+	if !attacker.IsAlive() {
+		t.Errorf("Expected attacker sergeant to win the tie, got eliminated")
+	}
+	if target.IsAlive() {
+		t.Errorf("Expected target sergeant to be eliminated, got alive")
+	}
+	if player1.GetPieceScore() != sergeant1.GetStrategicValue() {
+		t.Errorf("Expected player1 piece score to be %d, got %d", sergeant1.GetStrategicValue(), player1.GetPieceScore())
+	}
+	if player2.GetPieceScore() != 0 {
+		t.Errorf("Expected player2 piece score to be 0, got %d", player2.GetPieceScore())
+	}
+}
@@ -39,6 +39,20 @@ func (p *Player) SetWinner() {
 	p.won = true
 }
 
+// ClearWinner removes the winner mark of the player, e.g. when the winning move is taken back.
+func (p *Player) ClearWinner() {
+	p.won = false
+}
+
+// Clone returns a copy of the player with the same id, name, avatar, score and winner mark.
+// The piece tracking of the copy starts empty, add the copied pieces with AddPiece.
+func (pl *Player) Clone() *Player {
+	clone := NewPlayer(pl.id, pl.name, pl.avatar)
+	clone.pieceScore = pl.pieceScore
+	clone.won = pl.won
+	return &clone
+}
+
 // GetName returns the name of the player. This is used by the game engine to
 // display the names of players in the game.
 func (pl *Player) GetName() string {
@@ -103,6 +117,31 @@ func (pl *Player) RemovePiece(piece *Piece) {
 	delete(pl.piecePositions, piece)
 }
 
+// RestorePiece tracks an eliminated piece again, undoing UpdatePieceScore.
+// The piece is inserted at the given index of the alive pieces, so their order is the same as before
+// the piece was eliminated. A negative index only restores the score, for pieces that were not tracked.
+func (pl *Player) RestorePiece(piece *Piece, pos Position, index int) {
+	pl.pieceScore += piece.GetStrategicValue()
+	if index < 0 {
+		return
+	}
+	index = min(index, len(pl.alivePieces))
+	pl.alivePieces = append(pl.alivePieces, nil)
+	copy(pl.alivePieces[index+1:], pl.alivePieces[index:])
+	pl.alivePieces[index] = piece
+	pl.piecePositions[piece] = pos
+}
+
+// IndexOfPiece returns the index of the piece among the alive pieces, or -1 if it is not tracked.
+func (pl *Player) IndexOfPiece(piece *Piece) int {
+	for i, p := range pl.alivePieces {
+		if p == piece {
+			return i
+		}
+	}
+	return -1
+}
+
 // UpdatePiecePosition updates a piece's position (O(1))
 func (pl *Player) UpdatePiecePosition(piece *Piece, newPos Position) {
 	pl.piecePositions[piece] = newPos
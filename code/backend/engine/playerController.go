@@ -14,7 +14,7 @@ const (
 type PlayerController interface {
 	GetPlayer() *Player
 	GetControllerType() ControllerType
-	MakeMove(board *Board) Move
+	MakeMove(view *PlayerView) Move
 }
 
 // HumanPlayerController represents a human player waiting for input
@@ -39,7 +39,7 @@ func (h *HumanPlayerController) GetControllerType() ControllerType {
 }
 
 // MakeMove for human returns an empty move - the game should wait for SetPendingMove
-func (h *HumanPlayerController) MakeMove(board *Board) Move {
+func (h *HumanPlayerController) MakeMove(view *PlayerView) Move {
 	// Return empty move - game loop should check for this and wait
 	return Move{}
 }
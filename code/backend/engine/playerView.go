@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"digital-innovation/stratego/models"
+	"errors"
+)
+
+var ErrNotOwnPiece = errors.New("only moves of own pieces can be listed")
+
+// Knowledge describes what a player knows about a square of the board.
+type Knowledge int
+
+const (
+	SquareEmpty   Knowledge = iota // no piece on the square
+	PieceOwn                       // own piece, everything is known
+	PieceUnknown                   // enemy piece that has neither moved nor been revealed
+	PieceMoved                     // enemy piece that has moved, so it is no bomb or flag
+	PieceRevealed                  // enemy piece whose rank is revealed
+)
+
+// ViewPiece is what a player sees of a square.
+// Type is only set for own and revealed pieces, Piece only for own pieces.
+type ViewPiece struct {
+	Knowledge Knowledge
+	OwnerID   int
+	Moved     bool
+	Type      *models.PieceType
+	Piece     *Piece
+}
+
+// IsEnemy reports whether the square holds a piece of the opponent.
+func (vp ViewPiece) IsEnemy() bool {
+	return vp.Knowledge != SquareEmpty && vp.Knowledge != PieceOwn
+}
+
+// PlayerView is the board as seen by one player: own pieces are fully known,
+// enemy pieces only show whether they have moved or been revealed.
+// Controllers decide their moves from a view, so they cannot see hidden enemy pieces.
+type PlayerView struct {
+	player  *Player
+	board   *Board // only read through the methods below, never handed out
+	history []Move
+	round   int
+	rules   models.RuleSet
+}
+
+// NewPlayerView creates the view of the board for the given player.
+// The history is used to keep the listed moves within the repetition rules.
+func NewPlayerView(board *Board, player *Player, history []Move, round int) *PlayerView {
+	return NewPlayerViewWithRules(board, player, history, round, models.ClassicRules())
+}
+
+// NewPlayerViewWithRules creates the view of the board for the given player in a game played with the rule set.
+func NewPlayerViewWithRules(board *Board, player *Player, history []Move, round int, rules models.RuleSet) *PlayerView {
+	return &PlayerView{
+		player:  player,
+		board:   board,
+		history: history,
+		round:   round,
+		rules:   rules,
+	}
+}
+
+// GetPlayer returns the player the view belongs to.
+func (v *PlayerView) GetPlayer() *Player {
+	return v.player
+}
+
+// GetRound returns the round of the game.
+func (v *PlayerView) GetRound() int {
+	return v.round
+}
+
+// GetRules returns the rule set of the game. The army of the opponent is public information.
+func (v *PlayerView) GetRules() models.RuleSet {
+	return v.rules
+}
+
+// ViewMove is a move of the history as both players see it: the squares and the ID of the player who moved.
+type ViewMove struct {
+	From     Position
+	To       Position
+	PlayerID int
+}
+
+// NewViewMove returns the move as both players see it.
+func NewViewMove(move Move) ViewMove {
+	return ViewMove{From: move.GetFrom(), To: move.GetTo(), PlayerID: move.GetPlayer().GetID()}
+}
+
+// SeenPiece is a piece as a player saw it in a combat or a rescue: its owner, its type if it was revealed and
+// whether it is still on the board. Unlike a *Piece it does not lead to the other pieces of its owner.
+type SeenPiece struct {
+	OwnerID int
+	Type    *models.PieceType // nil if the rank stayed hidden
+	Alive   bool
+}
+
+// NewSeenPiece returns the piece with its rank revealed.
+func NewSeenPiece(piece *Piece) SeenPiece {
+	pieceType := *piece.GetType()
+	return SeenPiece{OwnerID: piece.GetOwner().GetID(), Type: &pieceType, Alive: piece.IsAlive()}
+}
+
+// GetMoveHistory returns all moves played so far. Moves are public information, but the players that made them are not
+// handed out, since their pieces would tell the hidden ranks of the enemy.
+func (v *PlayerView) GetMoveHistory() []ViewMove {
+	moves := make([]ViewMove, len(v.history))
+	for i, move := range v.history {
+		moves[i] = NewViewMove(move)
+	}
+	return moves
+}
+
+// GetWidth returns the number of columns of the board.
+func (v *PlayerView) GetWidth() int {
+	return v.board.GetWidth()
+}
+
+// GetHeight returns the number of rows of the board.
+func (v *PlayerView) GetHeight() int {
+	return v.board.GetHeight()
+}
+
+// EmptyCompactBoard returns a compact board of the view's board without pieces, see NewEmptyCompactBoard.
+func (v *PlayerView) EmptyCompactBoard() CompactBoard {
+	return NewEmptyCompactBoard(v.board)
+}
+
+// IsLake returns a boolean indicating whether the given position is a lake.
+func (v *PlayerView) IsLake(pos Position) bool {
+	return v.board.IsLake(pos)
+}
+
+// IsInBounds returns a boolean indicating whether the given position lies on the board.
+func (v *PlayerView) IsInBounds(pos Position) bool {
+	return v.board.IsInBounds(pos)
+}
+
+// GetPieceAt returns what the player knows about the piece at the given position.
+func (v *PlayerView) GetPieceAt(pos Position) ViewPiece {
+	piece := v.board.GetPieceAt(pos)
+	if piece == nil {
+		return ViewPiece{Knowledge: SquareEmpty}
+	}
+
+	vp := ViewPiece{OwnerID: piece.GetOwner().GetID(), Moved: piece.HasMoved()}
+	switch {
+	case samePlayer(piece.GetOwner(), v.player):
+		vp.Knowledge = PieceOwn
+		vp.Type = piece.GetType()
+		vp.Piece = piece
+	case piece.IsRevealed():
+		vp.Knowledge = PieceRevealed
+		vp.Type = piece.GetType()
+	case piece.HasMoved():
+		vp.Knowledge = PieceMoved
+	default:
+		vp.Knowledge = PieceUnknown
+	}
+	return vp
+}
+
+// ListLegalMoves returns the moves of the own piece at the given position that are valid on the board
+// and keep to the repetition rules. Listing moves of enemy pieces would tell whether they can move,
+// so it returns ErrNotOwnPiece for them.
+func (v *PlayerView) ListLegalMoves(pos Position) ([]Move, error) {
+	if !v.board.IsInBounds(pos) {
+		return nil, ErrOutOfBounds
+	}
+	piece := v.board.GetPieceAt(pos)
+	if piece != nil && !samePlayer(piece.GetOwner(), v.player) {
+		return nil, ErrNotOwnPiece
+	}
+	return v.board.ListLegalMoves(pos, v.repetitionHistory())
+}
+
+// ValidateMove checks a move of the player against the board and the repetition rules.
+func (v *PlayerView) ValidateMove(move *Move) error {
+	if !samePlayer(move.GetPlayer(), v.player) {
+		return ErrNotYourPiece
+	}
+	if err := ValidateMove(v.board, move); err != nil {
+		return err
+	}
+	return CheckRepetition(v.repetitionHistory(), *move)
+}
+
+// repetitionHistory returns the history the repetition rules are checked against, none if the rules are off.
+func (v *PlayerView) repetitionHistory() []Move {
+	if !v.rules.RepetitionRules {
+		return nil
+	}
+	return v.history
+}
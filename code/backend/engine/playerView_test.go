@@ -0,0 +1,83 @@
+package engine_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPlayerViewHidesEnemyPieces(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+
+	own := engine.NewPiece(models.Major, &alice)
+	hidden := engine.NewPiece(models.Bomb, &bob)
+	moved := engine.NewPiece(models.Scout, &bob)
+	moved.SetMoved(true)
+	revealed := engine.NewPiece(models.Marshal, &bob)
+	revealed.Reveal()
+
+	board.SetPieceAt(engine.NewPosition(0, 6), own)
+	board.SetPieceAt(engine.NewPosition(0, 3), hidden)
+	board.SetPieceAt(engine.NewPosition(1, 3), moved)
+	board.SetPieceAt(engine.NewPosition(2, 3), revealed)
+
+	view := engine.NewPlayerView(board, &alice, nil, 1)
+
+	if vp := view.GetPieceAt(engine.NewPosition(0, 6)); vp.Knowledge != engine.PieceOwn || vp.Piece != own || vp.Type.GetName() != "Major" {
+		t.Errorf("Expected own piece to be fully known, got %+v", vp)
+	}
+	if vp := view.GetPieceAt(engine.NewPosition(0, 3)); vp.Knowledge != engine.PieceUnknown || vp.Type != nil || vp.Piece != nil {
+		t.Errorf("Expected unmoved enemy piece to be unknown, got %+v", vp)
+	}
+	if vp := view.GetPieceAt(engine.NewPosition(1, 3)); vp.Knowledge != engine.PieceMoved || vp.Type != nil || !vp.Moved {
+		t.Errorf("Expected moved enemy piece to show only that it moved, got %+v", vp)
+	}
+	if vp := view.GetPieceAt(engine.NewPosition(2, 3)); vp.Knowledge != engine.PieceRevealed || vp.Type.GetName() != "Marshal" || vp.Piece != nil {
+		t.Errorf("Expected revealed enemy piece to show its rank, got %+v", vp)
+	}
+	if vp := view.GetPieceAt(engine.NewPosition(5, 5)); vp.Knowledge != engine.SquareEmpty || vp.IsEnemy() {
+		t.Errorf("Expected empty square, got %+v", vp)
+	}
+}
+
+func TestPlayerViewListLegalMoves(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+	board.SetPieceAt(engine.NewPosition(0, 6), engine.NewPiece(models.Major, &alice))
+	board.SetPieceAt(engine.NewPosition(0, 3), engine.NewPiece(models.Bomb, &bob))
+
+	view := engine.NewPlayerView(board, &alice, nil, 1)
+
+	moves, err := view.ListLegalMoves(engine.NewPosition(0, 6))
+	if err != nil || len(moves) != 3 {
+		t.Errorf("Expected 3 moves for own piece, got %d (%v)", len(moves), err)
+	}
+
+	if _, err := view.ListLegalMoves(engine.NewPosition(0, 3)); !errors.Is(err, engine.ErrNotOwnPiece) {
+		t.Errorf("Expected ErrNotOwnPiece for enemy piece, got: %v", err)
+	}
+}
+
+func TestPlayerViewMoveHistory(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+	history := []engine.Move{
+		engine.NewMove(engine.NewPosition(0, 6), engine.NewPosition(0, 5), &alice),
+		engine.NewMove(engine.NewPosition(0, 3), engine.NewPosition(0, 4), &bob),
+	}
+
+	view := engine.NewPlayerView(board, &alice, history, 2)
+	expected := []engine.ViewMove{
+		{From: engine.NewPosition(0, 6), To: engine.NewPosition(0, 5), PlayerID: 0},
+		{From: engine.NewPosition(0, 3), To: engine.NewPosition(0, 4), PlayerID: 1},
+	}
+	if moves := view.GetMoveHistory(); !reflect.DeepEqual(moves, expected) {
+		t.Errorf("Expected the history %+v, got: %+v", expected, moves)
+	}
+}
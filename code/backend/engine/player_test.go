@@ -78,3 +78,27 @@ func TestUpdatePieceScore(t *testing.T) {
 		t.Errorf("Expected player piece score to be 60 after update, got %d", player.GetPieceScore())
 	}
 }
+
+func TestRestorePiece(t *testing.T) {
+	player := engine.NewPlayer(1, "Alice", "red")
+	first := engine.NewPiece(models.Scout, &player)
+	second := engine.NewPiece(models.Major, &player)
+	player.AddPiece(first, engine.NewPosition(0, 0))
+	player.AddPiece(second, engine.NewPosition(1, 0))
+	player.InitializePieceScore(first.GetStrategicValue() + second.GetStrategicValue())
+	score := player.GetPieceScore()
+
+	index := player.IndexOfPiece(first)
+	first.Eliminate()
+	first.Revive(engine.NewPosition(0, 0), index)
+
+	if !first.IsAlive() || player.GetPieceScore() != score {
+		t.Errorf("Expected piece alive and score %d, got alive=%t score=%d", score, first.IsAlive(), player.GetPieceScore())
+	}
+	if pieces := player.GetAlivePieces(); len(pieces) != 2 || pieces[0] != first {
+		t.Errorf("Expected the piece to be restored at its old index")
+	}
+	if pos, ok := player.GetPiecePosition(first); !ok || pos != engine.NewPosition(0, 0) {
+		t.Errorf("Expected the piece position to be tracked again, got %v", pos)
+	}
+}
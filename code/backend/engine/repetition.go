@@ -0,0 +1,140 @@
+package engine
+
+import "errors"
+
+var (
+	ErrTwoSquareRule   = errors.New("move breaks the two-square rule")
+	ErrMoreSquaresRule = errors.New("move breaks the more-squares rule")
+)
+
+// CheckRepetition checks a move against the ISF repetition rules, given the moves played so far.
+// It returns ErrTwoSquareRule when the piece has already moved three times non-stop between the same
+// two squares, and ErrMoreSquaresRule when the move continues a chase into a position that already
+// occurred during that chase. The rules are tracked per player: only the moves of the player making
+// the move (and, for chases, the replies of the chased piece) are taken into account.
+// It returns nil if the move is allowed.
+func CheckRepetition(history []Move, move Move) error {
+	if violatesTwoSquareRule(history, move) {
+		return ErrTwoSquareRule
+	}
+	if violatesMoreSquaresRule(history, move) {
+		return ErrMoreSquaresRule
+	}
+	return nil
+}
+
+// ListLegalMoves returns the moves of the piece at the given position that are valid on the board
+// and do not break the repetition rules given the history of the game.
+// The returned moves belong to the owner of the piece.
+func (b *Board) ListLegalMoves(pos Position, history []Move) ([]Move, error) {
+	moves, err := b.ListMoves(pos)
+	if err != nil {
+		return moves, err
+	}
+
+	owner := b.GetPieceAt(pos).GetOwner()
+	legal := moves[:0]
+	for _, m := range moves {
+		move := NewMove(m.GetFrom(), m.GetTo(), owner)
+		if CheckRepetition(history, move) == nil {
+			legal = append(legal, move)
+		}
+	}
+	return legal, nil
+}
+
+// violatesTwoSquareRule checks whether the last three moves of the player were made by the same piece,
+// going back and forth within the squares of the first of those moves, and the move would do so again.
+// For most pieces these are just two squares; for a scout all squares spanned by its first move count.
+func violatesTwoSquareRule(history []Move, move Move) bool {
+	own := lastMovesOf(history, move.GetPlayer(), 3)
+	if len(own) < 3 {
+		return false
+	}
+
+	first, second, third := own[0], own[1], own[2]
+	if second.GetFrom() != first.GetTo() || third.GetFrom() != second.GetTo() || move.GetFrom() != third.GetTo() {
+		return false // not the same piece every time
+	}
+
+	from, to := first.GetFrom(), first.GetTo()
+	return isBetween(second.GetTo(), from, to) &&
+		isBetween(third.GetTo(), from, to) &&
+		isBetween(move.GetTo(), from, to)
+}
+
+// violatesMoreSquaresRule checks whether the move continues a chase and would recreate a position
+// that already occurred during that chase. A chase is a sequence in which the same piece keeps moving
+// next to the same opponent piece, and that opponent piece keeps fleeing from it.
+// Since only these two pieces move during a chase, a position is identified by their two squares.
+func violatesMoreSquaresRule(history []Move, move Move) bool {
+	n := len(history)
+	if n < 2 {
+		return false
+	}
+
+	target := history[n-1].GetTo()
+	if !isAdjacent(move.GetTo(), target) {
+		return false
+	}
+
+	// Walk back through the chase, one pair of (chaser move, chased reply) at a time
+	chaser, chased := move.GetFrom(), target
+	for i := n - 1; i >= 1; i -= 2 {
+		reply, threat := history[i], history[i-1]
+		if !samePlayer(threat.GetPlayer(), move.GetPlayer()) || samePlayer(reply.GetPlayer(), move.GetPlayer()) {
+			break
+		}
+		if reply.GetTo() != chased || threat.GetTo() != chaser || !isAdjacent(threat.GetTo(), reply.GetFrom()) {
+			break
+		}
+
+		// Position right after the chaser's move
+		if threat.GetTo() == move.GetTo() && reply.GetFrom() == target {
+			return true
+		}
+
+		chaser, chased = threat.GetFrom(), reply.GetFrom()
+	}
+	return false
+}
+
+// lastMovesOf returns up to count of the most recent moves made by the player, oldest first.
+func lastMovesOf(history []Move, player *Player, count int) []Move {
+	moves := make([]Move, 0, count)
+	for i := len(history) - 1; i >= 0 && len(moves) < count; i-- {
+		if samePlayer(history[i].GetPlayer(), player) {
+			moves = append(moves, history[i])
+		}
+	}
+	for i, j := 0, len(moves)-1; i < j; i, j = i+1, j-1 {
+		moves[i], moves[j] = moves[j], moves[i]
+	}
+	return moves
+}
+
+// samePlayer compares players by ID, so moves stay comparable across copies of a game.
+func samePlayer(a, b *Player) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.GetID() == b.GetID()
+}
+
+// isBetween reports whether pos lies on the straight segment from a to b, both ends included.
+func isBetween(pos, a, b Position) bool {
+	switch {
+	case a.X == b.X:
+		return pos.X == a.X && pos.Y >= min(a.Y, b.Y) && pos.Y <= max(a.Y, b.Y)
+	case a.Y == b.Y:
+		return pos.Y == a.Y && pos.X >= min(a.X, b.X) && pos.X <= max(a.X, b.X)
+	default:
+		return pos == a || pos == b
+	}
+}
+
+// isAdjacent reports whether two positions are orthogonal neighbours.
+func isAdjacent(a, b Position) bool {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx+dy*dy == 1
+}
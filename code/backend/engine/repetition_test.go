@@ -0,0 +1,146 @@
+package engine_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"errors"
+	"testing"
+)
+
+func testMove(x1, y1, x2, y2 int, player *engine.Player) engine.Move {
+	return engine.NewMove(engine.NewPosition(x1, y1), engine.NewPosition(x2, y2), player)
+}
+
+func TestCheckRepetitionTwoSquareRule(t *testing.T) {
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+
+	history := []engine.Move{
+		testMove(0, 6, 0, 5, &alice), testMove(9, 3, 9, 4, &bob),
+		testMove(0, 5, 0, 6, &alice), testMove(9, 4, 9, 3, &bob),
+		testMove(0, 6, 0, 5, &alice), testMove(9, 3, 9, 4, &bob),
+	}
+
+	err := engine.CheckRepetition(history, testMove(0, 5, 0, 6, &alice))
+	if !errors.Is(err, engine.ErrTwoSquareRule) {
+		t.Errorf("Expected two-square rule violation, got: %v", err)
+	}
+
+	if err := engine.CheckRepetition(history, testMove(0, 5, 0, 4, &alice)); err != nil {
+		t.Errorf("Expected moving to a new square to be allowed, got: %v", err)
+	}
+
+	if err := engine.CheckRepetition(history[:4], testMove(0, 6, 0, 5, &alice)); err != nil {
+		t.Errorf("Expected the third move between two squares to be allowed, got: %v", err)
+	}
+}
+
+func TestCheckRepetitionTwoSquareRuleOtherPiece(t *testing.T) {
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+
+	history := []engine.Move{
+		testMove(0, 6, 0, 5, &alice), testMove(9, 3, 9, 4, &bob),
+		testMove(5, 6, 5, 5, &alice), testMove(9, 4, 9, 3, &bob),
+		testMove(0, 5, 0, 6, &alice), testMove(9, 3, 9, 4, &bob),
+	}
+
+	if err := engine.CheckRepetition(history, testMove(0, 6, 0, 5, &alice)); err != nil {
+		t.Errorf("Expected move to be allowed when another piece moved in between, got: %v", err)
+	}
+}
+
+func TestCheckRepetitionTwoSquareRuleScout(t *testing.T) {
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+
+	// Scout moves between (0,6) and (0,2), the squares in between count as well
+	history := []engine.Move{
+		testMove(0, 6, 0, 2, &alice), testMove(9, 3, 9, 4, &bob),
+		testMove(0, 2, 0, 5, &alice), testMove(9, 4, 9, 3, &bob),
+		testMove(0, 5, 0, 3, &alice), testMove(9, 3, 9, 4, &bob),
+	}
+
+	err := engine.CheckRepetition(history, testMove(0, 3, 0, 4, &alice))
+	if !errors.Is(err, engine.ErrTwoSquareRule) {
+		t.Errorf("Expected two-square rule violation for scout, got: %v", err)
+	}
+}
+
+func TestCheckRepetitionMoreSquaresRuleNoChase(t *testing.T) {
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+
+	// Bob's piece moves back and forth, but never away from Alice's piece
+	history := []engine.Move{
+		testMove(4, 7, 4, 6, &alice),
+		testMove(9, 3, 9, 4, &bob),
+		testMove(4, 6, 5, 6, &alice),
+		testMove(9, 4, 9, 3, &bob),
+	}
+
+	if err := engine.CheckRepetition(history, testMove(5, 6, 4, 6, &alice)); err != nil {
+		t.Errorf("Expected move to be allowed when nothing is chased, got: %v", err)
+	}
+}
+
+func TestCheckRepetitionMoreSquaresRuleRepeatedPosition(t *testing.T) {
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+
+	// Alice's piece chases Bob's piece back and forth between two squares
+	history := []engine.Move{
+		testMove(4, 7, 4, 6, &alice), // alice at (4,6) threatens bob at (4,5)
+		testMove(4, 5, 5, 5, &bob),   // bob flees to (5,5)
+		testMove(4, 6, 5, 6, &alice), // alice follows to (5,6)
+		testMove(5, 5, 4, 5, &bob),   // bob flees back to (4,5)
+	}
+
+	// Alice returning to (4,6) recreates the position after her first move
+	err := engine.CheckRepetition(history, testMove(5, 6, 4, 6, &alice))
+	if !errors.Is(err, engine.ErrMoreSquaresRule) {
+		t.Errorf("Expected more-squares rule violation, got: %v", err)
+	}
+
+	// Threatening from another square is still allowed
+	if err := engine.CheckRepetition(history, testMove(5, 6, 5, 5, &alice)); err != nil {
+		t.Errorf("Expected a new chasing position to be allowed, got: %v", err)
+	}
+
+	// Bob is not chasing, so he may repeat freely
+	bobHistory := append(history, testMove(5, 6, 5, 7, &alice))
+	if err := engine.CheckRepetition(bobHistory, testMove(4, 5, 5, 5, &bob)); err != nil {
+		t.Errorf("Expected chased player to be allowed to move, got: %v", err)
+	}
+}
+
+func TestListLegalMovesFiltersRepetitions(t *testing.T) {
+	board := engine.NewBoard()
+	alice := engine.NewPlayer(0, "Alice", "red")
+	bob := engine.NewPlayer(1, "Bob", "blue")
+	board.SetPieceAt(engine.NewPosition(0, 5), engine.NewPiece(models.Sergeant, &alice))
+
+	history := []engine.Move{
+		testMove(0, 6, 0, 5, &alice), testMove(9, 3, 9, 4, &bob),
+		testMove(0, 5, 0, 6, &alice), testMove(9, 4, 9, 3, &bob),
+		testMove(0, 6, 0, 5, &alice), testMove(9, 3, 9, 4, &bob),
+	}
+
+	moves, err := board.ListLegalMoves(engine.NewPosition(0, 5), history)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for _, m := range moves {
+		if m.GetTo() == engine.NewPosition(0, 6) {
+			t.Errorf("Expected move back to (0,6) to be filtered out")
+		}
+		if m.GetPlayer() != &alice {
+			t.Errorf("Expected legal moves to belong to the piece owner")
+		}
+	}
+
+	if len(moves) != 2 {
+		t.Errorf("Expected 2 legal moves, got %d", len(moves))
+	}
+}
@@ -0,0 +1,103 @@
+package game_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestBuiltInBoardLayoutsAreValid(t *testing.T) {
+	for _, name := range models.BoardLayoutNames {
+		layout, err := models.BoardLayoutByName(name)
+		if err != nil {
+			t.Fatalf("Expected board layout %s to exist, got: %v", name, err)
+		}
+		if err := layout.Validate(); err != nil {
+			t.Errorf("Expected board layout %s to be valid, got: %v", name, err)
+		}
+	}
+	if _, err := models.BoardLayoutByName("hexagon"); err == nil {
+		t.Error("Expected an error for an unknown board layout")
+	}
+}
+
+func TestRuleSetValidateBoard(t *testing.T) {
+	testCases := []struct {
+		name   string
+		change func(l *models.BoardLayout)
+	}{
+		{"TooLarge", func(l *models.BoardLayout) { l.Width = 11 }},
+		{"TooSmall", func(l *models.BoardLayout) { l.Height = 3 }},
+		{"LakeOffBoard", func(l *models.BoardLayout) { l.Lakes = append(l.Lakes, models.Square{X: 10, Y: 4}) }},
+		{"LakeTwice", func(l *models.BoardLayout) { l.Lakes = append(l.Lakes, l.Lakes[0]) }},
+		{"LakeInSetupRows", func(l *models.BoardLayout) { l.Lakes = append(l.Lakes, models.Square{X: 0, Y: 3}) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := models.BarrageRules()
+			tc.change(&rules.Board)
+			if err := rules.Validate(); err == nil {
+				t.Error("Expected an invalid board to be rejected")
+			}
+		})
+	}
+
+	// Rule sets stored without a board are played on the classic board
+	rules := models.ClassicRules()
+	rules.Board = models.BoardLayout{}
+	if err := rules.Validate(); err != nil || rules.Layout().Width != 10 || len(rules.Layout().Lakes) != 8 {
+		t.Errorf("Expected a rule set without a board to use the classic board, got: %+v, %v", rules.Layout(), err)
+	}
+}
+
+func TestWithBoardReducesSetupRows(t *testing.T) {
+	rules := models.BarrageRules().WithBoard(models.SmallBoard())
+	if rules.Name != models.RuleSetCustom || rules.SetupRows != 3 || rules.SetupCells() != 24 {
+		t.Errorf("Expected a custom rule set with 3 setup rows of 8 squares, got: %+v", rules)
+	}
+	if err := rules.Validate(); err != nil {
+		t.Errorf("Expected Barrage to fit the small board, got: %v", err)
+	}
+	if err := models.ClassicRules().WithBoard(models.SmallBoard()).Validate(); err == nil {
+		t.Error("Expected the classic army not to fit the small board")
+	}
+}
+
+func TestGameOnSmallBoard(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Player 1", "red")
+	player2 := engine.NewPlayer(1, "Player 2", "blue")
+	rules := models.BarrageRules().WithBoard(models.SmallBoard())
+	g := game.QuickStartWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2),
+		rules, rand.New(rand.NewPCG(3, 4)))
+
+	if g.Board.GetWidth() != 8 || g.Board.GetHeight() != 8 {
+		t.Fatalf("Expected an 8x8 board, got %dx%d", g.Board.GetWidth(), g.Board.GetHeight())
+	}
+	for _, piece := range player1.GetAlivePieces() {
+		if pos, _ := player1.GetPiecePosition(piece); pos.Y < 5 {
+			t.Errorf("Expected player 1 to set up in rows 5-7, got a piece in row %d", pos.Y)
+		}
+	}
+	for _, piece := range player2.GetAlivePieces() {
+		if pos, _ := player2.GetPiecePosition(piece); pos.Y > 2 {
+			t.Errorf("Expected player 2 to set up in rows 0-2, got a piece in row %d", pos.Y)
+		}
+	}
+
+	state := g.GetInitialBoardState()
+	if len(state) != 8 || len(state[0]) != 8 {
+		t.Fatalf("Expected an 8x8 initial state, got %d rows", len(state))
+	}
+	replayed, err := game.ReplayWithRules(rules, state, nil)
+	if err != nil {
+		t.Fatalf("Expected no error replaying the initial state, got: %v", err)
+	}
+	if replayed.Board.String() != g.Board.String() {
+		t.Errorf("Expected the replayed board to equal the original\n%s\ngot\n%s", g.Board, replayed.Board)
+	}
+	if _, err := game.ReplayWithRules(models.BarrageRules(), state, nil); err == nil {
+		t.Error("Expected an 8x8 initial state not to replay on the classic board")
+	}
+}
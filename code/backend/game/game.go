@@ -19,6 +19,7 @@ type CombatResult struct {
 	DefenderPiece    *engine.Piece
 	AttackerPosition engine.Position
 	DefenderPosition engine.Position
+	DefenderHidden   bool // silent defense: the defender won and its rank is not revealed to the attacker
 }
 
 type Game struct {
@@ -30,15 +31,24 @@ type Game struct {
 	MoveHistory       []engine.Move
 	HistoricalHistory []models.HistoricalMove
 	InitialState      [][]models.PieceData
+	Rules             models.RuleSet
 	LastCombat        *CombatResult // Track last combat for broadcasting
 	round             int
 	winner            *engine.Player
 	winCause          WinCause
 	gameOver          bool
+	undoEnabled       bool // keep the undo records of moves, see EnableUndo
+	undoStack         []undoRecord
+	redoStack         []redoRecord
 }
 
 func NewGame(controller1, controller2 engine.PlayerController) *Game {
-	board := engine.NewBoard()
+	return NewGameWithRules(controller1, controller2, models.ClassicRules())
+}
+
+// NewGameWithRules creates a game played with the rule set, which decides the board, the armies and how they are set up
+func NewGameWithRules(controller1, controller2 engine.PlayerController, rules models.RuleSet) *Game {
+	board := engine.NewBoardWithLayout(rules.Layout())
 	player1 := controller1.GetPlayer()
 	player2 := controller2.GetPlayer()
 
@@ -50,12 +60,19 @@ func NewGame(controller1, controller2 engine.PlayerController) *Game {
 		CurrentController: controller1,
 		MoveHistory:       []engine.Move{},
 		HistoricalHistory: []models.HistoricalMove{},
+		Rules:             rules,
 		round:             1,
 		gameOver:          false,
 	}
 }
 
 func (g *Game) NextTurn() {
+	g.advanceTurn()
+}
+
+// advanceTurn passes the turn to the other player, or ends the game if a flag was captured.
+// It returns the pieces that were hidden again because a new round started.
+func (g *Game) advanceTurn() []*engine.Piece {
 	switch {
 	case g.Players[0].HasWon():
 		g.winner = g.Players[0]
@@ -73,8 +90,9 @@ func (g *Game) NextTurn() {
 		g.CurrentController = g.PlayerControllers[0]
 		g.round++
 		// Hide all revealed pieces at the start of a new round
-		g.HideAllRevealedPieces()
+		return g.hideAllRevealedPieces()
 	}
+	return nil
 }
 
 func (g *Game) IsGameOver() bool {
@@ -93,6 +111,15 @@ func (g *Game) GetWinner() *engine.Player {
 	return g.winner
 }
 
+// GetWinnerID returns the ID of the winner, 0 or 1, nil if there is none
+func (g *Game) GetWinnerID() *int {
+	if g.winner == nil {
+		return nil
+	}
+	id := g.winner.GetID()
+	return &id
+}
+
 func (g *Game) GetWinCause() WinCause {
 	return g.winCause
 }
@@ -104,15 +131,35 @@ func (g *Game) SetWinner(player *engine.Player, cause WinCause) {
 	player.SetWinner()
 }
 
+// SetDraw ends the game without a winner, e.g. when the turn limit was reached with equal armies
+func (g *Game) SetDraw(cause WinCause) {
+	g.winner = nil
+	g.winCause = cause
+	g.gameOver = true
+}
+
 // MakeMove makes a move on the game board and resolves any combat that may occur.
-// If the move results in combat, the attacker and defender pieces are revealed.
+// If the move results in combat, the attacker and defender pieces are revealed, except for a winning defender
+// under silent defense. The special rules of the rule set decide ties and rescues, see models.RuleSet.
 // The function returns a slice of two pieces: the attacker and defender pieces in the combat.
 // If no combat occurs, the slice will contain only the attacker piece.
 // The game state is updated after the move, and all observers (AI) are notified of the move.
 // The observers are given the opportunity to analyze the move and observe any combat that may have occurred.
+// The move can be taken back with UnmakeMove if undo is enabled, see EnableUndo.
 func (g *Game) MakeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece {
+	g.redoStack = g.redoStack[:0]
+	return g.makeMove(move, piece)
+}
+
+func (g *Game) makeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece {
+	var record undoRecord
+	if g.undoEnabled {
+		record = g.newUndoRecord(move, piece)
+	}
+	piece.SetMoved(true)
 	target := g.Board.GetPieceAt(move.GetTo())
 	if target != nil {
+		targetRevealed := target.IsRevealed()
 		piece.Reveal()
 		target.Reveal()
 
@@ -124,7 +171,7 @@ func (g *Game) MakeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece
 			DefenderPosition: move.GetTo(),
 		}
 
-		result := piece.Attack(target)
+		result := piece.AttackWithRules(target, g.Rules)
 		piece, target = result[0], result[1]
 		if !piece.IsAlive() {
 			err := g.Board.RemovePieceAt(move.GetFrom())
@@ -142,6 +189,12 @@ func (g *Game) MakeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece
 			g.Board.MovePiece(move, piece)
 			piece.GetOwner().UpdatePiecePosition(piece, move.GetTo())
 		}
+
+		// Silent defense: a defender that wins keeps its rank hidden, unless it was revealed already
+		if g.Rules.SilentDefense && !piece.IsAlive() && target.IsAlive() && !targetRevealed {
+			target.Hide()
+			g.LastCombat.DefenderHidden = true
+		}
 	} else {
 		// No combat - clear any previous combat result
 		g.LastCombat = nil
@@ -149,6 +202,12 @@ func (g *Game) MakeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece
 		piece.GetOwner().UpdatePiecePosition(piece, move.GetTo())
 	}
 
+	var rescued *engine.Piece
+	if g.Rules.Rescue && piece.IsAlive() {
+		rescued, record.rescuedAt = g.rescue(piece, move.GetTo())
+		record.rescued = rescued
+	}
+
 	// Record historical move
 	histMove := models.HistoricalMove{
 		MoveIndex: len(g.HistoricalHistory),
@@ -179,6 +238,7 @@ func (g *Game) MakeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece
 			Rank:    string(defenderType.GetRank()),
 			OwnerID: g.LastCombat.DefenderPiece.GetOwner().GetID(),
 		}
+		histMove.DefenderHidden = g.LastCombat.DefenderHidden
 		// Determine result
 		attackerAlive := g.LastCombat.AttackerPiece.IsAlive()
 		defenderAlive := g.LastCombat.DefenderPiece.IsAlive()
@@ -198,52 +258,114 @@ func (g *Game) MakeMove(move *engine.Move, piece *engine.Piece) []*engine.Piece
 		}
 	}
 
+	if rescued != nil {
+		histMove.Rescue = &models.Rescue{
+			Piece: models.PieceData{
+				Type:    rescued.GetType().GetName(),
+				Rank:    string(rescued.GetType().GetRank()),
+				OwnerID: rescued.GetOwner().GetID(),
+			},
+			X: record.rescuedAt.X,
+			Y: record.rescuedAt.Y,
+		}
+	}
+
 	g.MoveHistory = append(g.MoveHistory, *move)
 	g.HistoricalHistory = append(g.HistoricalHistory, histMove)
 
-	// Notify all observers (AI)
+	// Notify all observers (AI): the opponent analyzes the move, both players see the combat and the rescue.
+	// A defender hidden by silent defense is only shown to its owner.
 	round := g.GetRound()
 	for _, ctrl := range g.PlayerControllers {
-		if ctrl.GetPlayer() == move.GetPlayer() {
-			continue
-		}
-
-		if analyzer, ok := ctrl.(interface {
-			AnalyzeMove(engine.Move, *engine.Player, int)
-		}); ok {
-			analyzer.AnalyzeMove(*move, move.GetPlayer(), round)
+		if ctrl.GetPlayer() != move.GetPlayer() {
+			if analyzer, ok := ctrl.(interface {
+				AnalyzeMove(engine.ViewMove, int)
+			}); ok {
+				analyzer.AnalyzeMove(engine.NewViewMove(*move), round)
+			}
 		}
 
 		if g.LastCombat != nil && g.LastCombat.Occurred {
 			if observer, ok := ctrl.(interface {
-				ObserveCombat(engine.Position, engine.Position, *engine.Piece, *engine.Piece, int)
+				ObserveCombat(engine.Position, engine.Position, engine.SeenPiece, engine.SeenPiece, int)
 			}); ok {
+				defender := engine.NewSeenPiece(g.LastCombat.DefenderPiece)
+				if g.LastCombat.DefenderHidden && ctrl.GetPlayer() == move.GetPlayer() {
+					defender.Type = nil
+				}
 				observer.ObserveCombat(
 					g.LastCombat.AttackerPosition,
 					g.LastCombat.DefenderPosition,
-					g.LastCombat.AttackerPiece,
-					g.LastCombat.DefenderPiece,
+					engine.NewSeenPiece(g.LastCombat.AttackerPiece),
+					defender,
 					round,
 				)
 			}
 		}
+
+		if rescued != nil {
+			if observer, ok := ctrl.(interface {
+				ObserveRescue(engine.Position, engine.SeenPiece, int)
+			}); ok {
+				observer.ObserveRescue(record.rescuedAt, engine.NewSeenPiece(rescued), round)
+			}
+		}
 	}
 
-	g.NextTurn()
+	record.hidden = g.advanceTurn()
+	if g.undoEnabled {
+		g.undoStack = append(g.undoStack, record)
+	}
 	return []*engine.Piece{piece, target}
 }
 
+// ViewFor returns the board as seen by the given player, hiding the enemy pieces that are not revealed.
+func (g *Game) ViewFor(player *engine.Player) *engine.PlayerView {
+	return engine.NewPlayerViewWithRules(g.Board, player, g.MoveHistory, g.round, g.Rules)
+}
+
+// ListLegalMoves returns the moves of the piece at the given position,
+// leaving out moves that would break the two-square or more-squares rule for its owner.
+func (g *Game) ListLegalMoves(pos engine.Position) ([]engine.Move, error) {
+	return g.Board.ListLegalMoves(pos, g.repetitionHistory())
+}
+
+// ValidateMove checks whether the move may be played now: it must be the turn of the moving player,
+// the move must be valid on the board (see engine.ValidateMove) and it must not break the repetition rules.
+func (g *Game) ValidateMove(move *engine.Move) error {
+	if move.GetPlayer() == nil || g.CurrentPlayer == nil || move.GetPlayer().GetID() != g.CurrentPlayer.GetID() {
+		return engine.ErrNotYourTurn
+	}
+	if err := engine.ValidateMove(g.Board, move); err != nil {
+		return err
+	}
+	return g.CheckRepetition(move)
+}
+
+// CheckRepetition checks the move against the repetition rules, based on the moves played so far.
+// Every move passes if the rule set turns the repetition rules off.
+func (g *Game) CheckRepetition(move *engine.Move) error {
+	return engine.CheckRepetition(g.repetitionHistory(), *move)
+}
+
+// repetitionHistory returns the history the repetition rules are checked against, none if the rules are off.
+func (g *Game) repetitionHistory() []engine.Move {
+	if !g.Rules.RepetitionRules {
+		return nil
+	}
+	return g.MoveHistory
+}
+
 // GetInitialBoardState returns the full board state as PieceData (for history)
 func (g *Game) GetInitialBoardState() [][]models.PieceData {
 	if g.InitialState != nil {
 		return g.InitialState
 	}
 	field := g.Board.GetField()
-	state := make([][]models.PieceData, 10)
-	for y := range 10 {
-		state[y] = make([]models.PieceData, 10)
-		for x := range 10 {
-			piece := field[y][x]
+	state := make([][]models.PieceData, len(field))
+	for y, row := range field {
+		state[y] = make([]models.PieceData, len(row))
+		for x, piece := range row {
 			if piece != nil {
 				state[y][x] = models.PieceData{
 					Type:    piece.GetType().GetName(),
@@ -286,23 +408,26 @@ func (g *Game) HideCombatPieces() {
 // HideAllRevealedPieces hides all revealed pieces on the board
 // Called at the start of each new round to reset piece visibility
 func (g *Game) HideAllRevealedPieces() {
-	field := g.Board.GetField()
-	for y := range 10 {
-		for x := range 10 {
-			piece := field[y][x]
+	g.hideAllRevealedPieces()
+}
+
+func (g *Game) hideAllRevealedPieces() []*engine.Piece {
+	var hidden []*engine.Piece
+	for _, row := range g.Board.GetField() {
+		for _, piece := range row {
 			if piece != nil && piece.IsAlive() && piece.IsRevealed() {
 				piece.Hide()
+				hidden = append(hidden, piece)
 			}
 		}
 	}
+	return hidden
 }
 
 // InitializePieces scans board and tracks all pieces for both players (call once at game start)
 func (g *Game) InitializePieces() {
-	field := g.Board.GetField()
-	for y := range 10 {
-		for x := range 10 {
-			piece := field[y][x]
+	for y, row := range g.Board.GetField() {
+		for x, piece := range row {
 			if piece != nil {
 				pos := engine.NewPosition(x, y)
 				piece.GetOwner().AddPiece(piece, pos)
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"digital-innovation/stratego/engine"
+	"slices"
+)
+
+// cloneController stands in for the controller of a player in a cloned game.
+// It never makes moves and does not observe them, so moves explored on a clone
+// are not reported to the AIs playing the original game.
+type cloneController struct {
+	player         *engine.Player
+	controllerType engine.ControllerType
+}
+
+func (c *cloneController) GetPlayer() *engine.Player {
+	return c.player
+}
+
+func (c *cloneController) GetControllerType() engine.ControllerType {
+	return c.controllerType
+}
+
+func (c *cloneController) MakeMove(view *engine.PlayerView) engine.Move {
+	return engine.Move{}
+}
+
+// Clone returns a deep copy of the game that can be changed without affecting the original.
+// Players, pieces, the board, the move history and LastCombat are copied, and the copies refer
+// to each other the same way the originals do. The controllers are replaced by passive stand-ins
+// of the same type, so AIs observing the original game are not notified of moves on the clone.
+// Undo is enabled on the clone, moves made before cloning cannot be taken back on it with UnmakeMove.
+func (g *Game) Clone() *Game {
+	players := make(map[*engine.Player]*engine.Player, len(g.Players))
+	pieces := make(map[*engine.Piece]*engine.Piece, 80)
+
+	clone := &Game{
+		Players:           make([]*engine.Player, 0, len(g.Players)),
+		PlayerControllers: make([]engine.PlayerController, 0, len(g.PlayerControllers)),
+		MoveHistory:       make([]engine.Move, 0, len(g.MoveHistory)),
+		HistoricalHistory: slices.Clone(g.HistoricalHistory),
+		InitialState:      g.InitialState,
+		Rules:             g.Rules,
+		round:             g.round,
+		winCause:          g.winCause,
+		gameOver:          g.gameOver,
+		undoEnabled:       true,
+	}
+
+	for i, player := range g.Players {
+		copied := player.Clone()
+		players[player] = copied
+		for _, piece := range player.GetAlivePieces() {
+			pos, _ := player.GetPiecePosition(piece)
+			copiedPiece := piece.Clone(copied)
+			pieces[piece] = copiedPiece
+			copied.AddPiece(copiedPiece, pos)
+		}
+
+		ctrl := &cloneController{player: copied, controllerType: g.PlayerControllers[i].GetControllerType()}
+		clone.Players = append(clone.Players, copied)
+		clone.PlayerControllers = append(clone.PlayerControllers, ctrl)
+		if g.CurrentController == g.PlayerControllers[i] {
+			clone.CurrentController = ctrl
+		}
+	}
+
+	clonePiece := func(piece *engine.Piece) *engine.Piece {
+		if piece == nil {
+			return nil
+		}
+		if copied, ok := pieces[piece]; ok {
+			return copied
+		}
+		copied := piece.Clone(players[piece.GetOwner()])
+		pieces[piece] = copied
+		return copied
+	}
+
+	clone.Board = g.Board.CloneWith(clonePiece)
+	clone.CurrentPlayer = players[g.CurrentPlayer]
+	clone.winner = players[g.winner]
+
+	for _, move := range g.MoveHistory {
+		player := move.GetPlayer()
+		if copied, ok := players[player]; ok {
+			player = copied
+		}
+		clone.MoveHistory = append(clone.MoveHistory, engine.NewMove(move.GetFrom(), move.GetTo(), player))
+	}
+
+	if g.LastCombat != nil {
+		combat := *g.LastCombat
+		combat.AttackerPiece = clonePiece(combat.AttackerPiece)
+		combat.DefenderPiece = clonePiece(combat.DefenderPiece)
+		clone.LastCombat = &combat
+	}
+
+	return clone
+}
@@ -0,0 +1,51 @@
+package game_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	for range 20 {
+		move, ok := randomMove(g, rng)
+		if !ok || g.IsGameOver() {
+			break
+		}
+		g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+	}
+
+	clone := g.Clone()
+	before := snapshot(g)
+	if got := snapshot(clone); got != before {
+		t.Fatalf("Expected the clone to equal the original\nexpected: %s\ngot:      %s", before, got)
+	}
+
+	for y := range 10 {
+		for x := range 10 {
+			pos := engine.NewPosition(x, y)
+			original, copied := g.Board.GetPieceAt(pos), clone.Board.GetPieceAt(pos)
+			if original != nil && (original == copied || copied.GetOwner() == original.GetOwner()) {
+				t.Fatalf("Expected pieces of the clone to be copies owned by copied players at %v", pos)
+			}
+		}
+	}
+
+	for range 50 {
+		move, ok := randomMove(clone, rng)
+		if !ok || clone.IsGameOver() {
+			break
+		}
+		clone.MakeMove(&move, clone.Board.GetPieceAt(move.GetFrom()))
+	}
+
+	if got := snapshot(g); got != before {
+		t.Errorf("Expected moves on the clone not to change the original\nexpected: %s\ngot:      %s", before, got)
+	}
+}
@@ -0,0 +1,443 @@
+package game
+
+import (
+	"bufio"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A game record is the text form of a complete game: headers, the setup rows of both players and one move per line.
+//
+//	Rules: classic
+//	Red: Alice
+//	Blue: Bob
+//	Result: red
+//	Cause: flag_captured
+//
+//	Setup 10: 3 B 0 B 3 ...
+//	...
+//	Setup 1: 2 2 7 B ...
+//
+//	B4-B5
+//	C7xC6 7x4
+//	D9-D10 +M@A1
+//
+// Squares are a column letter and a row number, A1 is the bottom left corner of the board, the back row of red.
+// Pieces are written by their rank, see models.PieceType, "." is an empty square of a smaller army.
+// A move is a plain move like "B4-B5" or an attack like "C7xC6 7x4" with the ranks of the attacker and the defender,
+// followed by "+<rank>@<square>" if it rescued a piece. Red moves first.
+// The rules are the name of a built-in rule set or a custom rule set as JSON, the result is red, blue, draw or *
+// for an unfinished game. Blank lines and lines starting with # are ignored.
+
+// Results of a game record
+const (
+	recordResultRed        = "red"
+	recordResultBlue       = "blue"
+	recordResultDraw       = "draw"
+	recordResultUnfinished = "*"
+)
+
+// ErrUnknownInitialState is returned when exporting a game that was played from a setup that was not recorded
+var ErrUnknownInitialState = errors.New("the initial state of the game is unknown")
+
+var recordMovePattern = regexp.MustCompile(`^([A-Z][0-9]+)([-x])([A-Z][0-9]+)(?: (\S)x(\S))?(?: \+(\S)@([A-Z][0-9]+))?$`)
+
+// ExportRecord writes a game as a game record. The game must know its initial state, see Game.InitialState,
+// unless no move was made yet.
+func ExportRecord(g *Game) (string, error) {
+	state := g.InitialState
+	if state == nil {
+		if len(g.HistoricalHistory) > 0 {
+			return "", ErrUnknownInitialState
+		}
+		state = g.GetInitialBoardState()
+	}
+	rules, err := recordRules(g.Rules)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rules: %s\n", rules)
+	fmt.Fprintf(&b, "Red: %s\n", recordName(g.Players[0].GetName()))
+	fmt.Fprintf(&b, "Blue: %s\n", recordName(g.Players[1].GetName()))
+	fmt.Fprintf(&b, "Result: %s\n", recordResult(g))
+	if g.winCause != "" {
+		fmt.Fprintf(&b, "Cause: %s\n", g.winCause)
+	}
+
+	b.WriteString("\n")
+	height := len(state)
+	_, blueLast := SetupRowRange(g.Rules, 1)
+	redFirst, _ := SetupRowRange(g.Rules, 0)
+	for y, row := range state {
+		if y > blueLast && y < redFirst {
+			continue
+		}
+		cells := make([]string, len(row))
+		for x, data := range row {
+			cells[x] = "."
+			if data.OwnerID >= 0 {
+				cells[x] = data.Rank
+			}
+		}
+		fmt.Fprintf(&b, "Setup %d: %s\n", height-y, strings.Join(cells, " "))
+	}
+
+	b.WriteString("\n")
+	for _, move := range g.HistoricalHistory {
+		b.WriteString(recordMove(move, height))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// recordRules returns the name of a built-in rule set, or the rule set as JSON if it was changed
+func recordRules(rules models.RuleSet) (string, error) {
+	if builtIn, err := models.RuleSetByName(rules.Name); err == nil && reflect.DeepEqual(builtIn, rules) {
+		return rules.Name, nil
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return "", fmt.Errorf("failed to write the rules: %v", err)
+	}
+	return string(data), nil
+}
+
+// recordName returns a player name on a single line
+func recordName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// recordResult returns the result of the game as written in a game record
+func recordResult(g *Game) string {
+	switch {
+	case !g.gameOver:
+		return recordResultUnfinished
+	case g.winner == g.Players[0]:
+		return recordResultRed
+	case g.winner == g.Players[1]:
+		return recordResultBlue
+	default:
+		return recordResultDraw
+	}
+}
+
+// recordMove writes a move of the history in coordinate notation
+func recordMove(move models.HistoricalMove, height int) string {
+	from := recordSquare(move.FromX, move.FromY, height)
+	to := recordSquare(move.ToX, move.ToY, height)
+	var text string
+	if move.Defender == nil {
+		text = from + "-" + to
+	} else {
+		text = fmt.Sprintf("%sx%s %sx%s", from, to, move.Attacker.Rank, move.Defender.Rank)
+	}
+	if move.Rescue != nil {
+		text += fmt.Sprintf(" +%s@%s", move.Rescue.Piece.Rank, recordSquare(move.Rescue.X, move.Rescue.Y, height))
+	}
+	return text
+}
+
+// recordSquare writes a square as a column letter and a row number counted from the bottom of the board
+func recordSquare(x, y, height int) string {
+	return fmt.Sprintf("%c%d", 'A'+x, height-y)
+}
+
+// parseRecordSquare reads a square written by recordSquare and checks that it lies on the board
+func parseRecordSquare(square string, board *engine.Board) (engine.Position, error) {
+	row, err := strconv.Atoi(square[1:])
+	if err != nil {
+		return engine.Position{}, fmt.Errorf("invalid square %q", square)
+	}
+	pos := engine.NewPosition(int(square[0]-'A'), board.GetHeight()-row)
+	if !board.IsInBounds(pos) {
+		return engine.Position{}, fmt.Errorf("square %s is not on the board", square)
+	}
+	return pos, nil
+}
+
+// gameRecord is a game record as read, before it is played through the engine
+type gameRecord struct {
+	rules  models.RuleSet
+	names  [2]string
+	result string
+	cause  WinCause
+	setup  map[int][]string // cells of the setup rows by row number
+	moves  []string
+	lines  []int // line numbers of the moves
+}
+
+// ImportRecord reads a game record and plays it through the engine, which checks the setups of both players,
+// that every move is legal and that its combat and rescue match the record.
+// A result the moves do not lead to, e.g. a resignation, is taken from the record.
+// Both players get human controllers, so nothing moves on its own.
+func ImportRecord(r io.Reader) (*Game, error) {
+	record, err := readRecord(r)
+	if err != nil {
+		return nil, err
+	}
+
+	player1 := engine.NewPlayer(0, record.names[0], "red")
+	player2 := engine.NewPlayer(1, record.names[1], "blue")
+	g := NewGameWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), record.rules)
+
+	state, err := recordState(record, g.Board)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := setupFromState(g, state); err != nil {
+		return nil, err
+	}
+
+	for i, text := range record.moves {
+		if g.IsGameOver() {
+			return nil, fmt.Errorf("line %d: the game is over after move %d", record.lines[i], i)
+		}
+		if err := playRecordMove(g, text); err != nil {
+			return nil, fmt.Errorf("line %d: move %d %q: %w", record.lines[i], i+1, text, err)
+		}
+	}
+
+	if err := applyRecordResult(g, record); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// readRecord reads the headers, setup rows and moves of a game record
+func readRecord(r io.Reader) (*gameRecord, error) {
+	record := &gameRecord{
+		rules: models.ClassicRules(),
+		names: [2]string{"Player 1", "Player 2"},
+		setup: make(map[int][]string),
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := record.readLine(line, lineNumber); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the game record: %v", err)
+	}
+	return record, nil
+}
+
+// readLine reads a header, setup row or move of a game record
+func (record *gameRecord) readLine(line string, lineNumber int) error {
+	key, value, isHeader := strings.Cut(line, ":")
+	if !isHeader {
+		if len(record.setup) == 0 {
+			return errors.New("move before the setup")
+		}
+		record.moves = append(record.moves, line)
+		record.lines = append(record.lines, lineNumber)
+		return nil
+	}
+	if len(record.moves) > 0 {
+		return fmt.Errorf("%s after the moves", key)
+	}
+
+	value = strings.TrimSpace(value)
+	if rowText, ok := strings.CutPrefix(key, "Setup "); ok {
+		row, err := strconv.Atoi(rowText)
+		if err != nil {
+			return fmt.Errorf("invalid setup row %q", rowText)
+		}
+		if _, ok := record.setup[row]; ok {
+			return fmt.Errorf("setup row %d is listed twice", row)
+		}
+		record.setup[row] = strings.Fields(value)
+		return nil
+	}
+	if len(record.setup) > 0 {
+		return fmt.Errorf("header %s after the setup", key)
+	}
+
+	switch key {
+	case "Rules":
+		return record.readRules(value)
+	case "Red":
+		record.names[0] = value
+	case "Blue":
+		record.names[1] = value
+	case "Result":
+		switch value {
+		case recordResultRed, recordResultBlue, recordResultDraw, recordResultUnfinished:
+			record.result = value
+		default:
+			return fmt.Errorf("invalid result %q, expected red, blue, draw or *", value)
+		}
+	case "Cause":
+		switch cause := WinCause(value); cause {
+		case WinCauseFlagCaptured, WinCauseNoMovablePieces, WinCauseMaxTurns:
+			record.cause = cause
+		default:
+			return fmt.Errorf("invalid cause %q", value)
+		}
+	default:
+		return fmt.Errorf("unknown header %q", key)
+	}
+	return nil
+}
+
+// readRules reads the name of a built-in rule set or a rule set as JSON
+func (record *gameRecord) readRules(value string) error {
+	var rules models.RuleSet
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), &rules); err != nil {
+			return fmt.Errorf("invalid rules: %v", err)
+		}
+	} else {
+		var err error
+		if rules, err = models.RuleSetByName(value); err != nil {
+			return err
+		}
+	}
+	if err := rules.Validate(); err != nil {
+		return fmt.Errorf("invalid rules: %v", err)
+	}
+	record.rules = rules
+	return nil
+}
+
+// recordState builds the initial state of the board from the setup rows of a game record.
+// The owner of a piece is given by the setup rows it stands in.
+func recordState(record *gameRecord, board *engine.Board) ([][]models.PieceData, error) {
+	width, height := board.GetWidth(), board.GetHeight()
+	state := make([][]models.PieceData, height)
+	for y := range state {
+		state[y] = make([]models.PieceData, width)
+		for x := range state[y] {
+			state[y][x] = models.PieceData{OwnerID: -1}
+		}
+	}
+
+	seen := 0
+	for seat := range 2 {
+		first, last := SetupRowRange(record.rules, seat)
+		for y := first; y <= last; y++ {
+			cells, ok := record.setup[height-y]
+			if !ok {
+				return nil, fmt.Errorf("setup row %d is missing", height-y)
+			}
+			seen++
+			if len(cells) != width {
+				return nil, fmt.Errorf("setup row %d must have %d squares, got %d", height-y, width, len(cells))
+			}
+			for x, cell := range cells {
+				if cell == "." {
+					continue
+				}
+				if len(cell) != 1 {
+					return nil, fmt.Errorf("invalid rank %q in setup row %d", cell, height-y)
+				}
+				id, ok := engine.GetPieceIDFromRank(cell[0])
+				if !ok {
+					return nil, fmt.Errorf("invalid rank %q in setup row %d", cell, height-y)
+				}
+				state[y][x] = models.PieceData{Type: engine.GetPieceTypeFromID(id).GetName(), Rank: cell, OwnerID: seat}
+			}
+		}
+	}
+	if seen != len(record.setup) {
+		return nil, errors.New("setup rows outside the setup area of the rules")
+	}
+	return state, nil
+}
+
+// playRecordMove plays a move of a game record for the player to move and checks the combat and rescue it records
+func playRecordMove(g *Game, text string) error {
+	parts := recordMovePattern.FindStringSubmatch(text)
+	if parts == nil {
+		return errors.New("invalid notation")
+	}
+	attack := parts[2] == "x"
+	if attack != (parts[4] != "") {
+		return errors.New("an attack must list the ranks of both pieces, a plain move none")
+	}
+	from, err := parseRecordSquare(parts[1], g.Board)
+	if err != nil {
+		return err
+	}
+	to, err := parseRecordSquare(parts[3], g.Board)
+	if err != nil {
+		return err
+	}
+
+	move := engine.NewMove(from, to, g.CurrentPlayer)
+	if err := g.ValidateMove(&move); err != nil {
+		return err
+	}
+	g.MakeMove(&move, g.Board.GetPieceAt(from))
+	made := g.HistoricalHistory[len(g.HistoricalHistory)-1]
+
+	if !attack && made.Defender != nil {
+		return fmt.Errorf("expected a plain move, got an attack on %s", made.Defender.Type)
+	}
+	if attack {
+		if made.Defender == nil {
+			return errors.New("expected an attack, got a plain move")
+		}
+		if made.Attacker.Rank != parts[4] || made.Defender.Rank != parts[5] {
+			return fmt.Errorf("expected %sx%s, got %sx%s", parts[4], parts[5], made.Attacker.Rank, made.Defender.Rank)
+		}
+	}
+
+	switch {
+	case parts[6] == "" && made.Rescue != nil:
+		return fmt.Errorf("expected no rescue, got %s", made.Rescue.Piece.Type)
+	case parts[6] != "" && made.Rescue == nil:
+		return errors.New("expected a rescue, got none")
+	case parts[6] != "":
+		rescuedAt, err := parseRecordSquare(parts[7], g.Board)
+		if err != nil {
+			return err
+		}
+		if made.Rescue.Piece.Rank != parts[6] || made.Rescue.X != rescuedAt.X || made.Rescue.Y != rescuedAt.Y {
+			return fmt.Errorf("expected rescue %s@%s, got %s@%s", parts[6], parts[7],
+				made.Rescue.Piece.Rank, recordSquare(made.Rescue.X, made.Rescue.Y, g.Board.GetHeight()))
+		}
+	}
+	return nil
+}
+
+// applyRecordResult checks the result of a game record against the played game,
+// or ends the game with it if the moves do not end the game. The cause is optional, stored games do not keep it.
+func applyRecordResult(g *Game, record *gameRecord) error {
+	if g.IsGameOver() {
+		if record.result != "" && record.result != recordResult(g) {
+			return fmt.Errorf("the moves end the game with result %s, the record gives %s", recordResult(g), record.result)
+		}
+		if record.cause != "" && record.cause != g.winCause {
+			return fmt.Errorf("the moves end the game by %s, the record gives %s", g.winCause, record.cause)
+		}
+		return nil
+	}
+
+	switch record.result {
+	case recordResultRed, recordResultBlue:
+		winner := g.Players[0]
+		if record.result == recordResultBlue {
+			winner = g.Players[1]
+		}
+		g.SetWinner(winner, record.cause)
+	case recordResultDraw:
+		g.SetDraw(record.cause)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package game_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// playRandomGame plays up to the given number of random moves from a random setup and keeps the initial state
+func playRandomGame(rules models.RuleSet, seed uint64, moves int) *game.Game {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	rng := rand.New(rand.NewPCG(seed, seed+1))
+	g := game.QuickStartWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules, rng)
+	g.InitialState = g.GetInitialBoardState()
+
+	for range moves {
+		move, ok := randomMove(g, rng)
+		if !ok || g.IsGameOver() {
+			break
+		}
+		g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+	}
+	return g
+}
+
+// exportRecord exports a game record and fails the test on errors
+func exportRecord(t *testing.T, g *game.Game) string {
+	t.Helper()
+	record, err := game.ExportRecord(g)
+	if err != nil {
+		t.Fatalf("Expected no error exporting the game, got: %v", err)
+	}
+	return record
+}
+
+func TestGameRecordRoundTrip(t *testing.T) {
+	specialRules := models.BarrageRules()
+	specialRules.SilentDefense = true
+	specialRules.AggressorAdvantage = true
+	specialRules.Rescue = true
+
+	testCases := []struct {
+		name  string
+		rules models.RuleSet
+		seed  uint64
+	}{
+		{"Classic", models.ClassicRules(), 7},
+		{"Duel", models.DuelRules(), 9},
+		{"SmallBoard", models.BarrageRules().WithBoard(models.SmallBoard()), 3},
+		{"SpecialRules", specialRules, 21},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := playRandomGame(tc.rules, tc.seed, 1000)
+			record := exportRecord(t, g)
+
+			imported, err := game.ImportRecord(strings.NewReader(record))
+			if err != nil {
+				t.Fatalf("Expected the record to import, got: %v\n%s", err, record)
+			}
+			if imported.Board.String() != g.Board.String() {
+				t.Errorf("Expected the imported board to equal the original\n%s\ngot\n%s", g.Board, imported.Board)
+			}
+			if !reflect.DeepEqual(imported.HistoricalHistory, g.HistoricalHistory) {
+				t.Errorf("Expected the imported history to equal the original")
+			}
+			if !reflect.DeepEqual(imported.Rules, tc.rules) {
+				t.Errorf("Expected the rules %+v, got: %+v", tc.rules, imported.Rules)
+			}
+			if imported.Players[0].GetName() != "Alice" || imported.Players[1].GetName() != "Bob" {
+				t.Errorf("Expected the player names to be kept, got: %s and %s", imported.Players[0].GetName(), imported.Players[1].GetName())
+			}
+			if imported.IsGameOver() != g.IsGameOver() || imported.GetWinCause() != g.GetWinCause() {
+				t.Errorf("Expected the result to be kept, got over %t by %s", imported.IsGameOver(), imported.GetWinCause())
+			}
+			if again := exportRecord(t, imported); again != record {
+				t.Errorf("Expected exporting the imported game to give the same record\n%s\ngot\n%s", record, again)
+			}
+		})
+	}
+}
+
+func TestGameRecordNotation(t *testing.T) {
+	g := playRandomGame(models.ClassicRules(), 7, 200)
+	record := exportRecord(t, g)
+
+	if !strings.HasPrefix(record, "Rules: classic\nRed: Alice\nBlue: Bob\n") {
+		t.Errorf("Expected the headers first, got:\n%s", record)
+	}
+	if strings.Count(record, "Setup ") != 8 || !strings.Contains(record, "Setup 10: ") || strings.Contains(record, "Setup 5: ") {
+		t.Errorf("Expected the 4 setup rows of both players, got:\n%s", record)
+	}
+
+	// Rows are counted from the back row of red, the first move leaves its front row 4
+	first := g.HistoricalHistory[0]
+	expected := fmt.Sprintf("%c4-%c%d", 'A'+first.FromX, 'A'+first.ToX, 10-first.ToY)
+	if first.FromY != 6 || !strings.Contains(record, "\n"+expected+"\n") {
+		t.Errorf("Expected the first move of red to be written as %s, got:\n%s", expected, record)
+	}
+	for _, move := range g.HistoricalHistory {
+		if move.Defender != nil && !strings.Contains(record, " "+move.Attacker.Rank+"x"+move.Defender.Rank+"\n") {
+			t.Errorf("Expected attacks to list the ranks, got:\n%s", record)
+			break
+		}
+	}
+}
+
+func TestImportRecordRejectsInvalidRecords(t *testing.T) {
+	g := playRandomGame(models.ClassicRules(), 7, 200)
+	record := exportRecord(t, g)
+	lines := strings.Split(record, "\n")
+	setupLine, attackLine := -1, -1
+	for i, line := range lines {
+		if setupLine < 0 && strings.HasPrefix(line, "Setup 1:") {
+			setupLine = i
+		}
+		if attackLine < 0 && strings.Contains(line, "x") && !strings.Contains(line, ":") {
+			attackLine = i
+		}
+	}
+	if setupLine < 0 || attackLine < 0 {
+		t.Fatalf("Expected a setup row and an attack in the record:\n%s", record)
+	}
+
+	// replace returns the record with line i changed
+	replace := func(i int, line string) string {
+		changed := append([]string{}, lines...)
+		changed[i] = line
+		return strings.Join(changed, "\n")
+	}
+	attack := strings.Fields(lines[attackLine])
+	ranks := strings.Split(attack[1], "x")
+	wrongRank := "M"
+	if ranks[1] == wrongRank {
+		wrongRank = "1"
+	}
+
+	testCases := []struct {
+		name   string
+		record string
+	}{
+		{"UnknownHeader", "Event: final\n" + record},
+		{"UnknownRules", strings.Replace(record, "Rules: classic", "Rules: chess", 1)},
+		{"InvalidRules", strings.Replace(record, "Rules: classic", `Rules: {"name":"custom","army":{"0":2},"setupRows":4}`, 1)},
+		{"InvalidResult", strings.Replace(record, "Result: ", "Result: won", 1)},
+		{"MissingSetupRow", replace(setupLine, "")},
+		{"ShortSetupRow", replace(setupLine, lines[setupLine][:len(lines[setupLine])-2])},
+		{"WrongArmy", replace(setupLine, strings.Replace(lines[setupLine], "B", "M", 1))},
+		{"SetupOutsideSetupRows", strings.Replace(record, "Setup 1:", "Setup 5:", 1)},
+		{"InvalidNotation", replace(attackLine, "B4 to B5")},
+		{"WrongRank", replace(attackLine, attack[0]+" "+ranks[0]+"x"+wrongRank)},
+		{"AttackAsPlainMove", replace(attackLine, strings.Replace(attack[0], "x", "-", 1))},
+		{"IllegalMove", replace(attackLine, "A1-J10")},
+		{"OffBoard", replace(attackLine, "A1-A11")},
+		{"UnexpectedRescue", replace(attackLine, lines[attackLine]+" +M@A1")},
+		{"HeaderAfterMoves", record + "Red: Carol\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := game.ImportRecord(strings.NewReader(tc.record)); err == nil {
+				t.Errorf("Expected an invalid record to be rejected:\n%s", tc.record)
+			}
+		})
+	}
+}
+
+func TestImportRecordResult(t *testing.T) {
+	g := playRandomGame(models.ClassicRules(), 5, 20)
+	if g.IsGameOver() {
+		t.Fatalf("Expected the game not to be over after 20 moves")
+	}
+	record := exportRecord(t, g)
+	if !strings.Contains(record, "Result: *\n") {
+		t.Fatalf("Expected an unfinished game, got:\n%s", record)
+	}
+
+	// A result the moves do not lead to is taken from the record
+	resigned := strings.Replace(record, "Result: *", "Result: blue\nCause: no_movable_pieces", 1)
+	imported, err := game.ImportRecord(strings.NewReader(resigned))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !imported.IsGameOver() || imported.GetWinner() != imported.Players[1] || imported.GetWinCause() != game.WinCauseNoMovablePieces {
+		t.Errorf("Expected blue to win by the record, got winner %v by %s", imported.GetWinner(), imported.GetWinCause())
+	}
+	withoutCause, err := game.ImportRecord(strings.NewReader(strings.Replace(record, "Result: *", "Result: red", 1)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if withoutCause.GetWinner() != withoutCause.Players[0] || withoutCause.GetWinCause() != "" {
+		t.Errorf("Expected red to win without a cause, got winner %v by %q", withoutCause.GetWinner(), withoutCause.GetWinCause())
+	}
+
+	drawn, err := game.ImportRecord(strings.NewReader(strings.Replace(record, "Result: *", "Result: draw\nCause: max_turns", 1)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !drawn.IsGameOver() || drawn.GetWinner() != nil {
+		t.Errorf("Expected a draw, got winner %v", drawn.GetWinner())
+	}
+	if again := exportRecord(t, drawn); !strings.Contains(again, "Result: draw\nCause: max_turns\n") {
+		t.Errorf("Expected the draw to be exported, got:\n%s", again)
+	}
+
+	// A finished game must have the result of its moves
+	finished := playRandomGame(models.BarrageRules(), 21, 1000)
+	if !finished.IsGameOver() {
+		t.Fatalf("Expected the Barrage game to end")
+	}
+	record = exportRecord(t, finished)
+	wrong := strings.Replace(strings.Replace(record, "Result: red", "Result: x", 1), "Result: blue", "Result: red", 1)
+	wrong = strings.Replace(wrong, "Result: x", "Result: blue", 1)
+	if _, err := game.ImportRecord(strings.NewReader(wrong)); err == nil {
+		t.Errorf("Expected a result that contradicts the moves to be rejected:\n%s", wrong)
+	}
+}
+
+func TestExportRecordNeedsInitialState(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	rng := rand.New(rand.NewPCG(1, 2))
+	g := game.QuickStartWithRand(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rng)
+	move, _ := randomMove(g, rng)
+	g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+
+	if _, err := game.ExportRecord(g); !errors.Is(err, game.ErrUnknownInitialState) {
+		t.Errorf("Expected ErrUnknownInitialState, got: %v", err)
+	}
+}
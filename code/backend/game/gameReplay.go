@@ -0,0 +1,113 @@
+package game
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"fmt"
+)
+
+// Replay recreates a stored game from its initial state and recorded moves, checking the result of every move.
+// Pass the first n moves to get the game after move n. Both players get human controllers, so nothing moves on its own.
+func Replay(initialState [][]models.PieceData, moves []models.HistoricalMove) (*Game, error) {
+	return ReplayWithRules(models.ClassicRules(), initialState, moves)
+}
+
+// ReplayWithRules recreates a stored game that was played with the rule set, see Replay.
+func ReplayWithRules(rules models.RuleSet, initialState [][]models.PieceData, moves []models.HistoricalMove) (*Game, error) {
+	player1 := engine.NewPlayer(0, "Player 1", "red")
+	player2 := engine.NewPlayer(1, "Player 2", "blue")
+	g := NewGameWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules)
+
+	if _, err := setupFromState(g, initialState); err != nil {
+		return nil, err
+	}
+	if err := replayMoves(g, moves); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// setupFromState places the pieces of an initial state on the board of a new game with SetupGame.
+// It returns the pieces of both players in the order SetupGame places them, nil for the empty squares of the setup rows.
+func setupFromState(g *Game, state [][]models.PieceData) ([2][]*engine.Piece, error) {
+	var pieces [2][]*engine.Piece
+	width, height := g.Board.GetWidth(), g.Board.GetHeight()
+	if len(state) != height {
+		return pieces, fmt.Errorf("initial state must have %d rows, got %d", height, len(state))
+	}
+
+	for y, row := range state {
+		if len(row) != width {
+			return pieces, fmt.Errorf("row %d of the initial state must have %d squares, got %d", y, width, len(row))
+		}
+		// Player 1 sets up in the bottom rows, player 2 in the top rows, rows 6-9 and 0-3 in the classic game
+		ownerID := -1
+		if first, _ := SetupRowRange(g.Rules, 0); y >= first {
+			ownerID = 0
+		} else if _, last := SetupRowRange(g.Rules, 1); y <= last {
+			ownerID = 1
+		}
+
+		for x, data := range row {
+			if ownerID >= 0 && data.OwnerID < 0 {
+				pieces[ownerID] = append(pieces[ownerID], nil) // empty square of a smaller army
+				continue
+			}
+			if data.OwnerID != ownerID {
+				return pieces, fmt.Errorf("square (%d,%d) of the initial state has owner %d, expected %d", x, y, data.OwnerID, ownerID)
+			}
+			if ownerID < 0 {
+				continue
+			}
+			var pieceType *models.PieceType
+			if len(data.Rank) == 1 {
+				if id, ok := engine.GetPieceIDFromRank(data.Rank[0]); ok {
+					pieceType = engine.GetPieceTypeFromID(id)
+				}
+			}
+			if pieceType == nil {
+				return pieces, fmt.Errorf("invalid rank %q at (%d,%d) of the initial state", data.Rank, x, y)
+			}
+			pieces[ownerID] = append(pieces[ownerID], engine.NewPiece(*pieceType, g.Players[ownerID]))
+		}
+	}
+
+	if err := SetupGame(g, pieces[0], pieces[1]); err != nil {
+		return pieces, fmt.Errorf("failed to setup game: %v", err)
+	}
+	g.InitialState = g.GetInitialBoardState()
+	return pieces, nil
+}
+
+// replayMoves plays the recorded moves in order.
+func replayMoves(g *Game, moves []models.HistoricalMove) error {
+	for _, recorded := range moves {
+		if err := replayMove(g, recorded); err != nil {
+			return fmt.Errorf("move %d: %w", recorded.MoveIndex, err)
+		}
+	}
+	return nil
+}
+
+// replayMove plays a recorded move and checks that it has the recorded result and rescue.
+func replayMove(g *Game, recorded models.HistoricalMove) error {
+	player := g.Players[0]
+	if recorded.PlayerID == g.Players[1].GetID() {
+		player = g.Players[1]
+	}
+	from := engine.NewPosition(recorded.FromX, recorded.FromY)
+	move := engine.NewMove(from, engine.NewPosition(recorded.ToX, recorded.ToY), player)
+	if err := g.ValidateMove(&move); err != nil {
+		return err
+	}
+
+	g.MakeMove(&move, g.Board.GetPieceAt(from))
+	made := g.HistoricalHistory[len(g.HistoricalHistory)-1]
+	if made.Result != recorded.Result {
+		return fmt.Errorf("expected result %s, got %s", recorded.Result, made.Result)
+	}
+	if (made.Rescue == nil) != (recorded.Rescue == nil) || made.Rescue != nil && *made.Rescue != *recorded.Rescue {
+		return fmt.Errorf("expected rescue %+v, got %+v", recorded.Rescue, made.Rescue)
+	}
+	return nil
+}
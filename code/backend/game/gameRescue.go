@@ -0,0 +1,80 @@
+package game
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+)
+
+// rescue applies the rescue rule after the piece moved to pos: a piece reaching the back row of the opponent
+// brings back the strongest captured movable piece of its owner, once per piece and not with the move that wins the game.
+// The rescued piece is placed on the first empty square of the owner's setup rows, starting at their back row,
+// and revealed, since both players know which pieces were captured.
+// It returns the rescued piece and its square, nil if nothing was rescued.
+func (g *Game) rescue(piece *engine.Piece, pos engine.Position) (*engine.Piece, engine.Position) {
+	owner := piece.GetOwner()
+	seat := 0
+	if owner == g.Players[1] {
+		seat = 1
+	}
+	enemyBackRow := 0
+	if seat == 1 {
+		enemyBackRow = g.Board.GetHeight() - 1
+	}
+	if piece.HasRescued() || owner.HasWon() || pos.Y != enemyBackRow {
+		return nil, pos
+	}
+
+	pieceType := g.strongestCaptured(owner)
+	if pieceType == nil {
+		return nil, pos
+	}
+	square, ok := g.rescueSquare(seat)
+	if !ok {
+		return nil, pos
+	}
+
+	rescued := engine.NewPiece(*pieceType, owner)
+	rescued.Reveal()
+	g.Board.SetPieceAt(square, rescued)
+	owner.RestorePiece(rescued, square, len(owner.GetAlivePieces()))
+	piece.SetRescued(true)
+	return rescued, square
+}
+
+// strongestCaptured returns the movable piece type of the army with the highest strategic value
+// of which the player has lost a piece, nil if none was lost. Captured bombs are not rescued.
+func (g *Game) strongestCaptured(player *engine.Player) *models.PieceType {
+	alive := make(map[byte]int)
+	for _, piece := range player.GetAlivePieces() {
+		alive[piece.GetRank()]++
+	}
+
+	var strongest *models.PieceType
+	for i, pieceType := range models.PieceTypes {
+		if !pieceType.IsMovable() || alive[pieceType.GetRank()] >= g.Rules.Count(pieceType) {
+			continue
+		}
+		if strongest == nil || pieceType.GetStrategicValue() > strongest.GetStrategicValue() {
+			strongest = &models.PieceTypes[i]
+		}
+	}
+	return strongest
+}
+
+// rescueSquare returns the first empty square of the setup rows of the seat, starting at its back row.
+func (g *Game) rescueSquare(seat int) (engine.Position, bool) {
+	first, last := SetupRowRange(g.Rules, seat)
+	for i := range last - first + 1 {
+		y := first + i
+		if seat == 0 {
+			y = last - i
+		}
+		for x := range g.Board.GetWidth() {
+			pos := engine.NewPosition(x, y)
+			if g.Board.GetPieceAt(pos) == nil && !g.Board.IsLake(pos) {
+				return pos, true
+			}
+		}
+	}
+	return engine.Position{}, false
+}
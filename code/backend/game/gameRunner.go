@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -13,10 +15,11 @@ type GameRunner struct {
 	game                 *Game
 	turnDelay            time.Duration // Optional delay between AI turns for visualization, can be 0 to remove the delay
 	maxTurns             int
-	waitingForHumanInput bool
-	paused               bool // flag to indicate if game is paused
+	waitingForHumanInput atomic.Bool
+	paused               atomic.Bool // flag to indicate if game is paused
 	onMoveExecuted       func()
 	stopChan             chan bool
+	locker               sync.Locker // held while the runner changes the game, see SetLocker
 }
 
 func NewGameRunner(game *Game, turnDelay time.Duration, maxTurns int) *GameRunner {
@@ -27,7 +30,6 @@ func NewGameRunner(game *Game, turnDelay time.Duration, maxTurns int) *GameRunne
 		game:      game,
 		turnDelay: turnDelay,
 		maxTurns:  maxTurns,
-		paused:    false,
 	}
 }
 
@@ -36,6 +38,21 @@ func (gr *GameRunner) SetMoveCallback(callback func()) {
 	gr.onMoveExecuted = callback
 }
 
+// SetLocker sets the lock the runner holds while it changes the game,
+// so others can read the game under the same lock while it runs
+func (gr *GameRunner) SetLocker(locker sync.Locker) {
+	gr.locker = locker
+}
+
+// update changes the game under the lock of the runner, if it has one
+func (gr *GameRunner) update(change func()) {
+	if gr.locker != nil {
+		gr.locker.Lock()
+		defer gr.locker.Unlock()
+	}
+	change()
+}
+
 // RunToCompletion runs the game until it's over (for AI vs AI)
 // Winner can be nil when max turns are reached and both AIs have a similar piece count
 func (gr *GameRunner) RunToCompletion(logging bool) *engine.Player {
@@ -84,9 +101,11 @@ func (gr *GameRunner) RunToCompletion(logging bool) *engine.Player {
 
 	if turnCount >= gr.maxTurns {
 		if logging {
-			fmt.Println("Game ended: Maximum turns reached")
+			log.Printf("Game ended: Maximum turns reached")
 		}
-		return gr.calculateWinnerOnMaxTurnsExceeded()
+		var winner *engine.Player
+		gr.update(func() { winner = gr.calculateWinnerOnMaxTurnsExceeded() })
+		return winner
 	}
 
 	return gr.game.GetWinner()
@@ -132,11 +151,8 @@ func (gr *GameRunner) executeTurn(logging bool, ignorePause bool) bool {
 	if controller.GetControllerType() == engine.HumanController {
 		humanController, ok := controller.(*engine.HumanPlayerController)
 		if !ok || !humanController.HasPendingMove() {
-			if !gr.waitingForHumanInput {
-				if logging {
-					log.Printf("GameRunner.ExecuteTurn: Waiting for human input")
-				}
-				gr.waitingForHumanInput = true
+			if !gr.waitingForHumanInput.Swap(true) && logging {
+				log.Printf("GameRunner.ExecuteTurn: Waiting for human input")
 			}
 			return false // Wait for human input
 		}
@@ -146,14 +162,17 @@ func (gr *GameRunner) executeTurn(logging bool, ignorePause bool) bool {
 			return false
 		}
 
-		piece := gr.game.Board.GetPieceAt(move.GetFrom())
-		if piece == nil {
-			fmt.Println("Invalid move: no piece at from position")
+		if err := gr.game.ValidateMove(move); err != nil {
+			if logging {
+				log.Printf("GameRunner.ExecuteTurn: Rejected human move %v: %v", move, err)
+			}
 			return false
 		}
 
-		gr.game.MakeMove(move, piece)
-		gr.waitingForHumanInput = false
+		gr.update(func() {
+			gr.game.MakeMove(move, gr.game.Board.GetPieceAt(move.GetFrom()))
+			gr.waitingForHumanInput.Store(false)
+		})
 
 		if gr.onMoveExecuted != nil {
 			gr.onMoveExecuted()
@@ -161,10 +180,10 @@ func (gr *GameRunner) executeTurn(logging bool, ignorePause bool) bool {
 		return true
 	}
 
-	// AI controller - make move
+	// AI controller - make move from its own view of the board
 	// Calculate AI move first so we can subtract its thinking time from the pacing delay
 	start := time.Now()
-	move := controller.MakeMove(gr.game.Board)
+	move := controller.MakeMove(gr.game.ViewFor(gr.game.CurrentPlayer))
 	elapsed := time.Since(start)
 
 	// Add delay for pacing if requested, compensating for AI thinking time
@@ -194,24 +213,24 @@ func (gr *GameRunner) executeTurn(logging bool, ignorePause bool) bool {
 				gr.game.CurrentPlayer.GetName(), move.GetFrom())
 		}
 		opponent := gr.getOpponent(gr.game.CurrentPlayer)
-		gr.game.SetWinner(opponent, WinCauseNoMovablePieces)
+		gr.update(func() { gr.game.SetWinner(opponent, WinCauseNoMovablePieces) })
 		if logging {
-			fmt.Printf("%s has no valid moves remaining - %s wins!\n",
+			log.Printf("%s has no valid moves remaining - %s wins!",
 				gr.game.CurrentPlayer.GetName(), opponent.GetName())
 		}
 		return false
 	}
 
-	if !gr.game.Board.IsValidMove(&move) {
+	if err := gr.game.ValidateMove(&move); err != nil {
 		if logging {
-			log.Printf("AI %s provided invalid move: %v", gr.game.CurrentPlayer.GetName(), move)
+			log.Printf("AI %s provided invalid move %v: %v", gr.game.CurrentPlayer.GetName(), move, err)
 		}
 		opponent := gr.getOpponent(gr.game.CurrentPlayer)
-		gr.game.SetWinner(opponent, WinCauseNoMovablePieces)
+		gr.update(func() { gr.game.SetWinner(opponent, WinCauseNoMovablePieces) })
 		return false
 	}
 
-	gr.game.MakeMove(&move, piece)
+	gr.update(func() { gr.game.MakeMove(&move, piece) })
 
 	if gr.onMoveExecuted != nil {
 		gr.onMoveExecuted()
@@ -229,13 +248,13 @@ func (gr *GameRunner) getOpponent(player *engine.Player) *engine.Player {
 
 // IsWaitingForInput returns true if the game is waiting for human input
 func (gr *GameRunner) IsWaitingForInput() bool {
-	return gr.waitingForHumanInput
+	return gr.waitingForHumanInput.Load()
 }
 
 // DebugSetWaitingForInput sets the waiting for human input flag to the given value.
 // This is for debugging (& testing) purposes only and should not be used in production code.
 func (gr *GameRunner) DebugSetWaitingForInput(value bool) {
-	gr.waitingForHumanInput = value
+	gr.waitingForHumanInput.Store(value)
 }
 
 // GetGame returns the underlying game
@@ -245,7 +264,7 @@ func (gr *GameRunner) GetGame() *Game {
 
 // SubmitHumanMove allows external code to submit a human player's move
 func (gr *GameRunner) SubmitHumanMove(move engine.Move) error {
-	if !gr.waitingForHumanInput {
+	if !gr.waitingForHumanInput.Load() {
 		return fmt.Errorf("not waiting for input")
 	}
 
@@ -259,13 +278,8 @@ func (gr *GameRunner) SubmitHumanMove(move engine.Move) error {
 		return fmt.Errorf("invalid controller type")
 	}
 
-	// Check that the move's player matches the current player
-	if move.GetPlayer() != gr.game.CurrentPlayer {
-		return fmt.Errorf("move player does not match current player")
-	}
-
-	if !gr.game.Board.IsValidMove(&move) {
-		return fmt.Errorf("invalid move")
+	if err := gr.game.ValidateMove(&move); err != nil {
+		return err
 	}
 
 	humanController.SetPendingMove(move)
@@ -277,12 +291,12 @@ func (gr *GameRunner) SubmitHumanMove(move engine.Move) error {
 
 // Pause pauses the game runner
 func (gr *GameRunner) Pause() {
-	gr.paused = true
+	gr.paused.Store(true)
 }
 
 // Unpause unpauses the game runner
 func (gr *GameRunner) Unpause() {
-	gr.paused = false
+	gr.paused.Store(false)
 }
 
 // SetTurnDelay sets the delay between AI turns
@@ -297,5 +311,5 @@ func (gr *GameRunner) Step(logging bool) bool {
 
 // IsPaused returns whether the game runner is paused
 func (gr *GameRunner) IsPaused() bool {
-	return gr.paused
+	return gr.paused.Load()
 }
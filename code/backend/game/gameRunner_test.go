@@ -5,6 +5,8 @@ import (
 	"digital-innovation/stratego/engine"
 	"digital-innovation/stratego/game"
 	"digital-innovation/stratego/models"
+	"errors"
+	"math/rand/v2"
 	"testing"
 	"time"
 )
@@ -110,7 +112,7 @@ func TestSubmitHumanMove(t *testing.T) {
 	}
 
 	// Valid move
-	move = engine.NewMove(engine.NewPosition(0, 6), engine.NewPosition(0, 5), &player1)
+	move = frontRowMove(t, gameObj, &player1)
 	err = runner.SubmitHumanMove(move)
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
@@ -126,6 +128,44 @@ func TestSubmitHumanMove(t *testing.T) {
 	}
 }
 
+// frontRowMove returns a valid move one square forward for a movable piece in the front row (row 6)
+func frontRowMove(t *testing.T, g *game.Game, player *engine.Player) engine.Move {
+	t.Helper()
+	for x := range 10 {
+		from := engine.NewPosition(x, 6)
+		move := engine.NewMove(from, engine.NewPosition(x, 5), player)
+		if engine.ValidateMove(g.Board, &move) == nil {
+			return move
+		}
+	}
+	t.Fatal("Expected at least one movable piece in the front row")
+	return engine.Move{}
+}
+
+func TestSubmitHumanMoveUnreachable(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Human", "red")
+	player2 := engine.NewPlayer(1, "AI", "blue")
+
+	controller1 := engine.NewHumanPlayerController(&player1)
+	controller2 := AIhandler.CreateAI(models.Fafo, &player2)
+
+	g := game.NewGame(controller1, controller2)
+	g.Board.SetPieceAt(engine.NewPosition(0, 6), engine.NewPiece(models.Major, &player1))
+	runner := game.NewGameRunner(g, 0, 1000)
+	runner.DebugSetWaitingForInput(true)
+
+	// A major cannot jump several squares at once
+	move := engine.NewMove(engine.NewPosition(0, 6), engine.NewPosition(0, 3), &player1)
+	err := runner.SubmitHumanMove(move)
+	if !errors.Is(err, engine.ErrTooFar) {
+		t.Errorf("Expected ErrTooFar, got: %v", err)
+	}
+
+	if len(g.MoveHistory) != 0 {
+		t.Errorf("Expected rejected move not to be played")
+	}
+}
+
 func TestGameRunnerIsWaitingForInput(t *testing.T) {
 	player1 := engine.NewPlayer(0, "Human", "red")
 	player2 := engine.NewPlayer(1, "AI", "blue")
@@ -209,3 +249,29 @@ func TestGameRunnerWithDelay(t *testing.T) {
 		t.Errorf("Expected game to take at least 10ms with delays, took: %v", elapsed)
 	}
 }
+
+func BenchmarkRunToCompletion(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		player1 := engine.NewPlayer(0, "player 1", "red")
+		player2 := engine.NewPlayer(1, "player 2", "blue")
+		g := game.QuickStart(AIhandler.CreateAI(models.Fafo, &player1), AIhandler.CreateAI(models.Fafo, &player2))
+		game.NewGameRunner(g, 0, 1000).RunToCompletion(false)
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+}
+
+func BenchmarkCompactRunToCompletion(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	moves := make([]engine.CompactMove, 0, 256)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		player1 := engine.NewPlayer(0, "player 1", "red")
+		player2 := engine.NewPlayer(1, "player 2", "blue")
+		g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+		compact := engine.NewCompactBoard(g.Board, &player2, nil)
+		compact.RandomPlayout(rng, 0, 2000, moves)
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "games/s")
+}
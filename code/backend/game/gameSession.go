@@ -18,6 +18,8 @@ type GameSession struct {
 	runner                *GameRunner
 	mutex                 sync.RWMutex
 	running               bool
+	stopped               bool // Stop was called, the session will not be (re)started
+	ended                 bool // the game loop has returned
 	isSetupPhase          bool
 	headless              bool
 	player1Pieces         []*engine.Piece
@@ -35,11 +37,16 @@ type GameSession struct {
 }
 
 func NewGameSession(id string, controller1, controller2 engine.PlayerController) *GameSession {
-	g := NewGame(controller1, controller2)
+	return NewGameSessionWithRules(id, controller1, controller2, models.ClassicRules())
+}
+
+// NewGameSessionWithRules creates a session of a game played with the rule set, in the setup phase
+func NewGameSessionWithRules(id string, controller1, controller2 engine.PlayerController, rules models.RuleSet) *GameSession {
+	g := NewGameWithRules(controller1, controller2, rules)
 
 	// Generate initial piece setups for both players
-	player1Pieces := RandomSetup(g.Players[0])
-	player2Pieces := RandomSetup(g.Players[1])
+	player1Pieces := randomSetup(g.Players[0], rules)
+	player2Pieces := randomSetup(g.Players[1], rules)
 
 	session := &GameSession{
 		ID:                    id,
@@ -57,6 +64,7 @@ func NewGameSession(id string, controller1, controller2 engine.PlayerController)
 	}
 
 	session.runner.stopChan = session.stopChan
+	session.runner.SetLocker(&session.mutex)
 
 	session.runner.SetMoveCallback(func() {
 		session.NotifyMoveExecuted()
@@ -74,6 +82,10 @@ func (gs *GameSession) Start() error {
 		gs.mutex.Unlock()
 		return errors.New("game already running")
 	}
+	if gs.stopped {
+		gs.mutex.Unlock()
+		return errors.New("game session stopped")
+	}
 	gs.running = true
 	gs.mutex.Unlock()
 
@@ -85,6 +97,7 @@ func (gs *GameSession) Start() error {
 		gs.doneChan <- winner
 		gs.mutex.Lock()
 		gs.running = false
+		gs.ended = true
 		gs.mutex.Unlock()
 	}()
 
@@ -92,8 +105,10 @@ func (gs *GameSession) Start() error {
 }
 
 // Stop forcefully stops the game session
+// A session stopped during setup can no longer be started
 func (gs *GameSession) Stop() {
 	gs.mutex.Lock()
+	gs.stopped = true
 	if !gs.running {
 		gs.mutex.Unlock()
 		return
@@ -165,6 +180,10 @@ func (gs *GameSession) SubmitMove(playerID int, move engine.Move) error {
 		return errors.New("failed to cast to human controller")
 	}
 
+	if err := gs.game.ValidateMove(&move); err != nil {
+		return err
+	}
+
 	humanController.SetPendingMove(move)
 	return nil
 }
@@ -189,6 +208,7 @@ func (gs *GameSession) GetGameState() models.GameState {
 		Player2AlivePieces: len(gs.game.Players[1].GetAlivePieces()),
 		IsSetupPhase:       gs.isSetupPhase,
 		Headless:           gs.headless,
+		Rules:              gs.game.Rules,
 	}
 }
 
@@ -200,6 +220,7 @@ func (gs *GameSession) GetBoard() *engine.Board {
 }
 
 // GetAvailableMoves returns valid moves for a piece at the given position
+// Moves that would break the two-square or more-squares rule are left out
 // It returns an error if the piece does not belong to the requesting player
 func (gs *GameSession) GetAvailableMoves(playerID int, pos engine.Position) ([]engine.Move, error) {
 	gs.mutex.RLock()
@@ -214,7 +235,7 @@ func (gs *GameSession) GetAvailableMoves(playerID int, pos engine.Position) ([]e
 		return nil, errors.New("you can only request moves for your own pieces")
 	}
 
-	return gs.game.Board.ListMoves(pos)
+	return gs.game.ListLegalMoves(pos)
 }
 
 // WaitForCompletion blocks until the game is complete and returns the winner
@@ -369,6 +390,20 @@ func getPlayerIDOrNil(player *engine.Player) *int {
 	return &id
 }
 
+// IsStopped returns whether Stop was called
+func (gs *GameSession) IsStopped() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.stopped
+}
+
+// HasEnded returns whether the game loop has returned, because the game is over or the session was stopped
+func (gs *GameSession) HasEnded() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.ended
+}
+
 // IsSetupPhase returns whether the game is in setup phase
 func (gs *GameSession) IsSetupPhase() bool {
 	gs.mutex.RLock()
@@ -390,6 +425,13 @@ func (gs *GameSession) IsHeadless() bool {
 	return gs.headless
 }
 
+// GetRules returns the rule set of the game
+func (gs *GameSession) GetRules() models.RuleSet {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.game.Rules
+}
+
 // GetSetupPieces returns the setup pieces for a player
 func (gs *GameSession) GetSetupPieces(playerID int) []*engine.Piece {
 	gs.mutex.RLock()
@@ -420,7 +462,7 @@ func (gs *GameSession) SwapSetupPieces(playerID int, pos1, pos2 engine.Position)
 		return errors.New("invalid player ID")
 	}
 
-	// Calculate indices from positions (setup area is 4x10 = 40 pieces)
+	// Calculate indices from positions (setup area is the setup rows of the rule set, 4x10 in the classic game)
 	idx1 := gs.positionToIndex(pos1, playerID)
 	idx2 := gs.positionToIndex(pos2, playerID)
 
@@ -437,26 +479,20 @@ func (gs *GameSession) SwapSetupPieces(playerID int, pos1, pos2 engine.Position)
 
 // positionToIndex converts a board position to piece array index
 func (gs *GameSession) positionToIndex(pos engine.Position, playerID int) int {
-	var startRow, endRow int
-	if playerID == 0 {
-		startRow = 6
-		endRow = 9
-	} else {
-		startRow = 0
-		endRow = 3
-	}
+	startRow, endRow := SetupRowRange(gs.game.Rules, playerID)
+	width := gs.game.Board.GetWidth()
 
 	// Check if position is in valid range
-	if pos.Y < startRow || pos.Y > endRow || pos.X < 0 || pos.X >= 10 {
+	if pos.Y < startRow || pos.Y > endRow || pos.X < 0 || pos.X >= width {
 		return -1
 	}
 
 	// Calculate index
 	rowOffset := pos.Y - startRow
-	return rowOffset*10 + pos.X
+	return rowOffset*width + pos.X
 }
 
-// LoadSetup loads a predefined setup from binary data (40 bytes)
+// LoadSetup loads a predefined setup from binary data, one byte per setup square (40 bytes in the classic game)
 func (gs *GameSession) LoadSetup(playerID int, data []byte) error {
 	gs.mutex.Lock()
 	defer gs.mutex.Unlock()
@@ -475,7 +511,7 @@ func (gs *GameSession) LoadSetup(playerID int, data []byte) error {
 		return errors.New("invalid player ID")
 	}
 
-	pieces, err := ParseSetup(player, data)
+	pieces, err := ParseSetupWithRules(player, gs.game.Rules, data)
 	if err != nil {
 		return fmt.Errorf("invalid setup data: %v", err)
 	}
@@ -504,10 +540,10 @@ func (gs *GameSession) RandomizeSetup(playerID int) error {
 	switch playerID {
 	case 0:
 		player = gs.game.Players[0]
-		gs.player1Pieces = RandomSetup(player)
+		gs.player1Pieces = randomSetup(player, gs.game.Rules)
 	case 1:
 		player = gs.game.Players[1]
-		gs.player2Pieces = RandomSetup(player)
+		gs.player2Pieces = randomSetup(player, gs.game.Rules)
 	default:
 		return errors.New("invalid player ID")
 	}
@@ -524,6 +560,10 @@ func (gs *GameSession) StartGameFromSetup(headless bool) error {
 		gs.mutex.Unlock()
 		return errors.New("not in setup phase")
 	}
+	if gs.stopped {
+		gs.mutex.Unlock()
+		return errors.New("game session stopped")
+	}
 
 	log.Printf("Game %s: Starting game from setup - placing pieces on board (headless=%v)", gs.ID, headless)
 
@@ -197,7 +197,7 @@ func TestGameSessionSubmitMove(t *testing.T) {
 	time.Sleep(100 * time.Millisecond) // Wait for game to be ready
 
 	// Submit a valid move
-	move := engine.NewMove(engine.NewPosition(0, 6), engine.NewPosition(0, 5), &player1)
+	move := frontRowMove(t, session.GetGame(), &player1)
 	err = session.SubmitMove(0, move)
 	if err != nil {
 		t.Errorf("Expected no error submitting move, got: %v", err)
@@ -327,3 +327,50 @@ func TestGameSessionStop(t *testing.T) {
 		t.Error("Expected session to stop running after Stop()")
 	}
 }
+
+func TestGameSessionStopDuringSetup(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Player1", "red")
+	player2 := engine.NewPlayer(1, "Player2", "blue")
+
+	controller1 := engine.NewHumanPlayerController(&player1)
+	controller2 := engine.NewHumanPlayerController(&player2)
+
+	session := game.NewGameSession("stop-setup-test", controller1, controller2)
+	session.Stop()
+
+	if !session.IsStopped() {
+		t.Error("Expected session to be stopped after Stop() in setup phase")
+	}
+	if err := session.StartGameFromSetup(false); err == nil {
+		t.Error("Expected starting a stopped session to fail")
+	}
+	if session.IsRunning() || session.HasEnded() {
+		t.Error("Expected a session stopped in setup to never run")
+	}
+}
+
+func TestGameSessionHasEndedAfterStop(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Player1", "red")
+	player2 := engine.NewPlayer(1, "Player2", "blue")
+
+	controller1 := engine.NewHumanPlayerController(&player1)
+	controller2 := engine.NewHumanPlayerController(&player2)
+
+	session := game.NewGameSession("ended-test", controller1, controller2)
+	if err := session.StartGameFromSetup(false); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if session.HasEnded() {
+		t.Error("Expected a running session not to have ended")
+	}
+
+	session.Stop()
+	deadline := time.Now().Add(2 * time.Second)
+	for !session.HasEnded() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !session.HasEnded() {
+		t.Error("Expected the game loop to end after Stop()")
+	}
+}
@@ -9,19 +9,34 @@ import (
 )
 
 // SetupGame initializes the board with pieces for both players and prepares the game
+// A setup has one entry per square of the setup rows of the game's rule set, nil for an empty square,
+// and must hold exactly the army of the rule set. Player 1 sets up at the bottom, player 2 at the top.
 func SetupGame(game *Game, player1Pieces, player2Pieces []*engine.Piece) error {
+	rules := game.Rules
+	cells := rules.SetupCells()
+
 	// Validate piece counts
-	if len(player1Pieces) != 40 || len(player2Pieces) != 40 {
-		return errors.New("each player must have exactly 40 pieces")
+	if len(player1Pieces) != cells || len(player2Pieces) != cells {
+		if rules.ArmySize() == cells {
+			return fmt.Errorf("each player must have exactly %d pieces", cells)
+		}
+		return fmt.Errorf("each player must set up exactly %d squares", cells)
+	}
+	for i, pieces := range [][]*engine.Piece{player1Pieces, player2Pieces} {
+		if err := rules.ValidateArmy(countRanks(pieces)); err != nil {
+			return fmt.Errorf("invalid army of player %d: %w", i+1, err)
+		}
 	}
 
-	// Place player 1 pieces (bottom 4 rows: 6-9)
-	if err := placePiecesInRows(game.Board, player1Pieces, 6, 9); err != nil {
+	// Place player 1 pieces (bottom rows, 6-9 in the classic game)
+	startRow, endRow := SetupRowRange(rules, 0)
+	if err := placePiecesInRows(game.Board, player1Pieces, startRow, endRow); err != nil {
 		return err
 	}
 
-	// Place player 2 pieces (top 4 rows: 0-3)
-	if err := placePiecesInRows(game.Board, player2Pieces, 0, 3); err != nil {
+	// Place player 2 pieces (top rows, 0-3 in the classic game)
+	startRow, endRow = SetupRowRange(rules, 1)
+	if err := placePiecesInRows(game.Board, player2Pieces, startRow, endRow); err != nil {
 		return err
 	}
 
@@ -33,16 +48,37 @@ func SetupGame(game *Game, player1Pieces, player2Pieces []*engine.Piece) error {
 	return nil
 }
 
-// placePiecesInRows places pieces sequentially in the specified row range
+// countRanks counts the pieces of a setup by rank
+func countRanks(pieces []*engine.Piece) map[byte]int {
+	counts := make(map[byte]int)
+	for _, piece := range pieces {
+		if piece != nil {
+			counts[piece.GetRank()]++
+		}
+	}
+	return counts
+}
+
+// SetupRowRange returns the first and last row a player sets up in under the rule set
+func SetupRowRange(rules models.RuleSet, playerID int) (int, int) {
+	if playerID == 0 {
+		height := rules.Layout().Height
+		return height - rules.SetupRows, height - 1
+	}
+	return 0, rules.SetupRows - 1
+}
+
+// placePiecesInRows places pieces sequentially in the specified row range, leaving the squares of nil entries empty
 func placePiecesInRows(board *engine.Board, pieces []*engine.Piece, startRow, endRow int) error {
 	pieceIndex := 0
 	for y := startRow; y <= endRow; y++ {
-		for x := 0; x < 10; x++ {
+		for x := range board.GetWidth() {
 			if pieceIndex >= len(pieces) {
 				return nil
 			}
-			pos := engine.NewPosition(x, y)
-			board.SetPieceAt(pos, pieces[pieceIndex])
+			if piece := pieces[pieceIndex]; piece != nil {
+				board.SetPieceAt(engine.NewPosition(x, y), piece)
+			}
 			pieceIndex++
 		}
 	}
@@ -51,9 +87,29 @@ func placePiecesInRows(board *engine.Board, pieces []*engine.Piece, startRow, en
 
 // RandomSetup creates a random valid piece placement for a player
 func RandomSetup(player *engine.Player) []*engine.Piece {
-	pieces := GetPieceList(player)
+	return RandomSetupWithRand(player, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
+
+// randomSetup creates a random placement of the army of a rule set from a new random source
+func randomSetup(player *engine.Player, rules models.RuleSet) []*engine.Piece {
+	return RandomSetupWithRules(player, rules, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
+
+// RandomSetupWithRand creates a random valid piece placement for a player from the given random source,
+// so the same seed always gives the same setup
+func RandomSetupWithRand(player *engine.Player, rng *rand.Rand) []*engine.Piece {
+	return RandomSetupWithRules(player, models.ClassicRules(), rng)
+}
+
+// RandomSetupWithRules creates a random placement of the army of a rule set in its setup rows,
+// with nil entries for the squares left empty
+func RandomSetupWithRules(player *engine.Player, rules models.RuleSet, rng *rand.Rand) []*engine.Piece {
+	pieces := GetArmy(player, rules)
+	for len(pieces) < rules.SetupCells() {
+		pieces = append(pieces, nil)
+	}
 	// Shuffle pieces for random placement
-	rand.Shuffle(len(pieces), func(i, j int) {
+	rng.Shuffle(len(pieces), func(i, j int) {
 		pieces[i], pieces[j] = pieces[j], pieces[i]
 	})
 	return pieces
@@ -76,9 +132,18 @@ func CustomSetup(player *engine.Player, piecePositions map[engine.Position]*engi
 // ParseSetup converts 40-byte binary setup data (bitpacked) or 40-character rank strings
 // into an ordered piece list for the player.
 func ParseSetup(player *engine.Player, data []byte) ([]*engine.Piece, error) {
-	if len(data) != 40 {
-		return nil, fmt.Errorf("setup data must be 40 bytes, got %d", len(data))
+	return ParseSetupWithRules(player, models.ClassicRules(), data)
+}
+
+// ParseSetupWithRules converts setup data with one byte per square of the setup rows of the rule set,
+// bitpacked or rank characters, into a setup for the player. Empty squares become nil entries.
+// The setup must hold exactly the army of the rule set.
+func ParseSetupWithRules(player *engine.Player, rules models.RuleSet, data []byte) ([]*engine.Piece, error) {
+	cells := rules.SetupCells()
+	if len(data) != cells {
+		return nil, fmt.Errorf("setup data must be %d bytes, got %d", cells, len(data))
 	}
+	full := rules.ArmySize() == cells
 
 	// Detect format: bitpacked vs rank characters
 	isBitpacked := false
@@ -89,20 +154,28 @@ func ParseSetup(player *engine.Player, data []byte) ([]*engine.Piece, error) {
 		}
 	}
 
-	pieces := make([]*engine.Piece, 0, 40)
-	for i := 0; i < 40; i++ {
+	pieces := make([]*engine.Piece, 0, cells)
+	for i := 0; i < cells; i++ {
 		cell := data[i]
 		var pieceType *models.PieceType
 
 		if isBitpacked {
 			if cell&engine.BitOccupied == 0 {
-				return nil, fmt.Errorf("cell %d is empty, all 40 cells must be occupied", i)
+				if full {
+					return nil, fmt.Errorf("cell %d is empty, all %d cells must be occupied", i, cells)
+				}
+				pieces = append(pieces, nil)
+				continue
 			}
 			pieceType = engine.GetPieceTypeFromCell(cell)
 		} else {
 			// Rank character format
 			if cell == '.' || cell == ' ' {
-				return nil, fmt.Errorf("cell %d is empty in rank string, all 40 cells must be occupied", i)
+				if full {
+					return nil, fmt.Errorf("cell %d is empty in rank string, all %d cells must be occupied", i, cells)
+				}
+				pieces = append(pieces, nil)
+				continue
 			}
 			pieceID, ok := engine.GetPieceIDFromRank(cell)
 			if !ok {
@@ -118,8 +191,8 @@ func ParseSetup(player *engine.Player, data []byte) ([]*engine.Piece, error) {
 		pieces = append(pieces, engine.NewPiece(*pieceType, player))
 	}
 
-	if len(pieces) != 40 {
-		return nil, fmt.Errorf("expected 40 pieces, got %d", len(pieces))
+	if err := rules.ValidateArmy(countRanks(pieces)); err != nil {
+		return nil, err
 	}
 
 	return pieces, nil
@@ -127,13 +200,24 @@ func ParseSetup(player *engine.Player, data []byte) ([]*engine.Piece, error) {
 
 // QuickStart creates a game with random setups for both players
 func QuickStart(controller1, controller2 engine.PlayerController) *Game {
-	game := NewGame(controller1, controller2)
+	return QuickStartWithRand(controller1, controller2, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
+
+// QuickStartWithRand creates a game with random setups for both players drawn from the given random source
+func QuickStartWithRand(controller1, controller2 engine.PlayerController, rng *rand.Rand) *Game {
+	return QuickStartWithRules(controller1, controller2, models.ClassicRules(), rng)
+}
+
+// QuickStartWithRules creates a game played with the rule set, with random setups for both players drawn from
+// the given random source
+func QuickStartWithRules(controller1, controller2 engine.PlayerController, rules models.RuleSet, rng *rand.Rand) *Game {
+	game := NewGameWithRules(controller1, controller2, rules)
 
 	player1 := controller1.GetPlayer()
 	player2 := controller2.GetPlayer()
 
-	player1Pieces := RandomSetup(player1)
-	player2Pieces := RandomSetup(player2)
+	player1Pieces := RandomSetupWithRules(player1, rules, rng)
+	player2Pieces := RandomSetupWithRules(player2, rules, rng)
 
 	if err := SetupGame(game, player1Pieces, player2Pieces); err != nil {
 		panic("Failed to setup game: " + err.Error())
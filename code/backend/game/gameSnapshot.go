@@ -0,0 +1,121 @@
+package game
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/models"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Snapshot returns the state needed to resume the game after a restart, see RestoreGameSession.
+// Take it while the runner waits, e.g. before acknowledging a move, so it does not see a move halfway.
+func (gs *GameSession) Snapshot() models.GameSnapshot {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+
+	g := gs.game
+	return models.GameSnapshot{
+		GameID:          gs.ID,
+		Rules:           g.Rules,
+		Player1UserID:   gs.Player1UserID,
+		Player2UserID:   gs.Player2UserID,
+		InitialState:    g.GetInitialBoardState(),
+		Moves:           slices.Clone(g.HistoricalHistory),
+		Board:           encodeBoard(g),
+		CurrentPlayerID: g.CurrentPlayer.GetID(),
+		Round:           g.GetRound(),
+		Revealed:        revealedSquares(g.Board),
+		StartTime:       gs.StartTime,
+	}
+}
+
+// RestoreGameSession recreates a session from a snapshot with new controllers for its players.
+// The moves are replayed from the initial state, which also rebuilds the move history for the repetition rules
+// and the memory of AI players, and the result is checked against the stored board.
+// The session is out of the setup phase but not running, call Start to continue the game.
+func RestoreGameSession(snapshot models.GameSnapshot, controller1, controller2 engine.PlayerController) (*GameSession, error) {
+	session := NewGameSessionWithRules(snapshot.GameID, controller1, controller2, snapshot.Rules)
+	g := session.game
+
+	pieces, err := setupFromState(g, snapshot.InitialState)
+	if err != nil {
+		return nil, err
+	}
+	if err := replayMoves(g, snapshot.Moves); err != nil {
+		return nil, err
+	}
+	if g.IsGameOver() {
+		return nil, errors.New("game is already over")
+	}
+	if encodeBoard(g) != snapshot.Board {
+		return nil, errors.New("replayed board does not match the snapshot")
+	}
+	if g.CurrentPlayer.GetID() != snapshot.CurrentPlayerID || g.GetRound() != snapshot.Round {
+		return nil, fmt.Errorf("replayed game is at round %d with player %d, the snapshot at round %d with player %d",
+			g.GetRound(), g.CurrentPlayer.GetID(), snapshot.Round, snapshot.CurrentPlayerID)
+	}
+
+	revealed := make(map[int]bool, len(snapshot.Revealed))
+	for _, square := range snapshot.Revealed {
+		revealed[square] = true
+	}
+	for y, row := range g.Board.GetField() {
+		for x, piece := range row {
+			if piece != nil {
+				if revealed[int(engine.SquareIndex(engine.NewPosition(x, y)))] {
+					piece.Reveal()
+				} else {
+					piece.Hide()
+				}
+			}
+		}
+	}
+	g.LastCombat = nil // already shown before the restart
+
+	session.player1Pieces = pieces[0]
+	session.player2Pieces = pieces[1]
+	session.isSetupPhase = false
+	session.runner.SetTurnDelay(1 * time.Second) // pacing of visible games, see StartGameFromSetup
+	session.Player1UserID = snapshot.Player1UserID
+	session.Player2UserID = snapshot.Player2UserID
+	session.StartTime = snapshot.StartTime
+
+	return session, nil
+}
+
+// encodeBoard returns the board in the cell layout of engine.EncodeBoardToBase64, 10 squares per row.
+// EncodeBoard only sets the color bit for a player with ID 2, while sessions use the IDs 0 and 1.
+// Blocked squares of the compact board are left empty, so the encoding only depends on the pieces.
+func encodeBoard(g *Game) string {
+	moved := make(map[engine.Position]bool)
+	for y, row := range g.Board.GetField() {
+		for x, piece := range row {
+			if piece != nil && piece.HasMoved() {
+				moved[engine.NewPosition(x, y)] = true
+			}
+		}
+	}
+	compact := engine.NewCompactBoard(g.Board, g.Players[1], moved)
+	for square, cell := range compact {
+		if cell == engine.CellBlocked {
+			compact[square] = 0
+		}
+	}
+	return base64.StdEncoding.EncodeToString(compact[:])
+}
+
+// revealedSquares returns the squares (y*10+x) of the revealed pieces.
+func revealedSquares(board *engine.Board) []int {
+	squares := []int{}
+	for y, row := range board.GetField() {
+		for x, piece := range row {
+			if piece != nil && piece.IsRevealed() {
+				squares = append(squares, int(engine.SquareIndex(engine.NewPosition(x, y))))
+			}
+		}
+	}
+	return squares
+}
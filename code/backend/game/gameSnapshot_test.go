@@ -0,0 +1,157 @@
+package game_test
+
+import (
+	AIhandler "digital-innovation/stratego/ai/handler"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newAIControllers(ai string) (engine.PlayerController, engine.PlayerController) {
+	player1 := engine.NewPlayer(0, "AI Red", "red")
+	player2 := engine.NewPlayer(1, "AI Blue", "blue")
+	return AIhandler.CreateAI(ai, &player1), AIhandler.CreateAI(ai, &player2)
+}
+
+// snapshotAfter plays headless AI vs AI games and takes a snapshot after the given number of moves,
+// starting over if a game ends sooner.
+func snapshotAfter(t *testing.T, moves int) models.GameSnapshot {
+	t.Helper()
+	for range 20 {
+		if snapshot, ok := playUntil(t, moves); ok {
+			return snapshot
+		}
+	}
+	t.Fatalf("Expected a game to last %d moves", moves)
+	return models.GameSnapshot{}
+}
+
+func playUntil(t *testing.T, moves int) (models.GameSnapshot, bool) {
+	t.Helper()
+	controller1, controller2 := newAIControllers(models.Fato)
+	session := game.NewGameSession("snapshot-test", controller1, controller2)
+	defer session.Stop()
+
+	if err := session.StartGameFromSetup(true); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	for i := 1; ; i++ {
+		played := session.WaitForMoveNotification(5 * time.Second)
+		if session.GetGameState().IsGameOver {
+			session.AckMoveProcessed()
+			return models.GameSnapshot{}, false
+		}
+		if !played {
+			t.Fatalf("Expected move %d to be played", i)
+		}
+		if i == moves {
+			// The runner waits for the acknowledgement, so the snapshot sees a finished move
+			snapshot := session.Snapshot()
+			session.AckMoveProcessed()
+			return snapshot, true
+		}
+		session.AckMoveProcessed()
+	}
+}
+
+func TestGameSessionSnapshotRoundTrip(t *testing.T) {
+	snapshot := snapshotAfter(t, 31)
+	if len(snapshot.Moves) != 31 || snapshot.CurrentPlayerID != 1 || snapshot.Round != 16 {
+		t.Fatalf("Expected a snapshot after 31 moves with player 1 at round 16, got %d moves, player %d, round %d",
+			len(snapshot.Moves), snapshot.CurrentPlayerID, snapshot.Round)
+	}
+
+	controller1, controller2 := newAIControllers(models.Fato)
+	restored, err := game.RestoreGameSession(snapshot, controller1, controller2)
+	if err != nil {
+		t.Fatalf("Expected no error restoring the snapshot, got: %v", err)
+	}
+	if restored.IsSetupPhase() || restored.IsRunning() {
+		t.Error("Expected the restored session to be out of setup and not running")
+	}
+	if again := restored.Snapshot(); !reflect.DeepEqual(again, snapshot) {
+		t.Errorf("Expected the restored session to have the same snapshot\nexpected: %+v\ngot: %+v", snapshot, again)
+	}
+
+	// The restored game continues where it stopped
+	if err := restored.Start(); err != nil {
+		t.Fatalf("Expected no error starting the restored session, got: %v", err)
+	}
+	defer restored.Stop()
+	if !restored.WaitForMoveNotification(5 * time.Second) {
+		t.Fatal("Expected the restored game to continue")
+	}
+	if state := restored.GetGameState(); state.MoveCount != 32 {
+		t.Errorf("Expected move 32 to be played next, got %d moves", state.MoveCount)
+	}
+	restored.AckMoveProcessed()
+}
+
+func TestRestoreGameSessionRejectsInconsistentSnapshots(t *testing.T) {
+	snapshot := snapshotAfter(t, 10)
+
+	testCases := []struct {
+		name   string
+		change func(s *models.GameSnapshot)
+	}{
+		{"Board", func(s *models.GameSnapshot) { s.Board = snapshotAfter(t, 12).Board }},
+		{"Round", func(s *models.GameSnapshot) { s.Round++ }},
+		{"Result", func(s *models.GameSnapshot) { s.Moves[9].Result = models.ResultCapture }},
+		{"Move", func(s *models.GameSnapshot) { s.Moves[0].ToX, s.Moves[0].ToY = s.Moves[0].FromX, s.Moves[0].FromY }},
+		{"InitialState", func(s *models.GameSnapshot) { s.InitialState[5][0] = s.InitialState[9][0] }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			broken := snapshot
+			broken.Moves = append([]models.HistoricalMove(nil), snapshot.Moves...)
+			broken.InitialState = make([][]models.PieceData, len(snapshot.InitialState))
+			for y, row := range snapshot.InitialState {
+				broken.InitialState[y] = append([]models.PieceData(nil), row...)
+			}
+			tc.change(&broken)
+
+			controller1, controller2 := newAIControllers(models.Fato)
+			if _, err := game.RestoreGameSession(broken, controller1, controller2); err == nil {
+				t.Error("Expected an error restoring an inconsistent snapshot")
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	snapshot := snapshotAfter(t, 20)
+
+	initial, err := game.Replay(snapshot.InitialState, nil)
+	if err != nil {
+		t.Fatalf("Expected no error replaying no moves, got: %v", err)
+	}
+	field := initial.Board.GetField()
+	for y, row := range snapshot.InitialState {
+		for x, data := range row {
+			piece := field[y][x]
+			if (piece == nil) != (data.OwnerID == -1) ||
+				piece != nil && (piece.GetOwner().GetID() != data.OwnerID || string(piece.GetRank()) != data.Rank) {
+				t.Fatalf("Expected the initial board to match the initial state at (%d,%d)", x, y)
+			}
+		}
+	}
+
+	g, err := game.Replay(snapshot.InitialState, snapshot.Moves[:15])
+	if err != nil {
+		t.Fatalf("Expected no error replaying 15 moves, got: %v", err)
+	}
+	if len(g.MoveHistory) != 15 || g.CurrentPlayer.GetID() != 1 || g.GetRound() != 8 {
+		t.Errorf("Expected player 1 to move at round 8 after 15 moves, got player %d at round %d after %d moves",
+			g.CurrentPlayer.GetID(), g.GetRound(), len(g.MoveHistory))
+	}
+
+	moves := append([]models.HistoricalMove(nil), snapshot.Moves...)
+	moves[3].PlayerID = 1 - moves[3].PlayerID
+	if _, err := game.Replay(snapshot.InitialState, moves); err == nil {
+		t.Error("Expected an error replaying a move out of turn")
+	}
+}
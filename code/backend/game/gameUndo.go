@@ -0,0 +1,177 @@
+package game
+
+import (
+	"digital-innovation/stratego/engine"
+	"errors"
+)
+
+var (
+	ErrNothingToUndo = errors.New("no move to undo")
+	ErrNothingToRedo = errors.New("no move to redo")
+)
+
+// pieceState is the part of a piece that a move can change.
+type pieceState struct {
+	alive    bool
+	revealed bool
+	moved    bool
+	rescued  bool
+	index    int // index among the owner's alive pieces, -1 if not tracked
+	pos      engine.Position
+	tracked  bool // whether the owner knew the position of the piece
+}
+
+func savePieceState(piece *engine.Piece) pieceState {
+	owner := piece.GetOwner()
+	pos, tracked := owner.GetPiecePosition(piece)
+	return pieceState{
+		alive:    piece.IsAlive(),
+		revealed: piece.IsRevealed(),
+		moved:    piece.HasMoved(),
+		rescued:  piece.HasRescued(),
+		index:    owner.IndexOfPiece(piece),
+		pos:      pos,
+		tracked:  tracked,
+	}
+}
+
+func (s pieceState) restore(piece *engine.Piece) {
+	owner := piece.GetOwner()
+	switch {
+	case s.alive && !piece.IsAlive():
+		piece.Revive(s.pos, s.index)
+	case s.tracked:
+		owner.UpdatePiecePosition(piece, s.pos)
+	default:
+		owner.RemovePiece(piece)
+	}
+
+	piece.SetMoved(s.moved)
+	piece.SetRescued(s.rescued)
+	if s.revealed {
+		piece.Reveal()
+	} else {
+		piece.Hide()
+	}
+}
+
+// undoRecord holds everything MakeMove changes, so the move can be taken back exactly.
+type undoRecord struct {
+	move              engine.Move
+	piece             *engine.Piece
+	pieceState        pieceState
+	from, to          *engine.Piece // pieces on the squares of the move before it was made
+	toState           pieceState
+	lastCombat        *CombatResult
+	round             int
+	currentPlayer     *engine.Player
+	currentController engine.PlayerController
+	winner            *engine.Player
+	winCause          WinCause
+	gameOver          bool
+	won               []bool
+	hidden            []*engine.Piece // pieces hidden because a new round started
+	rescued           *engine.Piece   // piece brought back by the rescue rule, nil if none
+	rescuedAt         engine.Position
+}
+
+// redoRecord is a move taken back by UnmakeMove, which can be made again with RedoMove.
+type redoRecord struct {
+	move  engine.Move
+	piece *engine.Piece
+}
+
+func (g *Game) newUndoRecord(move *engine.Move, piece *engine.Piece) undoRecord {
+	record := undoRecord{
+		move:              *move,
+		piece:             piece,
+		pieceState:        savePieceState(piece),
+		from:              g.Board.GetPieceAt(move.GetFrom()),
+		to:                g.Board.GetPieceAt(move.GetTo()),
+		lastCombat:        g.LastCombat,
+		round:             g.round,
+		currentPlayer:     g.CurrentPlayer,
+		currentController: g.CurrentController,
+		winner:            g.winner,
+		winCause:          g.winCause,
+		gameOver:          g.gameOver,
+		won:               make([]bool, len(g.Players)),
+	}
+	if record.to != nil {
+		record.toState = savePieceState(record.to)
+	}
+	for i, player := range g.Players {
+		record.won[i] = player.HasWon()
+	}
+	return record
+}
+
+// EnableUndo keeps the state before every following move, so it can be taken back with UnmakeMove.
+// It is off by default, so games that are only played forward do not grow with undo records; Clone enables it.
+func (g *Game) EnableUndo() {
+	g.undoEnabled = true
+}
+
+// UnmakeMove takes back the last move made with MakeMove and restores the exact state before it:
+// the board, the revealed and alive flags of the pieces, the piece tracking and scores of the players,
+// LastCombat, the current player, the round and the winner.
+// Observers (AI) are not notified, so search-based AIs should only use it on a Clone of the game.
+// It returns ErrNothingToUndo if no move was made since undo was enabled.
+func (g *Game) UnmakeMove() error {
+	n := len(g.undoStack)
+	if n == 0 {
+		return ErrNothingToUndo
+	}
+	record := g.undoStack[n-1]
+	g.undoStack = g.undoStack[:n-1]
+
+	for _, piece := range record.hidden {
+		piece.Reveal()
+	}
+	if record.rescued != nil {
+		g.Board.SetPieceAt(record.rescuedAt, nil)
+		record.rescued.Eliminate()
+	}
+
+	g.Board.SetPieceAt(record.move.GetFrom(), record.from)
+	g.Board.SetPieceAt(record.move.GetTo(), record.to)
+	record.pieceState.restore(record.piece)
+	if record.to != nil {
+		record.toState.restore(record.to)
+	}
+
+	for i, player := range g.Players {
+		if record.won[i] {
+			player.SetWinner()
+		} else {
+			player.ClearWinner()
+		}
+	}
+
+	g.LastCombat = record.lastCombat
+	g.round = record.round
+	g.CurrentPlayer = record.currentPlayer
+	g.CurrentController = record.currentController
+	g.winner = record.winner
+	g.winCause = record.winCause
+	g.gameOver = record.gameOver
+	g.MoveHistory = g.MoveHistory[:len(g.MoveHistory)-1]
+	g.HistoricalHistory = g.HistoricalHistory[:len(g.HistoricalHistory)-1]
+
+	g.redoStack = append(g.redoStack, redoRecord{move: record.move, piece: record.piece})
+	return nil
+}
+
+// RedoMove makes the last move taken back by UnmakeMove again.
+// Making any other move with MakeMove discards the moves that can be redone.
+// It returns ErrNothingToRedo if there is no move to redo.
+func (g *Game) RedoMove() error {
+	n := len(g.redoStack)
+	if n == 0 {
+		return ErrNothingToRedo
+	}
+	record := g.redoStack[n-1]
+	g.redoStack = g.redoStack[:n-1]
+	g.makeMove(&record.move, record.piece)
+	return nil
+}
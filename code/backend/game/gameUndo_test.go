@@ -0,0 +1,181 @@
+package game_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// snapshot describes everything a move can change, so two states can be compared.
+func snapshot(g *game.Game) string {
+	var sb strings.Builder
+	field := g.Board.GetField()
+	for y := range 10 {
+		for x := range 10 {
+			if piece := field[y][x]; piece != nil {
+				fmt.Fprintf(&sb, "%d,%d:%s/%d/%t/%t/%t ", x, y, piece.GetType().GetName(),
+					piece.GetOwner().GetID(), piece.IsAlive(), piece.IsRevealed(), piece.HasMoved())
+			}
+		}
+	}
+	for _, player := range g.Players {
+		fmt.Fprintf(&sb, "\nplayer %d score=%d won=%t pieces:", player.GetID(), player.GetPieceScore(), player.HasWon())
+		for _, piece := range player.GetAlivePieces() {
+			pos, _ := player.GetPiecePosition(piece)
+			fmt.Fprintf(&sb, " %s@%d,%d", piece.GetType().GetName(), pos.X, pos.Y)
+		}
+	}
+	fmt.Fprintf(&sb, "\nround=%d current=%d over=%t cause=%s history=%d/%d",
+		g.GetRound(), g.CurrentPlayer.GetID(), g.IsGameOver(), g.GetWinCause(),
+		len(g.MoveHistory), len(g.HistoricalHistory))
+	if combat := g.GetLastCombat(); combat != nil {
+		fmt.Fprintf(&sb, " combat=%v->%v %s/%s", combat.AttackerPosition, combat.DefenderPosition,
+			combat.AttackerPiece.GetType().GetName(), combat.DefenderPiece.GetType().GetName())
+	}
+	return sb.String()
+}
+
+// randomMove picks a random legal move for the current player, or returns false if there is none.
+func randomMove(g *game.Game, rng *rand.Rand) (engine.Move, bool) {
+	var moves []engine.Move
+	for _, piece := range g.CurrentPlayer.GetAlivePieces() {
+		pos, _ := g.CurrentPlayer.GetPiecePosition(piece)
+		legal, err := g.ListLegalMoves(pos)
+		if err == nil {
+			moves = append(moves, legal...)
+		}
+	}
+	if len(moves) == 0 {
+		return engine.Move{}, false
+	}
+	return moves[rng.IntN(len(moves))], true
+}
+
+func TestUnmakeMoveRestoresState(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+	g.EnableUndo()
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var states []string
+	for range 300 {
+		if g.IsGameOver() {
+			break
+		}
+		move, ok := randomMove(g, rng)
+		if !ok {
+			break
+		}
+		states = append(states, snapshot(g))
+		g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+	}
+
+	for i := len(states) - 1; i >= 0; i-- {
+		if err := g.UnmakeMove(); err != nil {
+			t.Fatalf("Expected move %d to be undone, got: %v", i, err)
+		}
+		if got := snapshot(g); got != states[i] {
+			t.Fatalf("Expected state before move %d to be restored\nexpected: %s\ngot:      %s", i, states[i], got)
+		}
+	}
+
+	if err := g.UnmakeMove(); !errors.Is(err, game.ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo, got: %v", err)
+	}
+}
+
+func TestUnmakeMoveFlagCapture(t *testing.T) {
+	player1 := engine.NewPlayer(1, "Alice", "red")
+	controller1 := engine.NewHumanPlayerController(&player1)
+	player2 := engine.NewPlayer(2, "Bob", "blue")
+	controller2 := engine.NewHumanPlayerController(&player2)
+	g := game.NewGame(controller1, controller2)
+	g.EnableUndo()
+
+	attacker := engine.NewPiece(models.Major, &player1)
+	flag := engine.NewPiece(models.Flag, &player2)
+	g.Board.SetPieceAt(engine.NewPosition(0, 0), attacker)
+	g.Board.SetPieceAt(engine.NewPosition(0, 1), flag)
+	g.InitializePieces()
+
+	move := engine.NewMove(engine.NewPosition(0, 0), engine.NewPosition(0, 1), &player1)
+	g.MakeMove(&move, attacker)
+	if err := g.UnmakeMove(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if g.IsGameOver() || g.GetWinner() != nil || player1.HasWon() {
+		t.Errorf("Expected the game to be running again after undoing the flag capture")
+	}
+	if !flag.IsAlive() || flag.IsRevealed() || attacker.IsRevealed() {
+		t.Errorf("Expected the flag to be alive and both pieces hidden again")
+	}
+	if g.Board.GetPieceAt(engine.NewPosition(0, 1)) != flag || g.Board.GetPieceAt(engine.NewPosition(0, 0)) != attacker {
+		t.Errorf("Expected both pieces back on their squares")
+	}
+	if len(player2.GetAlivePieces()) != 1 {
+		t.Errorf("Expected the flag to be tracked again, got %d pieces", len(player2.GetAlivePieces()))
+	}
+	if g.CurrentPlayer != &player1 {
+		t.Errorf("Expected player1 to be on turn again")
+	}
+}
+
+func TestUndoIsOffByDefault(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.QuickStart(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2))
+	move, _ := randomMove(g, rand.New(rand.NewPCG(1, 2)))
+	g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+
+	if err := g.UnmakeMove(); !errors.Is(err, game.ErrNothingToUndo) {
+		t.Errorf("Expected no undo records without EnableUndo, got: %v", err)
+	}
+
+	// A clone can take back the moves made on it
+	clone := g.Clone()
+	move, _ = randomMove(clone, rand.New(rand.NewPCG(3, 4)))
+	clone.MakeMove(&move, clone.Board.GetPieceAt(move.GetFrom()))
+	if err := clone.UnmakeMove(); err != nil {
+		t.Errorf("Expected the clone to undo its move, got: %v", err)
+	}
+}
+
+func TestRedoMove(t *testing.T) {
+	player1 := engine.NewPlayer(1, "Alice", "red")
+	controller1 := engine.NewHumanPlayerController(&player1)
+	player2 := engine.NewPlayer(2, "Bob", "blue")
+	controller2 := engine.NewHumanPlayerController(&player2)
+	g := game.NewGame(controller1, controller2)
+	g.EnableUndo()
+
+	attacker := engine.NewPiece(models.Captain, &player1)
+	defender := engine.NewPiece(models.Scout, &player2)
+	g.Board.SetPieceAt(engine.NewPosition(0, 0), attacker)
+	g.Board.SetPieceAt(engine.NewPosition(0, 1), defender)
+	g.InitializePieces()
+
+	move := engine.NewMove(engine.NewPosition(0, 0), engine.NewPosition(0, 1), &player1)
+	g.MakeMove(&move, attacker)
+	after := snapshot(g)
+
+	if err := g.UnmakeMove(); err != nil {
+		t.Fatalf("Expected no error on undo, got: %v", err)
+	}
+	if err := g.RedoMove(); err != nil {
+		t.Fatalf("Expected no error on redo, got: %v", err)
+	}
+	if got := snapshot(g); got != after {
+		t.Errorf("Expected redo to recreate the state after the move\nexpected: %s\ngot:      %s", after, got)
+	}
+
+	if err := g.RedoMove(); !errors.Is(err, game.ErrNothingToRedo) {
+		t.Errorf("Expected ErrNothingToRedo, got: %v", err)
+	}
+}
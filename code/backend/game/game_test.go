@@ -259,3 +259,45 @@ func TestMakeMoveCapturingFlag(t *testing.T) {
 		t.Errorf("Expected player1 to be the winner after capturing the flag")
 	}
 }
+
+func TestListLegalMovesTwoSquareRule(t *testing.T) {
+	player1 := engine.NewPlayer(1, "Alice", "red")
+	controller1 := engine.NewHumanPlayerController(&player1)
+	player2 := engine.NewPlayer(2, "Bob", "blue")
+	controller2 := engine.NewHumanPlayerController(&player2)
+	game := game.NewGame(controller1, controller2)
+
+	piece1 := engine.NewPiece(models.Major, &player1)
+	piece2 := engine.NewPiece(models.Major, &player2)
+	game.Board.SetPieceAt(engine.NewPosition(0, 9), piece1)
+	game.Board.SetPieceAt(engine.NewPosition(9, 0), piece2)
+
+	// Both players shuttle their piece back and forth three times
+	for i := range 3 {
+		from, to := engine.NewPosition(0, 9), engine.NewPosition(0, 8)
+		opponentFrom, opponentTo := engine.NewPosition(9, 0), engine.NewPosition(9, 1)
+		if i%2 == 1 {
+			from, to = to, from
+			opponentFrom, opponentTo = opponentTo, opponentFrom
+		}
+		move1 := engine.NewMove(from, to, &player1)
+		game.MakeMove(&move1, piece1)
+		move2 := engine.NewMove(opponentFrom, opponentTo, &player2)
+		game.MakeMove(&move2, piece2)
+	}
+
+	moves, err := game.ListLegalMoves(engine.NewPosition(0, 8))
+	if err != nil {
+		t.Fatalf("Expected no error listing moves, got: %v", err)
+	}
+	for _, move := range moves {
+		if move.GetTo() == engine.NewPosition(0, 9) {
+			t.Errorf("Expected move back to (0,9) to be forbidden by the two-square rule")
+		}
+	}
+
+	back := engine.NewMove(engine.NewPosition(0, 8), engine.NewPosition(0, 9), &player1)
+	if err := game.CheckRepetition(&back); err == nil {
+		t.Errorf("Expected repetition error for a fourth move between the same squares")
+	}
+}
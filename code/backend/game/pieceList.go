@@ -5,37 +5,31 @@ import (
 	"digital-innovation/stratego/models"
 )
 
-var pieceTypes = []models.PieceType{
-	models.Flag,
-	models.Bomb,
-	models.Spy,
-	models.Scout,
-	models.Miner,
-	models.Sergeant,
-	models.Lieutenant,
-	models.Captain,
-	models.Major,
-	models.Colonel,
-	models.General,
-	models.Marshal,
+// GetPieceList returns the pieces of a classic army
+func GetPieceList(player *engine.Player) []*engine.Piece {
+	return GetArmy(player, models.ClassicRules())
 }
 
-func GetPieceList(player *engine.Player) []*engine.Piece {
-	pieceList := make([]*engine.Piece, 0, 40)
+// GetArmy returns the pieces of the army of a rule set, from the flag to the marshal
+func GetArmy(player *engine.Player, rules models.RuleSet) []*engine.Piece {
+	pieceList := make([]*engine.Piece, 0, rules.ArmySize())
 
-	for _, pieceType := range pieceTypes {
-		for range pieceType.GetCount() {
+	for _, pieceType := range models.PieceTypes {
+		for range rules.Count(pieceType) {
 			pieceList = append(pieceList, engine.NewPiece(pieceType, player))
 		}
 	}
 	return pieceList
 }
 
+// GetPieceListStrategicValue sums up the strategic value of the pieces, nil entries of empty squares are skipped
 func GetPieceListStrategicValue(pieceList []*engine.Piece) int {
 	strategicValue := 0
 
 	for _, piece := range pieceList {
-		strategicValue += piece.GetType().GetStrategicValue()
+		if piece != nil {
+			strategicValue += piece.GetType().GetStrategicValue()
+		}
 	}
 	return strategicValue
 }
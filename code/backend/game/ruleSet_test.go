@@ -0,0 +1,148 @@
+package game_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestBuiltInRuleSetsAreValid(t *testing.T) {
+	expectedSizes := map[string]int{models.RuleSetClassic: 40, models.RuleSetBarrage: 8, models.RuleSetDuel: 20, models.RuleSetDuelSmall: 16}
+	for _, name := range models.RuleSetNames {
+		rules, err := models.RuleSetByName(name)
+		if err != nil {
+			t.Fatalf("Expected rule set %s to exist, got: %v", name, err)
+		}
+		if err := rules.Validate(); err != nil {
+			t.Errorf("Expected rule set %s to be valid, got: %v", name, err)
+		}
+		if rules.ArmySize() != expectedSizes[name] {
+			t.Errorf("Expected %d pieces in rule set %s, got %d", expectedSizes[name], name, rules.ArmySize())
+		}
+	}
+	if _, err := models.RuleSetByName("chess"); err == nil {
+		t.Error("Expected an error for an unknown rule set")
+	}
+}
+
+func TestRuleSetValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		change func(r *models.RuleSet)
+	}{
+		{"NoFlag", func(r *models.RuleSet) { delete(r.Army, "0") }},
+		{"OnlyBombs", func(r *models.RuleSet) { r.Army = map[string]int{"0": 1, "B": 3} }},
+		{"UnknownRank", func(r *models.RuleSet) { r.Army["X"] = 1 }},
+		{"TooManyPieces", func(r *models.RuleSet) { r.SetupRows = 3 }},
+		{"TooManyRows", func(r *models.RuleSet) { r.SetupRows = 5 }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := models.ClassicRules()
+			tc.change(&rules)
+			if err := rules.Validate(); err == nil {
+				t.Error("Expected an invalid rule set to be rejected")
+			}
+		})
+	}
+}
+
+func TestQuickStartWithRules(t *testing.T) {
+	testCases := []struct {
+		rules            models.RuleSet
+		pieces, firstRow int // pieces of each player, first setup row of player 1
+	}{
+		{models.BarrageRules(), 8, 6},
+		{models.DuelRules(), 20, 8},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.rules.Name, func(t *testing.T) {
+			player1 := engine.NewPlayer(0, "Player 1", "red")
+			player2 := engine.NewPlayer(1, "Player 2", "blue")
+			g := game.QuickStartWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2),
+				tc.rules, rand.New(rand.NewPCG(1, 2)))
+
+			if len(player1.GetAlivePieces()) != tc.pieces || len(player2.GetAlivePieces()) != tc.pieces {
+				t.Fatalf("Expected %d pieces per player, got %d and %d",
+					tc.pieces, len(player1.GetAlivePieces()), len(player2.GetAlivePieces()))
+			}
+			for _, piece := range player1.GetAlivePieces() {
+				if pos, _ := player1.GetPiecePosition(piece); pos.Y < tc.firstRow {
+					t.Errorf("Expected player 1 to set up from row %d, got a piece in row %d", tc.firstRow, pos.Y)
+				}
+			}
+			for _, piece := range player2.GetAlivePieces() {
+				if pos, _ := player2.GetPiecePosition(piece); pos.Y > 9-tc.firstRow {
+					t.Errorf("Expected player 2 to set up up to row %d, got a piece in row %d", 9-tc.firstRow, pos.Y)
+				}
+			}
+
+			// The initial state of a smaller army replays with the same rules
+			replayed, err := game.ReplayWithRules(tc.rules, g.GetInitialBoardState(), nil)
+			if err != nil {
+				t.Fatalf("Expected no error replaying the initial state, got: %v", err)
+			}
+			if len(replayed.Players[0].GetAlivePieces()) != tc.pieces {
+				t.Errorf("Expected the replayed game to have %d pieces per player, got %d",
+					tc.pieces, len(replayed.Players[0].GetAlivePieces()))
+			}
+		})
+	}
+}
+
+func TestSetupGameRejectsWrongArmy(t *testing.T) {
+	player1 := engine.NewPlayer(0, "Player 1", "red")
+	player2 := engine.NewPlayer(1, "Player 2", "blue")
+	g := game.NewGameWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2),
+		models.BarrageRules())
+
+	// 40 squares with a classic army
+	err := game.SetupGame(g, game.GetPieceList(&player1), game.GetPieceList(&player2))
+	if err == nil || !strings.Contains(err.Error(), "invalid army of player 1") {
+		t.Errorf("Expected a classic army to be rejected in a Barrage game, got: %v", err)
+	}
+}
+
+func TestParseSetupWithRules(t *testing.T) {
+	player := engine.NewPlayer(0, "Player 1", "red")
+	rules := models.BarrageRules()
+
+	setup := strings.Repeat(".", 32) + "0B12239M"
+	pieces, err := game.ParseSetupWithRules(&player, rules, []byte(setup))
+	if err != nil {
+		t.Fatalf("Expected no error parsing a Barrage setup, got: %v", err)
+	}
+	if len(pieces) != 40 || pieces[0] != nil || pieces[32] == nil || pieces[32].GetRank() != '0' {
+		t.Errorf("Expected 32 empty squares followed by the flag, got %d squares", len(pieces))
+	}
+
+	if _, err := game.ParseSetupWithRules(&player, rules, []byte(strings.Repeat(".", 32)+"0B1223MM")); err == nil {
+		t.Error("Expected a setup with two marshals to be rejected")
+	}
+}
+
+func TestRepetitionRulesCanBeTurnedOff(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.RepetitionRules = false
+
+	player1 := engine.NewPlayer(0, "Player 1", "red")
+	player2 := engine.NewPlayer(1, "Player 2", "blue")
+	g := game.NewGameWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules)
+	g.MoveHistory = []engine.Move{
+		engine.NewMove(engine.NewPosition(0, 6), engine.NewPosition(0, 5), &player1),
+		engine.NewMove(engine.NewPosition(0, 5), engine.NewPosition(0, 6), &player1),
+		engine.NewMove(engine.NewPosition(0, 6), engine.NewPosition(0, 5), &player1),
+	}
+
+	move := engine.NewMove(engine.NewPosition(0, 5), engine.NewPosition(0, 6), &player1)
+	if err := g.CheckRepetition(&move); err != nil {
+		t.Errorf("Expected no repetition check without the repetition rules, got: %v", err)
+	}
+	g.Rules.RepetitionRules = true
+	if err := g.CheckRepetition(&move); err != engine.ErrTwoSquareRule {
+		t.Errorf("Expected the two-square rule with the repetition rules, got: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package game_test
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"math/rand/v2"
+	"testing"
+)
+
+// newSpecialRulesGame creates an empty classic board played with the rules, with the flags of both players in a corner
+func newSpecialRulesGame(rules models.RuleSet) (*game.Game, *engine.Player, *engine.Player) {
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	g := game.NewGameWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules)
+	g.EnableUndo()
+	g.Board.SetPieceAt(engine.NewPosition(0, 9), engine.NewPiece(models.Flag, &player1))
+	g.Board.SetPieceAt(engine.NewPosition(9, 0), engine.NewPiece(models.Flag, &player2))
+	return g, &player1, &player2
+}
+
+func TestSilentDefense(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.SilentDefense = true
+	g, player1, player2 := newSpecialRulesGame(rules)
+
+	captain := engine.NewPiece(models.Captain, player1)
+	major := engine.NewPiece(models.Major, player2)
+	g.Board.SetPieceAt(engine.NewPosition(4, 6), captain)
+	g.Board.SetPieceAt(engine.NewPosition(4, 5), major)
+	g.InitializePieces()
+
+	move := engine.NewMove(engine.NewPosition(4, 6), engine.NewPosition(4, 5), player1)
+	g.MakeMove(&move, captain)
+
+	if captain.IsAlive() || !major.IsAlive() {
+		t.Fatalf("Expected the major to beat the captain")
+	}
+	if major.IsRevealed() || !captain.IsRevealed() {
+		t.Errorf("Expected only the attacker to be revealed, got attacker %t, defender %t", captain.IsRevealed(), major.IsRevealed())
+	}
+	if combat := g.GetLastCombat(); combat == nil || !combat.DefenderHidden {
+		t.Errorf("Expected the last combat to hide the defender, got: %+v", combat)
+	}
+	recorded := g.HistoricalHistory[0]
+	if recorded.Result != models.ResultLoss || !recorded.DefenderHidden || recorded.Defender.Rank != string(models.Major.GetRank()) {
+		t.Errorf("Expected a loss against a hidden major in the history, got: %+v", recorded)
+	}
+
+	if err := g.UnmakeMove(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !captain.IsAlive() || captain.IsRevealed() || g.GetLastCombat() != nil {
+		t.Errorf("Expected the captain to be alive and hidden again")
+	}
+}
+
+func TestSilentDefenseOnlyHidesWinningDefenders(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.SilentDefense = true
+	g, player1, player2 := newSpecialRulesGame(rules)
+
+	colonel := engine.NewPiece(models.Colonel, player1)
+	major := engine.NewPiece(models.Major, player2)
+	g.Board.SetPieceAt(engine.NewPosition(4, 6), colonel)
+	g.Board.SetPieceAt(engine.NewPosition(4, 5), major)
+	g.InitializePieces()
+
+	move := engine.NewMove(engine.NewPosition(4, 6), engine.NewPosition(4, 5), player1)
+	g.MakeMove(&move, colonel)
+
+	if recorded := g.HistoricalHistory[0]; recorded.Result != models.ResultWin || recorded.DefenderHidden {
+		t.Errorf("Expected a won attack with both pieces revealed, got: %+v", recorded)
+	}
+	if !colonel.IsRevealed() {
+		t.Errorf("Expected the winning attacker to be revealed")
+	}
+}
+
+func TestAggressorAdvantage(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.AggressorAdvantage = true
+	g, player1, player2 := newSpecialRulesGame(rules)
+
+	attacker := engine.NewPiece(models.Miner, player1)
+	defender := engine.NewPiece(models.Miner, player2)
+	g.Board.SetPieceAt(engine.NewPosition(4, 6), attacker)
+	g.Board.SetPieceAt(engine.NewPosition(4, 5), defender)
+	g.InitializePieces()
+
+	move := engine.NewMove(engine.NewPosition(4, 6), engine.NewPosition(4, 5), player1)
+	g.MakeMove(&move, attacker)
+
+	if !attacker.IsAlive() || defender.IsAlive() {
+		t.Errorf("Expected the attacking miner to win the tie")
+	}
+	if g.Board.GetPieceAt(engine.NewPosition(4, 5)) != attacker {
+		t.Errorf("Expected the attacker to move onto the defender's square")
+	}
+	if recorded := g.HistoricalHistory[0]; recorded.Result != models.ResultWin {
+		t.Errorf("Expected the tie to be recorded as a win, got: %s", recorded.Result)
+	}
+}
+
+func TestRescue(t *testing.T) {
+	rules := models.ClassicRules()
+	rules.Rescue = true
+	g, player1, player2 := newSpecialRulesGame(rules)
+
+	// Apart from the flag and the major, the army of player 1 is lost, the marshal is the strongest piece
+	major := engine.NewPiece(models.Major, player1)
+	scout := engine.NewPiece(models.Scout, player2)
+	g.Board.SetPieceAt(engine.NewPosition(0, 1), major)
+	g.Board.SetPieceAt(engine.NewPosition(5, 5), scout)
+	g.InitializePieces()
+	scoreBefore := player1.GetPieceScore()
+
+	move := engine.NewMove(engine.NewPosition(0, 1), engine.NewPosition(0, 0), player1)
+	g.MakeMove(&move, major)
+
+	// The first empty square from the back row of player 1, next to the flag
+	rescued := g.Board.GetPieceAt(engine.NewPosition(1, 9))
+	if rescued == nil || rescued.GetRank() != models.Marshal.GetRank() || rescued.GetOwner() != player1 || !rescued.IsRevealed() {
+		t.Fatalf("Expected a revealed marshal of player 1 at (1,9), got: %v", rescued)
+	}
+	if !major.HasRescued() || len(player1.GetAlivePieces()) != 3 || player1.GetPieceScore() != scoreBefore+rescued.GetStrategicValue() {
+		t.Errorf("Expected the marshal to be tracked and scored, got %d pieces and score %d", len(player1.GetAlivePieces()), player1.GetPieceScore())
+	}
+	expected := models.Rescue{Piece: models.PieceData{Type: "Marshal", Rank: string(models.Marshal.GetRank()), OwnerID: 0}, X: 1, Y: 9}
+	if recorded := g.HistoricalHistory[0].Rescue; recorded == nil || *recorded != expected {
+		t.Errorf("Expected the rescue %+v in the history, got: %+v", expected, recorded)
+	}
+
+	// Undo takes the marshal away again
+	if err := g.UnmakeMove(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if g.Board.GetPieceAt(engine.NewPosition(1, 9)) != nil || major.HasRescued() ||
+		len(player1.GetAlivePieces()) != 2 || player1.GetPieceScore() != scoreBefore {
+		t.Errorf("Expected the rescue to be taken back, got %d pieces and score %d", len(player1.GetAlivePieces()), player1.GetPieceScore())
+	}
+
+	// A piece rescues only once
+	if err := g.RedoMove(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	scoutMove := engine.NewMove(engine.NewPosition(5, 5), engine.NewPosition(5, 6), player2)
+	g.MakeMove(&scoutMove, scout)
+	again := engine.NewMove(engine.NewPosition(0, 0), engine.NewPosition(1, 0), player1)
+	g.MakeMove(&again, major)
+	if g.HistoricalHistory[2].Rescue != nil || len(player1.GetAlivePieces()) != 3 {
+		t.Errorf("Expected no second rescue by the same piece, got: %+v", g.HistoricalHistory[2].Rescue)
+	}
+}
+
+func TestNoRescueWithoutRule(t *testing.T) {
+	g, player1, _ := newSpecialRulesGame(models.ClassicRules())
+
+	major := engine.NewPiece(models.Major, player1)
+	g.Board.SetPieceAt(engine.NewPosition(0, 1), major)
+	g.InitializePieces()
+
+	move := engine.NewMove(engine.NewPosition(0, 1), engine.NewPosition(0, 0), player1)
+	g.MakeMove(&move, major)
+	if g.HistoricalHistory[0].Rescue != nil || len(player1.GetAlivePieces()) != 2 {
+		t.Errorf("Expected no rescue under the classic rules, got: %+v", g.HistoricalHistory[0].Rescue)
+	}
+}
+
+// TestSpecialRulesReplayAndUndo plays a random Barrage game with all special rules, which has rescues and hidden defenders
+func TestSpecialRulesReplayAndUndo(t *testing.T) {
+	rules := models.BarrageRules()
+	rules.SilentDefense = true
+	rules.AggressorAdvantage = true
+	rules.Rescue = true
+	player1 := engine.NewPlayer(0, "Alice", "red")
+	player2 := engine.NewPlayer(1, "Bob", "blue")
+	rng := rand.New(rand.NewPCG(21, 22))
+	g := game.QuickStartWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules, rng)
+	g.EnableUndo()
+	initial, state := snapshot(g), g.GetInitialBoardState()
+
+	for range 1000 {
+		move, ok := randomMove(g, rng)
+		if !ok || g.IsGameOver() {
+			break
+		}
+		g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+	}
+
+	replayed, err := game.ReplayWithRules(rules, state, g.HistoricalHistory)
+	if err != nil {
+		t.Fatalf("Expected the game to replay, got: %v", err)
+	}
+	if replayed.Board.String() != g.Board.String() {
+		t.Errorf("Expected the replayed board to equal the original\n%s\ngot\n%s", g.Board, replayed.Board)
+	}
+
+	for g.UnmakeMove() == nil {
+	}
+	if after := snapshot(g); after != initial {
+		t.Errorf("Expected undoing all moves to restore the start\n%s\ngot\n%s", initial, after)
+	}
+}
@@ -0,0 +1,265 @@
+// Package gravon reads the Stratego games of the Gravon archive, a public corpus of games between humans
+// played on gravon.de and kept in the format of the ISF, and replays them through the engine.
+//
+// A file holds one or more games:
+//
+//	<stratego>
+//	  <game>
+//	    <field content="FBBM...AA__AA__AA...NYQR"/>
+//	    <move id="1" source="B4" target="B5"/>
+//	    ...
+//	    <result type="1" winner="1"/>
+//	  </game>
+//	</stratego>
+//
+// The field lists the 100 squares of the board row by row, starting at A1, the bottom left corner on the side of red.
+// A is an empty square and _ a lake, B to M are the pieces of red and N to Y those of blue by rank with the bomb last:
+// flag, spy, scout, miner, sergeant, lieutenant, captain, major, colonel, general, marshal, bomb.
+// Moves use the same squares, a column letter and a row number; attacks are not marked, the replay resolves them.
+// The winner is 1 for red, 2 for blue and 0 for a draw. Red moves first and is player 0 of the engine.
+package gravon
+
+import (
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Size of the board of the archive, all variants are played on the classic board
+const boardSize = 10
+
+// Result types of the archive
+const (
+	ResultFlagCaptured = 1 // the winner captured the flag
+	ResultNoMoves      = 2 // the loser could not move
+	ResultResigned     = 3 // the loser gave up
+	ResultTimeout      = 4 // the loser ran out of time
+)
+
+// Piece letters of the field: red pieces from 'B', blue pieces from 'N'
+const (
+	emptySquare = 'A'
+	lakeSquare  = '_'
+	redPieces   = 'B'
+	bluePieces  = 'N'
+)
+
+// pieceOrder is the order of the piece letters of both players
+var pieceOrder = []models.PieceType{
+	models.Flag, models.Spy, models.Scout, models.Miner, models.Sergeant, models.Lieutenant,
+	models.Captain, models.Major, models.Colonel, models.General, models.Marshal, models.Bomb,
+}
+
+// Game is a game of the archive as read from the XML
+type Game struct {
+	Field  Field  `xml:"field"`
+	Moves  []Move `xml:"move"`
+	Result Result `xml:"result"`
+}
+
+// Field is the initial setup of a game
+type Field struct {
+	Content string `xml:"content,attr"`
+}
+
+// Move is a move of a game from one square to another
+type Move struct {
+	ID     int    `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+// Result is how a game ended
+type Result struct {
+	Type   int `xml:"type,attr"`
+	Winner int `xml:"winner,attr"` // 1 for red, 2 for blue, 0 for a draw
+}
+
+// Parse reads all games of an archive file
+func Parse(r io.Reader) ([]Game, error) {
+	var games []Game
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "game" {
+			continue
+		}
+		var g Game
+		if err := decoder.DecodeElement(&g, &start); err != nil {
+			return nil, fmt.Errorf("invalid game %d: %w", len(games)+1, err)
+		}
+		games = append(games, g)
+	}
+	if len(games) == 0 {
+		return nil, errors.New("no games found")
+	}
+	return games, nil
+}
+
+// Replay plays the game through the engine, which checks the setups of both players and every move.
+// The rule set is the built-in one with the army of the setups.
+// A result the moves do not lead to, e.g. a resignation, is taken from the archive.
+func (g Game) Replay() (*game.Game, error) {
+	state, err := g.initialState()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := rulesOf(state)
+	if err != nil {
+		return nil, err
+	}
+	replayed, err := game.ReplayWithRules(rules, state, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, move := range g.Moves {
+		if replayed.IsGameOver() {
+			return nil, fmt.Errorf("move %d: the game is over", i+1)
+		}
+		if err := playMove(replayed, move); err != nil {
+			return nil, fmt.Errorf("move %d %s-%s: %w", i+1, move.Source, move.Target, err)
+		}
+	}
+
+	if err := g.applyResult(replayed); err != nil {
+		return nil, err
+	}
+	return replayed, nil
+}
+
+// initialState converts the field to the initial state of the engine, player 0 is red at the bottom of the board
+func (g Game) initialState() ([][]models.PieceData, error) {
+	content := strings.TrimSpace(g.Field.Content)
+	if len(content) != boardSize*boardSize {
+		return nil, fmt.Errorf("field must have %d squares, got %d", boardSize*boardSize, len(content))
+	}
+
+	state := make([][]models.PieceData, boardSize)
+	for y := range state {
+		state[y] = make([]models.PieceData, boardSize)
+	}
+	for i := range len(content) {
+		x, y := i%boardSize, boardSize-1-i/boardSize
+		letter := content[i]
+		data := models.PieceData{OwnerID: -1}
+		switch {
+		case letter == emptySquare || letter == lakeSquare:
+		case letter >= redPieces && letter < redPieces+byte(len(pieceOrder)):
+			data = pieceData(pieceOrder[letter-redPieces], 0)
+		case letter >= bluePieces && letter < bluePieces+byte(len(pieceOrder)):
+			data = pieceData(pieceOrder[letter-bluePieces], 1)
+		default:
+			return nil, fmt.Errorf("invalid piece %q at %s", letter, squareName(x, y))
+		}
+		state[y][x] = data
+	}
+	return state, nil
+}
+
+func pieceData(pieceType models.PieceType, ownerID int) models.PieceData {
+	return models.PieceData{Type: pieceType.GetName(), Rank: string(pieceType.GetRank()), OwnerID: ownerID}
+}
+
+// rulesOf returns the built-in rule set of the classic board with the army of red,
+// the armies of both players are checked by the replay
+func rulesOf(state [][]models.PieceData) (models.RuleSet, error) {
+	counts := make(map[byte]int)
+	for _, row := range state {
+		for _, data := range row {
+			if data.OwnerID == 0 {
+				counts[data.Rank[0]]++
+			}
+		}
+	}
+	for _, name := range models.RuleSetNames {
+		rules, _ := models.RuleSetByName(name)
+		if rules.Layout().Name == models.BoardLayoutClassic && rules.ValidateArmy(counts) == nil {
+			return rules, nil
+		}
+	}
+	return models.RuleSet{}, errors.New("the army of red is not the army of a built-in rule set")
+}
+
+// playMove plays a move of the archive for the player to move
+func playMove(g *game.Game, move Move) error {
+	from, err := parseSquare(move.Source)
+	if err != nil {
+		return err
+	}
+	to, err := parseSquare(move.Target)
+	if err != nil {
+		return err
+	}
+	m := engine.NewMove(from, to, g.CurrentPlayer)
+	if err := g.ValidateMove(&m); err != nil {
+		return err
+	}
+	g.MakeMove(&m, g.Board.GetPieceAt(from))
+	return nil
+}
+
+// applyResult checks the result of the archive against a game that ended on the board,
+// or ends the game with it
+func (g Game) applyResult(replayed *game.Game) error {
+	var winner *engine.Player
+	switch g.Result.Winner {
+	case 0:
+	case 1, 2:
+		winner = replayed.Players[g.Result.Winner-1]
+	default:
+		return fmt.Errorf("invalid winner %d", g.Result.Winner)
+	}
+
+	if replayed.IsGameOver() {
+		if replayed.GetWinner() != winner || g.Result.Type != ResultFlagCaptured {
+			return fmt.Errorf("the moves end the game with a captured flag, the archive gives result %d for winner %d",
+				g.Result.Type, g.Result.Winner)
+		}
+		return nil
+	}
+	if g.Result.Type == ResultFlagCaptured {
+		return errors.New("the archive gives a captured flag, the moves do not capture it")
+	}
+
+	switch {
+	case winner == nil:
+		replayed.SetDraw("")
+	case g.Result.Type == ResultNoMoves:
+		replayed.SetWinner(winner, game.WinCauseNoMovablePieces)
+	default:
+		replayed.SetWinner(winner, "")
+	}
+	return nil
+}
+
+// parseSquare reads a square like B4 as a position of the engine
+func parseSquare(square string) (engine.Position, error) {
+	if len(square) < 2 {
+		return engine.Position{}, fmt.Errorf("invalid square %q", square)
+	}
+	row, err := strconv.Atoi(square[1:])
+	column := int(square[0] - 'A')
+	if err != nil || column < 0 || column >= boardSize || row < 1 || row > boardSize {
+		return engine.Position{}, fmt.Errorf("invalid square %q", square)
+	}
+	return engine.NewPosition(column, boardSize-row), nil
+}
+
+// squareName writes a position of the engine as a square of the archive
+func squareName(x, y int) string {
+	return fmt.Sprintf("%c%d", 'A'+x, boardSize-y)
+}
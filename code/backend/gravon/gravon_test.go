@@ -0,0 +1,243 @@
+package gravon_test
+
+import (
+	"digital-innovation/stratego/datagathering"
+	"digital-innovation/stratego/engine"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/gravon"
+	"digital-innovation/stratego/models"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// classicField is a classic setup of both players in the encoding of the archive, red in rows 1-4 and blue in rows 7-10.
+// Red has B flag, C spy, D scout, E miner, F sergeant, G lieutenant, H captain, I major, J colonel, K general,
+// L marshal and M bomb, blue the same from N to Y. It is written out by hand and does not use the mapping of the package.
+const classicField = "BMMMEEEEEF" + "MMMFFFGGGG" + "HHHHIIIJJC" + "DDDDDDDDKL" +
+	"AA__AA__AA" + "AA__AA__AA" +
+	"PPPPPPPPWX" + "TTTTUUUVVO" + "YYYRRRSSSS" + "NYYYQQQQQR"
+
+// shortGame has the scouts on A4 and A7 trade each other before blue gives up
+const shortGame = `<?xml version="1.0" encoding="UTF-8"?>
+<stratego>
+<game>
+<field content="` + classicField + `"/>
+<move id="1" source="A4" target="A5"/>
+<move id="2" source="A7" target="A6"/>
+<move id="3" source="A5" target="A6"/>
+<move id="4" source="B7" target="B5"/>
+<result type="3" winner="1"/>
+</game>
+</stratego>
+`
+
+func TestParseAndReplay(t *testing.T) {
+	games, err := gravon.Parse(strings.NewReader(shortGame))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(games) != 1 || len(games[0].Moves) != 4 || games[0].Result != (gravon.Result{Type: gravon.ResultResigned, Winner: 1}) {
+		t.Fatalf("Expected one game with 4 moves won by red, got: %+v", games)
+	}
+
+	g, err := games[0].Replay()
+	if err != nil {
+		t.Fatalf("Expected the game to replay, got: %v", err)
+	}
+	if g.Rules.Name != models.RuleSetClassic {
+		t.Errorf("Expected the classic rules, got: %s", g.Rules.Name)
+	}
+
+	// A1 is the bottom left corner on the side of red, A10 the top left corner on the side of blue
+	redFlag, blueFlag := g.Board.GetPieceAt(engine.NewPosition(0, 9)), g.Board.GetPieceAt(engine.NewPosition(0, 0))
+	if redFlag == nil || redFlag.GetType().GetName() != "Flag" || redFlag.GetOwner() != g.Players[0] {
+		t.Errorf("Expected the flag of red on A1, got: %v", redFlag)
+	}
+	if blueFlag == nil || blueFlag.GetType().GetName() != "Flag" || blueFlag.GetOwner() != g.Players[1] {
+		t.Errorf("Expected the flag of blue on A10, got: %v", blueFlag)
+	}
+	// Squares with the letters of the encoding: M and Y are bombs, L and X marshals, C and O spies
+	for square, expected := range map[string]string{"B1": "Bomb", "J4": "Marshal", "J3": "Spy", "B10": "Bomb", "J7": "Marshal", "J8": "Spy"} {
+		column, row := int(square[0]-'A'), 0
+		fmt.Sscan(square[1:], &row)
+		if piece := g.Board.GetPieceAt(engine.NewPosition(column, 10-row)); piece == nil || piece.GetType().GetName() != expected {
+			t.Errorf("Expected a %s on %s, got: %v", expected, square, piece)
+		}
+	}
+
+	if len(g.HistoricalHistory) != 4 || g.HistoricalHistory[2].Result != models.ResultTie {
+		t.Errorf("Expected the scouts to trade on the third move, got: %+v", g.HistoricalHistory)
+	}
+	if scout := g.Board.GetPieceAt(engine.NewPosition(1, 5)); scout == nil || scout.GetOwner() != g.Players[1] {
+		t.Errorf("Expected the scout of blue to move from B7 to B5, got: %v", scout)
+	}
+	if !g.IsGameOver() || g.GetWinner() != g.Players[0] {
+		t.Errorf("Expected red to win by resignation, got winner %v", g.GetWinner())
+	}
+
+	samples, err := datagathering.FromGame(g).Samples()
+	if err != nil || len(samples) != 4 || samples[0].Outcome != 1 || samples[1].Outcome != -1 {
+		t.Errorf("Expected a sample for every move with the outcome of the game, got %d samples, %v", len(samples), err)
+	}
+}
+
+func TestReplayRejectsInvalidGames(t *testing.T) {
+	testCases := []struct {
+		name string
+		game string
+	}{
+		{"ShortField", strings.Replace(shortGame, classicField, classicField[1:], 1)},
+		{"InvalidPiece", strings.Replace(shortGame, classicField, "Z"+classicField[1:], 1)},
+		{"WrongArmy", strings.Replace(shortGame, classicField, "C"+classicField[1:], 1)},
+		{"PieceOutsideSetupRows", strings.Replace(shortGame, "DDDDDDDDKLAA", "DDDDDDDDKAAL", 1)},
+		{"IllegalMove", strings.Replace(shortGame, `source="B7" target="B5"`, `source="A10" target="A9"`, 1)},
+		{"InvalidSquare", strings.Replace(shortGame, `source="B7" target="B5"`, `source="B7" target="B11"`, 1)},
+		{"FlagNotCaptured", strings.Replace(shortGame, `type="3"`, `type="1"`, 1)},
+		{"InvalidWinner", strings.Replace(shortGame, `winner="1"`, `winner="3"`, 1)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			games, err := gravon.Parse(strings.NewReader(tc.game))
+			if err != nil {
+				t.Fatalf("Expected the XML to parse, got: %v", err)
+			}
+			if _, err := games[0].Replay(); err == nil {
+				t.Errorf("Expected the game to be rejected:\n%s", tc.game)
+			}
+		})
+	}
+
+	if _, err := gravon.Parse(strings.NewReader("<stratego></stratego>")); err == nil {
+		t.Error("Expected an error for a file without games")
+	}
+	if _, err := gravon.Parse(strings.NewReader("<stratego><game>")); err == nil {
+		t.Error("Expected an error for invalid XML")
+	}
+}
+
+// randomGame plays random moves from random setups until a flag is captured or the player to move is stuck
+func randomGame(rules models.RuleSet, seed uint64) *game.Game {
+	player1 := engine.NewPlayer(0, "Red", "red")
+	player2 := engine.NewPlayer(1, "Blue", "blue")
+	rng := rand.New(rand.NewPCG(seed, seed+1))
+	g := game.QuickStartWithRules(engine.NewHumanPlayerController(&player1), engine.NewHumanPlayerController(&player2), rules, rng)
+	g.InitialState = g.GetInitialBoardState()
+
+	for !g.IsGameOver() {
+		var moves []engine.Move
+		for _, piece := range g.CurrentPlayer.GetAlivePieces() {
+			pos, _ := g.CurrentPlayer.GetPiecePosition(piece)
+			legal, _ := g.ListLegalMoves(pos)
+			moves = append(moves, legal...)
+		}
+		if len(moves) == 0 {
+			g.SetWinner(g.Players[1-g.CurrentPlayer.GetID()], game.WinCauseNoMovablePieces)
+			break
+		}
+		move := moves[rng.IntN(len(moves))]
+		g.MakeMove(&move, g.Board.GetPieceAt(move.GetFrom()))
+	}
+	return g
+}
+
+// archiveXML writes a game played by the engine in the format of the archive
+func archiveXML(g *game.Game) string {
+	letters := map[string]int{}
+	for i, rank := range []models.PieceType{models.Flag, models.Spy, models.Scout, models.Miner, models.Sergeant, models.Lieutenant,
+		models.Captain, models.Major, models.Colonel, models.General, models.Marshal, models.Bomb} {
+		letters[string(rank.GetRank())] = i
+	}
+
+	var field strings.Builder
+	for row := 1; row <= 10; row++ {
+		for x := range 10 {
+			data := g.InitialState[10-row][x]
+			switch {
+			case data.OwnerID >= 0:
+				field.WriteByte(byte('B' + 12*data.OwnerID + letters[data.Rank]))
+			case g.Board.IsLake(engine.NewPosition(x, 10-row)):
+				field.WriteByte('_')
+			default:
+				field.WriteByte('A')
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<stratego>\n<game>\n<field content=%q/>\n", field.String())
+	for i, move := range g.HistoricalHistory {
+		fmt.Fprintf(&sb, "<move id=\"%d\" source=\"%c%d\" target=\"%c%d\"/>\n", i+1, 'A'+move.FromX, 10-move.FromY, 'A'+move.ToX, 10-move.ToY)
+	}
+	resultType := gravon.ResultNoMoves
+	if g.GetWinCause() == game.WinCauseFlagCaptured {
+		resultType = gravon.ResultFlagCaptured
+	}
+	fmt.Fprintf(&sb, "<result type=\"%d\" winner=\"%d\"/>\n</game>\n</stratego>\n", resultType, *g.GetWinnerID()+1)
+	return sb.String()
+}
+
+func TestReplayRandomGames(t *testing.T) {
+	for _, rules := range []models.RuleSet{models.ClassicRules(), models.BarrageRules(), models.DuelRules()} {
+		t.Run(rules.Name, func(t *testing.T) {
+			original := randomGame(rules, 11)
+			games, err := gravon.Parse(strings.NewReader(archiveXML(original)))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			g, err := games[0].Replay()
+			if err != nil {
+				t.Fatalf("Expected the game to replay, got: %v", err)
+			}
+			if !reflect.DeepEqual(g.Rules, rules) {
+				t.Errorf("Expected the %s rules, got: %s", rules.Name, g.Rules.Name)
+			}
+			if !reflect.DeepEqual(g.HistoricalHistory, original.HistoricalHistory) {
+				t.Errorf("Expected the replayed history to equal the original")
+			}
+			if g.Board.String() != original.Board.String() {
+				t.Errorf("Expected the replayed board to equal the original\n%s\ngot\n%s", original.Board, g.Board)
+			}
+			if !reflect.DeepEqual(g.GetWinnerID(), original.GetWinnerID()) || g.GetWinCause() != original.GetWinCause() {
+				t.Errorf("Expected player %d to win by %s, got: %v by %s", *original.GetWinnerID(), original.GetWinCause(), g.GetWinnerID(), g.GetWinCause())
+			}
+		})
+	}
+}
+
+func TestImportDir(t *testing.T) {
+	dir := t.TempDir()
+	twoGames := strings.Replace(shortGame, "</stratego>", "", 1) +
+		strings.Replace(strings.Replace(shortGame, `<?xml version="1.0" encoding="UTF-8"?>`+"\n<stratego>", "", 1),
+			`source="B7" target="B5"`, `source="A10" target="A9"`, 1)
+	files := map[string]string{
+		"classic-1.xml": shortGame,
+		"classic-2.xml": twoGames,
+		"broken.xml":    "<stratego><game>",
+		"notes.txt":     "not a game",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var stored []string
+	summary, err := gravon.ImportDir(dir, func(id string, g *game.Game) error {
+		stored = append(stored, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if summary != (gravon.Summary{Files: 2, Games: 3, Imported: 2}) {
+		t.Errorf("Expected 2 of 3 games from 2 files to be imported, got: %+v", summary)
+	}
+	if expected := []string{"gravon-classic-1", "gravon-classic-2-1"}; !reflect.DeepEqual(stored, expected) {
+		t.Errorf("Expected the games %v to be stored, got: %v", expected, stored)
+	}
+}
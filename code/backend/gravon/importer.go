@@ -0,0 +1,85 @@
+package gravon
+
+import (
+	"digital-innovation/stratego/db"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/models"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Summary counts the files and games of an import
+type Summary struct {
+	Files    int // XML files read
+	Games    int // games found in the files
+	Imported int // games that replayed and were stored
+}
+
+// StoreFunc stores a replayed game of the archive under an ID
+type StoreFunc func(id string, g *game.Game) error
+
+// ImportDir replays the games of all XML files in the directory, in the order of their names, and stores those that replay.
+// A game gets the ID gravon-<file name>, with -<n> for the n-th game of a file with several games.
+// Files and games that cannot be read or replayed are logged and skipped, an error of store ends the import.
+func ImportDir(dir string, store StoreFunc) (Summary, error) {
+	var summary Summary
+	paths, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil {
+		return summary, err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		games, err := parseFile(path)
+		if err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+			continue
+		}
+		summary.Files++
+		summary.Games += len(games)
+
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for i, g := range games {
+			id := "gravon-" + name
+			if len(games) > 1 {
+				id = fmt.Sprintf("%s-%d", id, i+1)
+			}
+			replayed, err := g.Replay()
+			if err != nil {
+				log.Printf("Skipping game %s: %v", id, err)
+				continue
+			}
+			if err := store(id, replayed); err != nil {
+				return summary, fmt.Errorf("failed to store game %s: %w", id, err)
+			}
+			summary.Imported++
+		}
+	}
+	return summary, nil
+}
+
+func parseFile(path string) ([]Game, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// StoreInDB stores a replayed game in the games and game_moves tables as an imported game without users
+func StoreInDB(id string, g *game.Game) error {
+	if err := db.SaveGame(id, nil, nil, models.Imported, g.Rules, g.InitialState, g.GetWinnerID()); err != nil {
+		return err
+	}
+	for _, move := range g.HistoricalHistory {
+		if err := db.SaveMove(id, move); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,12 +4,18 @@ import (
 	aivsai "digital-innovation/stratego/ai/AIvsAI"
 	"digital-innovation/stratego/api"
 	"digital-innovation/stratego/auth"
+	"digital-innovation/stratego/datagathering"
 	"digital-innovation/stratego/db"
+	"digital-innovation/stratego/game"
+	"digital-innovation/stratego/gravon"
 	"digital-innovation/stratego/models"
+	"digital-innovation/stratego/stresstester"
 	"digital-innovation/stratego/utils"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strings"
 	"time"
 )
@@ -33,14 +39,27 @@ func main() {
 	serverMode := flag.Bool("server", false, "Run in WebSocket server mode")
 	defaultAddr := fmt.Sprintf(":%s", utils.GetEnv("PORT", "8080"))
 	addr := flag.String("addr", defaultAddr, "Server address")
+	spectatorDelay := flag.Duration("spectator-delay", 5*time.Second, "Delay of the broadcast to spectators of games with human players, against ghosting")
 	aiTypes := flag.String("ai", "fafo:fafo", "Run AI vs AI matches instead of server")
 	matches := flag.Int("matches", 100, "Number of AI vs AI matches to run")
-	format := flag.String("format", "none", "The format used to print the results of an AI vs AI competition, either none or md")
-	logging := flag.Bool("logging", true, "Show logs in stdout")
+	format := flag.String("format", "none", "The format of the results of an AI vs AI competition: none or md for a summary, json for a JSON summary, jsonl or csv for one record per game, samples or samples-bin for training samples of every move")
+	out := flag.String("out", "", "File to write the AI vs AI results to instead of stdout")
+	logging := flag.Bool("logging", true, "Show logs in stderr")
+	rules := flag.String("rules", models.RuleSetClassic, "Rule set of the AI vs AI games: "+strings.Join(models.RuleSetNames, ", "))
+	board := flag.String("board", "", "Board layout of the AI vs AI games, replacing the board of -rules: "+strings.Join(models.BoardLayoutNames, ", "))
+	seed := flag.Uint64("seed", 0, "Seed of the first AI vs AI game, game n uses seed+n-1; 0 picks a random seed")
+	workers := flag.Int("workers", 0, "Number of AI vs AI games played at the same time, 0 uses all CPU cores")
+	tournament := flag.String("tournament", "", "Run a round-robin tournament between a comma-separated list of AIs, playing -matches games per pairing")
+	stress := flag.Int("stress", 0, "Run a stress test with this many simulated clients, each playing the first AI of -ai")
+	stressMoves := flag.Int("stress-moves", 20, "Moves each stress test client makes before it leaves, 0 plays until the game is over")
+	stressURL := flag.String("stress-url", "", "Base URL of the server to stress test, e.g. http://localhost:8080; empty starts a server in this process")
+	exportGames := flag.Bool("export-games", false, "Export training samples of all finished games in the database to -out, as NDJSON or with -format samples-bin in the binary format")
+	importGravon := flag.String("import-gravon", "", "Import the Gravon XML games of a directory into the database, or with -format samples or samples-bin write their training samples to -out")
 
 	flag.Parse()
 
-	fmt.Println("=== Stratego Backend Running ===")
+	// Diagnostics go to stderr, stdout is kept for the results of the AI vs AI runs
+	fmt.Fprintln(os.Stderr, "=== Stratego Backend Running ===")
 
 	if *serverMode {
 		if err := db.InitDB(); err != nil {
@@ -54,7 +73,48 @@ func main() {
 
 		auth.Store.StartCleanupRoutine()
 
-		runServer(*addr) // websocket server
+		runServer(*addr, *spectatorDelay) // websocket server
+	} else if *stress > 0 {
+		options := stresstester.Options{
+			Clients: *stress,
+			Moves:   *stressMoves,
+			URL:     *stressURL,
+			AI:      strings.Split(*aiTypes, ":")[0],
+			Logging: *logging,
+		}
+		report, err := stresstester.Run(options)
+		if err != nil {
+			log.Fatalf("Stress test failed: %v", err)
+		}
+		stresstester.PrintReport(os.Stdout, report)
+	} else if *exportGames {
+		if err := aivsai.CheckFormat(*format, sampleFormats); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		if err := db.InitDB(); err != nil {
+			log.Fatalf("Failed to initialize database: %v", err)
+		}
+		defer func() {
+			if err := db.CloseDB(); err != nil {
+				log.Printf("Error closing database: %v", err)
+			}
+		}()
+
+		if err := exportStoredGames(*format, *out); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+	} else if *importGravon != "" {
+		if err := importGravonGames(*importGravon, *format, *out); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+	} else if *tournament != "" {
+		options := aivsai.Options{Matches: *matches, Seed: *seed, Workers: *workers, Format: *format, Out: *out, Logging: *logging, Rules: ruleSet(*rules, *board)}
+		start := time.Now()
+		if err := aivsai.RunTournament(strings.Split(*tournament, ","), options); err != nil {
+			log.Fatalf("Tournament failed: %v", err)
+		}
+		elapsed := time.Since(start)
+		fmt.Fprintf(os.Stderr, "\nTournament completed in %.2f seconds\n", elapsed.Seconds())
 	} else {
 		var ai1, ai2 string
 		if aiTypes == nil {
@@ -63,18 +123,124 @@ func main() {
 			aiTypeSplit := strings.Split(*aiTypes, ":")
 			ai1, ai2 = aiTypeSplit[0], aiTypeSplit[1]
 		}
+		options := aivsai.Options{Matches: *matches, Seed: *seed, Workers: *workers, Format: *format, Out: *out, Logging: *logging, Rules: ruleSet(*rules, *board)}
 		start := time.Now()
-		aivsai.RunAIvsAI(ai1, ai2, *matches, *format, *logging)
+		if err := aivsai.RunAIvsAI(ai1, ai2, options); err != nil {
+			log.Fatalf("AI vs AI failed: %v", err)
+		}
 		elapsed := time.Since(start)
-		fmt.Printf("\nAI vs AI matches completed in %.2f seconds\n", elapsed.Seconds())
+		fmt.Fprintf(os.Stderr, "\nAI vs AI matches completed in %.2f seconds\n", elapsed.Seconds())
+	}
+}
+
+// ruleSet returns the built-in rule set with the given name, played on the named board layout if it is not empty.
+// It exits on unknown names and on an army that does not fit the board.
+func ruleSet(name, board string) models.RuleSet {
+	rules, err := models.RuleSetByName(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if board != "" {
+		layout, err := models.BoardLayoutByName(board)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rules = rules.WithBoard(layout)
+		if err := rules.Validate(); err != nil {
+			log.Fatalf("rule set %s on the %s board: %v", name, board, err)
+		}
+	}
+	return rules
+}
+
+// sampleFormats are the formats -export-games and -import-gravon accept, see their flags
+var sampleFormats = []string{aivsai.FormatNone, aivsai.FormatSamples, aivsai.FormatSamplesBinary}
+
+// exportStoredGames writes the training samples of the stored games to the file at path, or stdout if path is empty
+func exportStoredGames(format, path string) error {
+	var out io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	writer := datagathering.NewNDJSONWriter(out)
+	if format == aivsai.FormatSamplesBinary {
+		writer = datagathering.NewBinaryWriter(out)
 	}
+	exported, err := datagathering.ExportStoredGames(writer)
+	if err != nil {
+		return err
+	}
+	log.Printf("Exported %d games", exported)
+	return nil
+}
+
+// importGravonGames replays the Gravon games of a directory and stores them in the database,
+// or writes their training samples to the file at path, or stdout if path is empty, for the samples formats
+func importGravonGames(dir, format, path string) error {
+	if err := aivsai.CheckFormat(format, sampleFormats); err != nil {
+		return err
+	}
+	store := gravon.StoreInDB
+	var writer datagathering.Writer
+	if format == aivsai.FormatSamples || format == aivsai.FormatSamplesBinary {
+		var out io.Writer = os.Stdout
+		if path != "" {
+			file, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		writer = datagathering.NewNDJSONWriter(out)
+		if format == aivsai.FormatSamplesBinary {
+			writer = datagathering.NewBinaryWriter(out)
+		}
+		store = func(_ string, g *game.Game) error {
+			return datagathering.WriteGame(writer, datagathering.FromGame(g))
+		}
+	} else {
+		if err := db.InitDB(); err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer func() {
+			if err := db.CloseDB(); err != nil {
+				log.Printf("Error closing database: %v", err)
+			}
+		}()
+	}
+
+	summary, err := gravon.ImportDir(dir, store)
+	if err != nil {
+		return err
+	}
+	if writer != nil {
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+	log.Printf("Imported %d of %d games from %d files", summary.Imported, summary.Games, summary.Files)
+	return nil
 }
 
 // runServer starts the WebSocket server
-func runServer(addr string) {
+func runServer(addr string, spectatorDelay time.Duration) {
 	fmt.Printf("Starting Stratego Game Server on %s\n", addr)
 
 	server := api.NewGameServer()
+	server.SetSpectatorDelay(spectatorDelay)
+	restored, err := server.RestoreGames()
+	if err != nil {
+		log.Printf("Failed to restore games: %v", err)
+	}
+	log.Printf("Restored %d in-progress games", restored)
+
 	if err := server.StartServer(addr); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
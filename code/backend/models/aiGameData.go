@@ -1,22 +1,73 @@
 package models
 
 type AiTournamentData struct {
-	Name                 string
-	Wins                 int
-	WinCauseFlagCaptured int
-	WinCauseNoMovesWin   int
-	WinCauseMaxTurns     int
+	Name                 string `json:"name"`
+	Wins                 int    `json:"wins"`
+	WinCauseFlagCaptured int    `json:"winCauseFlagCaptured"`
+	WinCauseNoMovesWin   int    `json:"winCauseNoMovesWin"`
+	WinCauseMaxTurns     int    `json:"winCauseMaxTurns"`
 }
 
 type GameSummary struct {
-	Player1data          AiTournamentData
-	Player2data          AiTournamentData
-	Draws                int
-	TotalRounds          int
-	AverageRounds        float64
-	LeastRounds          int
-	Matches              int
-	WinCauseFlagCaptured int
-	WinCauseNoMovesWins  int
-	WinCauseMaxTurns     int
+	Player1data          AiTournamentData `json:"player1"`
+	Player2data          AiTournamentData `json:"player2"`
+	Draws                int              `json:"draws"`
+	TotalRounds          int              `json:"totalRounds"`
+	AverageRounds        float64          `json:"averageRounds"`
+	LeastRounds          int              `json:"leastRounds"`
+	Matches              int              `json:"matches"`
+	Seed                 uint64           `json:"seed"` // seed of the first game, game i is played with Seed+i
+	WinCauseFlagCaptured int              `json:"winCauseFlagCaptured"`
+	WinCauseNoMovesWins  int              `json:"winCauseNoMovesWins"`
+	WinCauseMaxTurns     int              `json:"winCauseMaxTurns"`
+}
+
+// AiGameRecord is the full record of a single AI vs AI game, written one per line in the jsonl output.
+// Player 1 has ID 0 and player 2 ID 1 in the history.
+type AiGameRecord struct {
+	Game         int              `json:"game"` // 1-based index of the game in the run
+	Seed         uint64           `json:"seed"`
+	Rules        RuleSet          `json:"rules"` // rule set of the game, needed to replay its combats
+	Player1      string           `json:"player1"`
+	Player2      string           `json:"player2"`
+	FirstPlayer  string           `json:"firstPlayer"`
+	Winner       string           `json:"winner,omitempty"`   // empty for a draw
+	WinnerID     *int             `json:"winnerId,omitempty"` // 0 for player 1, 1 for player 2, nil for a draw
+	WinCause     string           `json:"winCause,omitempty"`
+	Rounds       int              `json:"rounds"`
+	Player1Score int              `json:"player1Score"`
+	Player2Score int              `json:"player2Score"`
+	InitialState [][]PieceData    `json:"initialState"`
+	History      []HistoricalMove `json:"history"`
+}
+
+// PairingResult holds the results of all games between two agents of a tournament.
+type PairingResult struct {
+	Agent1 string `json:"agent1"`
+	Agent2 string `json:"agent2"`
+	Wins1  int    `json:"wins1"`
+	Wins2  int    `json:"wins2"`
+	Draws  int    `json:"draws"`
+}
+
+// AgentStanding is the overall result of an agent in a tournament.
+// Score is the share of points with a draw counting half, Elo the estimated rating with its 95% confidence interval.
+type AgentStanding struct {
+	Name    string  `json:"name"`
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	Score   float64 `json:"score"`
+	Elo     float64 `json:"elo"`
+	EloLow  float64 `json:"eloLow"`
+	EloHigh float64 `json:"eloHigh"`
+}
+
+type TournamentSummary struct {
+	Agents            []string        `json:"agents"`
+	MatchesPerPairing int             `json:"matchesPerPairing"`
+	Seed              uint64          `json:"seed"` // seed of the first game, game k is played with Seed+k
+	Pairings          []PairingResult `json:"pairings"`
+	Standings         []AgentStanding `json:"standings"` // sorted by Elo, best first
 }
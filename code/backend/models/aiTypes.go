@@ -1,6 +1,12 @@
 package models
 
 const (
-	Fafo = "fafo"
-	Fato = "fato"
+	Fafo      = "fafo"
+	Fato      = "fato"
+	Heuristic = "heuristic"
+	Minimax   = "minimax"
+	Mcts      = "mcts"
 )
+
+// AINames are the names of the built-in AIs
+var AINames = []string{Fafo, Fato, Heuristic, Minimax, Mcts}
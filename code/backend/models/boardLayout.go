@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the built-in board layouts
+const (
+	BoardLayoutClassic = "classic"
+	BoardLayoutSmall   = "small"
+	BoardLayoutCustom  = "custom"
+)
+
+// Limits of the board size. Compact boards and the AI memory index squares as y*MaxBoardSize+x.
+const (
+	MinBoardSize = 4
+	MaxBoardSize = 10
+)
+
+// Square is a position on the board, (0,0) is the top left corner
+type Square struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// BoardLayout describes the board a game is played on: its size and the squares covered by lakes
+type BoardLayout struct {
+	Name   string   `json:"name"`
+	Width  int      `json:"width"`
+	Height int      `json:"height"`
+	Lakes  []Square `json:"lakes"`
+}
+
+// ClassicBoard returns the 10x10 board with two 2x2 lakes in the middle rows
+func ClassicBoard() BoardLayout {
+	return BoardLayout{
+		Name:   BoardLayoutClassic,
+		Width:  10,
+		Height: 10,
+		Lakes: []Square{
+			{X: 2, Y: 4}, {X: 3, Y: 4}, {X: 2, Y: 5}, {X: 3, Y: 5},
+			{X: 6, Y: 4}, {X: 7, Y: 4}, {X: 6, Y: 5}, {X: 7, Y: 5},
+		},
+	}
+}
+
+// SmallBoard returns an 8x8 board with two 1x2 lakes in the middle rows
+func SmallBoard() BoardLayout {
+	return BoardLayout{
+		Name:   BoardLayoutSmall,
+		Width:  8,
+		Height: 8,
+		Lakes: []Square{
+			{X: 2, Y: 3}, {X: 2, Y: 4},
+			{X: 5, Y: 3}, {X: 5, Y: 4},
+		},
+	}
+}
+
+// BoardLayoutNames are the names of the built-in board layouts
+var BoardLayoutNames = []string{BoardLayoutClassic, BoardLayoutSmall}
+
+// BoardLayoutByName returns a built-in board layout, an empty name gives the classic board
+func BoardLayoutByName(name string) (BoardLayout, error) {
+	switch name {
+	case "", BoardLayoutClassic:
+		return ClassicBoard(), nil
+	case BoardLayoutSmall:
+		return SmallBoard(), nil
+	default:
+		return BoardLayout{}, fmt.Errorf("unknown board layout %q, expected one of: %s", name, strings.Join(BoardLayoutNames, ", "))
+	}
+}
+
+// MaxSetupRows returns the number of rows each player can set up in, leaving at least one row between the armies
+func (l BoardLayout) MaxSetupRows() int {
+	return (l.Height - 1) / 2
+}
+
+// Validate checks the board size and that every lake lies on the board once
+func (l BoardLayout) Validate() error {
+	if l.Width < MinBoardSize || l.Width > MaxBoardSize || l.Height < MinBoardSize || l.Height > MaxBoardSize {
+		return fmt.Errorf("board must be between %dx%d and %dx%d squares, got %dx%d",
+			MinBoardSize, MinBoardSize, MaxBoardSize, MaxBoardSize, l.Width, l.Height)
+	}
+	seen := make(map[Square]bool, len(l.Lakes))
+	for _, lake := range l.Lakes {
+		if lake.X < 0 || lake.X >= l.Width || lake.Y < 0 || lake.Y >= l.Height {
+			return fmt.Errorf("lake (%d,%d) is not on the board", lake.X, lake.Y)
+		}
+		if seen[lake] {
+			return fmt.Errorf("lake (%d,%d) is listed twice", lake.X, lake.Y)
+		}
+		seen[lake] = true
+	}
+	return nil
+}
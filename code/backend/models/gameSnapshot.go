@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// GameSnapshot is the state of an in-progress game, stored after every move so the game can be resumed after a restart.
+// The memory of AI players is not stored: replaying the moves lets the AIs observe them again, which rebuilds it.
+type GameSnapshot struct {
+	GameID          string           `json:"gameId"`
+	GameType        string           `json:"gameType"`
+	Rules           RuleSet          `json:"rules"`
+	AI1             string           `json:"ai1,omitempty"` // AIs the session was created with, see GameServer.CreateGame
+	AI2             string           `json:"ai2,omitempty"`
+	Player1UserID   *int             `json:"player1UserId,omitempty"`
+	Player2UserID   *int             `json:"player2UserId,omitempty"`
+	InitialState    [][]PieceData    `json:"initialState"`
+	Moves           []HistoricalMove `json:"moves"`
+	Board           string           `json:"board"` // base64 in the cell layout of engine.EncodeBoardToBase64, player 1 has the color bit
+	CurrentPlayerID int              `json:"currentPlayerId"`
+	Round           int              `json:"round"`
+	Revealed        []int            `json:"revealed"` // squares (y*10+x) of the pieces revealed in the current round
+	StartTime       time.Time        `json:"startTime"`
+}
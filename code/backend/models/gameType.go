@@ -4,4 +4,5 @@ const (
 	HumanVsAi    = "human_vs_ai"
 	HumanVsHuman = "human_vs_human"
 	AiVsAi       = "ai_vs_ai"
+	Imported     = "imported" // uploaded game record, see game.ImportRecord
 )
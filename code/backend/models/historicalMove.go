@@ -11,6 +11,16 @@ type HistoricalMove struct {
 	Attacker  *PieceData     `json:"attacker,omitempty"`
 	Defender  *PieceData     `json:"defender,omitempty"`
 	Result    MoveResultType `json:"result"`
+
+	DefenderHidden bool    `json:"defenderHidden,omitempty"` // silent defense: the defender won without being revealed to the attacker
+	Rescue         *Rescue `json:"rescue,omitempty"`         // piece brought back by the rescue rule after the move
+}
+
+// Rescue is a captured piece brought back into the game by the rescue rule and the square it was placed on
+type Rescue struct {
+	Piece PieceData `json:"piece"`
+	X     int       `json:"x"`
+	Y     int       `json:"y"`
 }
 
 type PieceData struct {
@@ -32,7 +42,8 @@ const (
 // GameHistory represents the full history of a game
 type GameHistory struct {
 	GameID       string           `json:"gameId"`
-	InitialState interface{}      `json:"initialState"` // Will be JSONB/Array
+	Rules        RuleSet          `json:"rules"`
+	InitialState [][]PieceData    `json:"initialState"`
 	Moves        []HistoricalMove `json:"moves"`
 	WinnerID     *int             `json:"winnerId"`
 }
@@ -12,3 +12,6 @@ var Major PieceType = *NewPieceType("Major", '7', true, "The piece that can move
 var Colonel PieceType = *NewPieceType("Colonel", '8', true, "The piece that can move and attack but is weak.", "👮‍♀️", 2, 8)
 var General PieceType = *NewPieceType("General", '9', true, "The piece that can move and attack but is weak.", "👮‍♂️", 1, 9)
 var Marshal PieceType = *NewPieceType("Marshal", 'M', true, "The piece that can move and attack but is weak.", "👮‍♀️", 1, 10)
+
+// PieceTypes are all piece types, from the flag to the marshal
+var PieceTypes = []PieceType{Flag, Bomb, Spy, Scout, Miner, Sergeant, Lieutenant, Captain, Major, Colonel, General, Marshal}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Kinds of rated players
+const (
+	RatedUser = "user"
+	RatedAI   = "ai"
+)
+
+// RatedPlayer identifies a rated player, either a user or an AI
+type RatedPlayer struct {
+	UserID *int   // nil for an AI
+	AIName string // empty for a user
+}
+
+// Rating is the current rating of a user or an AI
+type Rating struct {
+	Rank        int       `json:"rank,omitempty"` // place on the leaderboard, starting at 1
+	PlayerType  string    `json:"player_type"`    // RatedUser or RatedAI
+	UserID      *int      `json:"user_id,omitempty"`
+	Name        string    `json:"name"` // username or AI name
+	Rating      float64   `json:"rating"`
+	Deviation   float64   `json:"deviation"` // uncertainty of the rating, lower after more games
+	GamesPlayed int       `json:"games_played"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RatingChange is the change of a rating by one game
+type RatingChange struct {
+	GameID          string    `json:"game_id"`
+	OpponentName    string    `json:"opponent_name"` // username or AI name
+	Result          string    `json:"result"`        // GameResultWin, GameResultLoss or GameResultDraw
+	RatingBefore    float64   `json:"rating_before"`
+	RatingAfter     float64   `json:"rating_after"`
+	DeviationBefore float64   `json:"deviation_before"`
+	DeviationAfter  float64   `json:"deviation_after"`
+	CreatedAt       time.Time `json:"created_at"`
+}
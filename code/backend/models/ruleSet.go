@@ -0,0 +1,210 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Names of the built-in rule sets
+const (
+	RuleSetClassic   = "classic"
+	RuleSetBarrage   = "barrage"
+	RuleSetDuel      = "duel"
+	RuleSetDuelSmall = "duel-small"
+	RuleSetCustom    = "custom" // a built-in rule set with a changed army, board, setup rows or special rules
+)
+
+// MaxSetupRows is the number of rows in front of each player on the classic board, up to the lakes
+const MaxSetupRows = 4
+
+// RuleSet describes a variant of the game: the board, the army of each player, the rows it is set up in and the special rules
+type RuleSet struct {
+	Name            string         `json:"name"`
+	Board           BoardLayout    `json:"board"`
+	Army            map[string]int `json:"army"`            // pieces per rank, e.g. "B" for bombs, ranks not listed are not in the army
+	SetupRows       int            `json:"setupRows"`       // rows at each player's side of the board the army is set up in
+	RepetitionRules bool           `json:"repetitionRules"` // enforce the two-square and more-squares rules
+
+	// Special rules of house and tournament variants, all off in the built-in rule sets
+	SilentDefense      bool `json:"silentDefense"`      // a defender that wins stays hidden, only the attacker's rank is revealed
+	AggressorAdvantage bool `json:"aggressorAdvantage"` // the attacker wins against a piece of equal rank instead of both being lost
+	Rescue             bool `json:"rescue"`             // a piece reaching the enemy's back row brings back the strongest captured piece, once
+}
+
+// ClassicRules returns the rules of the classic game with 40 pieces per player
+func ClassicRules() RuleSet {
+	army := make(map[string]int, len(PieceTypes))
+	for _, pieceType := range PieceTypes {
+		army[string(pieceType.GetRank())] = pieceType.GetCount()
+	}
+	return RuleSet{Name: RuleSetClassic, Board: ClassicBoard(), Army: army, SetupRows: MaxSetupRows, RepetitionRules: true}
+}
+
+// BarrageRules returns the rules of Barrage, a quick game with 8 pieces per player set up anywhere in the usual 4 rows
+func BarrageRules() RuleSet {
+	return RuleSet{
+		Name:  RuleSetBarrage,
+		Board: ClassicBoard(),
+		Army: map[string]int{
+			string(Flag.GetRank()): 1, string(Bomb.GetRank()): 1, string(Spy.GetRank()): 1, string(Scout.GetRank()): 2,
+			string(Miner.GetRank()): 1, string(General.GetRank()): 1, string(Marshal.GetRank()): 1,
+		},
+		SetupRows:       MaxSetupRows,
+		RepetitionRules: true,
+	}
+}
+
+// DuelRules returns the rules of Duel, a shorter game with 20 pieces per player set up in the 2 back rows
+func DuelRules() RuleSet {
+	return RuleSet{
+		Name:  RuleSetDuel,
+		Board: ClassicBoard(),
+		Army: map[string]int{
+			string(Flag.GetRank()): 1, string(Bomb.GetRank()): 3, string(Spy.GetRank()): 1, string(Scout.GetRank()): 4,
+			string(Miner.GetRank()): 3, string(Sergeant.GetRank()): 2, string(Lieutenant.GetRank()): 1,
+			string(Captain.GetRank()): 1, string(Major.GetRank()): 1, string(Colonel.GetRank()): 1,
+			string(General.GetRank()): 1, string(Marshal.GetRank()): 1,
+		},
+		SetupRows:       2,
+		RepetitionRules: true,
+	}
+}
+
+// SmallDuelRules returns the rules of Duel on the small 8x8 board, with 16 pieces per player set up in the 2 back rows.
+// The army of Duel does not fit into 2 rows of 8 squares, so it has one bomb, scout, miner and colonel less.
+func SmallDuelRules() RuleSet {
+	return RuleSet{
+		Name:  RuleSetDuelSmall,
+		Board: SmallBoard(),
+		Army: map[string]int{
+			string(Flag.GetRank()): 1, string(Bomb.GetRank()): 2, string(Spy.GetRank()): 1, string(Scout.GetRank()): 3,
+			string(Miner.GetRank()): 2, string(Sergeant.GetRank()): 2, string(Lieutenant.GetRank()): 1,
+			string(Captain.GetRank()): 1, string(Major.GetRank()): 1, string(General.GetRank()): 1,
+			string(Marshal.GetRank()): 1,
+		},
+		SetupRows:       2,
+		RepetitionRules: true,
+	}
+}
+
+// RuleSetNames are the names of the built-in rule sets
+var RuleSetNames = []string{RuleSetClassic, RuleSetBarrage, RuleSetDuel, RuleSetDuelSmall}
+
+// RuleSetByName returns a built-in rule set, an empty name gives the classic rules
+func RuleSetByName(name string) (RuleSet, error) {
+	switch name {
+	case "", RuleSetClassic:
+		return ClassicRules(), nil
+	case RuleSetBarrage:
+		return BarrageRules(), nil
+	case RuleSetDuel:
+		return DuelRules(), nil
+	case RuleSetDuelSmall:
+		return SmallDuelRules(), nil
+	default:
+		return RuleSet{}, fmt.Errorf("unknown rule set %q, expected one of: %s", name, strings.Join(RuleSetNames, ", "))
+	}
+}
+
+// Count returns the number of pieces of a type in the army
+func (r RuleSet) Count(pieceType PieceType) int {
+	return r.Army[string(pieceType.GetRank())]
+}
+
+// ArmySize returns the number of pieces in the army
+func (r RuleSet) ArmySize() int {
+	size := 0
+	for _, count := range r.Army {
+		size += count
+	}
+	return size
+}
+
+// Layout returns the board of the rule set. Rule sets stored before boards could be changed have none
+// and are played on the classic board.
+func (r RuleSet) Layout() BoardLayout {
+	if r.Board.Width == 0 && r.Board.Height == 0 {
+		return ClassicBoard()
+	}
+	return r.Board
+}
+
+// WithBoard returns the rule set played on another board, as a custom rule set.
+// The setup rows are reduced to the most the board allows, the army is kept.
+func (r RuleSet) WithBoard(layout BoardLayout) RuleSet {
+	r.Name = RuleSetCustom
+	r.Board = layout
+	r.SetupRows = min(r.SetupRows, layout.MaxSetupRows())
+	return r
+}
+
+// SetupCells returns the number of squares each player sets up their army in
+func (r RuleSet) SetupCells() int {
+	return r.SetupRows * r.Layout().Width
+}
+
+// Validate checks the board, that the army is playable and that it fits into the setup rows.
+// The setup rows of the two players must be separated by at least one row and must not contain lakes.
+func (r RuleSet) Validate() error {
+	layout := r.Layout()
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+	if maxRows := layout.MaxSetupRows(); r.SetupRows < 1 || r.SetupRows > maxRows {
+		return fmt.Errorf("setup rows must be between 1 and %d, got %d", maxRows, r.SetupRows)
+	}
+	for _, lake := range layout.Lakes {
+		if lake.Y < r.SetupRows || lake.Y >= layout.Height-r.SetupRows {
+			return fmt.Errorf("lake (%d,%d) lies in the setup rows", lake.X, lake.Y)
+		}
+	}
+
+	movable := 0
+	for rank, count := range r.Army {
+		pieceType := pieceTypeOfRank(rank)
+		if pieceType == nil {
+			return fmt.Errorf("unknown rank %q in the army", rank)
+		}
+		if count < 0 {
+			return fmt.Errorf("negative count of %s in the army", pieceType.GetName())
+		}
+		if pieceType.IsMovable() {
+			movable += count
+		}
+	}
+	if r.Count(Flag) != 1 {
+		return fmt.Errorf("the army must have exactly 1 flag, got %d", r.Count(Flag))
+	}
+	if movable == 0 {
+		return errors.New("the army must have a movable piece")
+	}
+	if size := r.ArmySize(); size > r.SetupCells() {
+		return fmt.Errorf("an army of %d pieces does not fit into %d setup rows", size, r.SetupRows)
+	}
+	return nil
+}
+
+// ValidateArmy checks that the counted pieces of a setup, by rank, are exactly the army
+func (r RuleSet) ValidateArmy(counts map[byte]int) error {
+	for _, pieceType := range PieceTypes {
+		if got, expected := counts[pieceType.GetRank()], r.Count(pieceType); got != expected {
+			return fmt.Errorf("piece %c: expected %d, got %d", pieceType.GetRank(), expected, got)
+		}
+	}
+	for rank, count := range counts {
+		if count > 0 && pieceTypeOfRank(string(rank)) == nil {
+			return fmt.Errorf("unknown piece %c", rank)
+		}
+	}
+	return nil
+}
+
+func pieceTypeOfRank(rank string) *PieceType {
+	for i := range PieceTypes {
+		if string(PieceTypes[i].GetRank()) == rank {
+			return &PieceTypes[i]
+		}
+	}
+	return nil
+}
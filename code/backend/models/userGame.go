@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Results of a game from the point of view of a player
+const (
+	GameResultWin  = "win"
+	GameResultLoss = "loss"
+	GameResultDraw = "draw"
+)
+
+// GameResultFor returns the result of a game for the player in a seat, winnerID is nil for a draw
+func GameResultFor(seat int, winnerID *int) string {
+	switch {
+	case winnerID == nil:
+		return GameResultDraw
+	case *winnerID == seat:
+		return GameResultWin
+	default:
+		return GameResultLoss
+	}
+}
+
+// UserGame is a finished game in the game list of a user
+type UserGame struct {
+	GameID       string    `json:"gameId"`
+	GameType     string    `json:"gameType"`
+	PlayerID     int       `json:"playerId"`               // seat of the user, 0 or 1
+	OpponentID   *int      `json:"opponentId,omitempty"`   // nil for AIs and guests
+	OpponentName string    `json:"opponentName,omitempty"` // username of the opponent
+	WinnerID     *int      `json:"winnerId"`               // 0 or 1, nil for a draw
+	Result       string    `json:"result"`                 // GameResultWin, GameResultLoss or GameResultDraw
+	MoveCount    int       `json:"moveCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+	FinishedAt   time.Time `json:"finishedAt"`
+}
+
+// UserGamesFilter selects a page of the games of a user, empty fields match all games
+type UserGamesFilter struct {
+	GameType string
+	Result   string     // GameResultWin, GameResultLoss or GameResultDraw
+	From     *time.Time // finished at or after
+	To       *time.Time // finished before
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,63 @@
+// Package rating implements the Glicko rating system, updated after every game.
+//
+// A rating comes with a deviation that measures how uncertain it is: new players start at a high deviation,
+// so their first games move their rating a lot, and it shrinks with every game played.
+// See http://www.glicko.net/glicko/glicko.pdf
+package rating
+
+import "math"
+
+const (
+	DefaultRating    = 1500.0 // rating of a player without games
+	DefaultDeviation = 350.0  // deviation of a player without games
+	MinDeviation     = 30.0   // lower bound of the deviation, so ratings keep following changes in strength
+)
+
+// q is the scale factor between the Elo scale and the natural logarithm
+var q = math.Ln10 / 400
+
+// Rating is the strength of a player
+type Rating struct {
+	Value     float64
+	Deviation float64
+}
+
+// New returns the rating of a player without games
+func New() Rating {
+	return Rating{Value: DefaultRating, Deviation: DefaultDeviation}
+}
+
+// Score of a game for the first player
+const (
+	Loss = 0.0
+	Draw = 0.5
+	Win  = 1.0
+)
+
+// Expected returns the expected score of a against b, between 0 and 1
+func Expected(a, b Rating) float64 {
+	return 1 / (1 + math.Pow(10, -g(b.Deviation)*(a.Value-b.Value)/400))
+}
+
+// Update returns the ratings of two players after a game between them with the given score for a
+func Update(a, b Rating, score float64) (Rating, Rating) {
+	return update(a, b, score), update(b, a, 1-score)
+}
+
+// update returns the rating of a player after a game against an opponent
+func update(player, opponent Rating, score float64) Rating {
+	gOpponent := g(opponent.Deviation)
+	expected := Expected(player, opponent)
+	dSquaredInverse := q * q * gOpponent * gOpponent * expected * (1 - expected)
+	precision := 1/(player.Deviation*player.Deviation) + dSquaredInverse
+
+	return Rating{
+		Value:     player.Value + q/precision*gOpponent*(score-expected),
+		Deviation: math.Max(math.Sqrt(1/precision), MinDeviation),
+	}
+}
+
+// g reduces the impact of a game against an opponent with an uncertain rating
+func g(deviation float64) float64 {
+	return 1 / math.Sqrt(1+3*q*q*deviation*deviation/(math.Pi*math.Pi))
+}
@@ -0,0 +1,58 @@
+package rating_test
+
+import (
+	"digital-innovation/stratego/rating"
+	"math"
+	"testing"
+)
+
+func TestUpdateBetweenEqualPlayers(t *testing.T) {
+	winner, loser := rating.Update(rating.New(), rating.New(), rating.Win)
+
+	if winner.Value <= rating.DefaultRating || loser.Value >= rating.DefaultRating {
+		t.Errorf("Expected the winner to gain and the loser to lose rating, got: %v, %v", winner.Value, loser.Value)
+	}
+	if gain, loss := winner.Value-rating.DefaultRating, rating.DefaultRating-loser.Value; math.Abs(gain-loss) > 1e-9 {
+		t.Errorf("Expected equal players to exchange the same amount, got +%v and -%v", gain, loss)
+	}
+	if winner.Deviation >= rating.DefaultDeviation || loser.Deviation >= rating.DefaultDeviation {
+		t.Errorf("Expected a game to lower the deviation of both players, got: %v, %v", winner.Deviation, loser.Deviation)
+	}
+
+	a, b := rating.Update(rating.New(), rating.New(), rating.Draw)
+	if a.Value != rating.DefaultRating || b.Value != rating.DefaultRating {
+		t.Errorf("Expected a draw between equal players to keep their ratings, got: %v, %v", a.Value, b.Value)
+	}
+}
+
+func TestUpdateRewardsUpsets(t *testing.T) {
+	strong := rating.Rating{Value: 1900, Deviation: 50}
+	weak := rating.Rating{Value: 1500, Deviation: 50}
+
+	if expected := rating.Expected(strong, weak); expected < 0.85 || expected > 0.95 {
+		t.Errorf("Expected a 400 point favourite to score about 0.9, got: %v", expected)
+	}
+
+	upsetWinner, _ := rating.Update(weak, strong, rating.Win)
+	expectedWinner, _ := rating.Update(weak, weak, rating.Win)
+	if upsetWinner.Value-weak.Value <= expectedWinner.Value-weak.Value {
+		t.Errorf("Expected beating a stronger player to gain more, got +%v against +%v",
+			upsetWinner.Value-weak.Value, expectedWinner.Value-weak.Value)
+	}
+
+	// A draw against a weaker player costs the stronger one
+	strongAfter, weakAfter := rating.Update(strong, weak, rating.Draw)
+	if strongAfter.Value >= strong.Value || weakAfter.Value <= weak.Value {
+		t.Errorf("Expected a draw to move both ratings towards each other, got: %v, %v", strongAfter.Value, weakAfter.Value)
+	}
+}
+
+func TestUpdateKeepsMinimumDeviation(t *testing.T) {
+	r := rating.Rating{Value: 1500, Deviation: rating.MinDeviation}
+	for range 100 {
+		r, _ = rating.Update(r, rating.Rating{Value: 1500, Deviation: rating.MinDeviation}, rating.Draw)
+	}
+	if r.Deviation != rating.MinDeviation {
+		t.Errorf("Expected the deviation to stay at %v, got: %v", rating.MinDeviation, r.Deviation)
+	}
+}
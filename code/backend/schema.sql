@@ -72,6 +72,9 @@ CREATE TABLE IF NOT EXISTS games (
   finished_at TIMESTAMPTZ -- NULL if abandoned/in progress
 );
 
+-- Rule set of the game as JSON, see models.RuleSet; NULL for classic games stored before rule sets existed
+ALTER TABLE games ADD COLUMN IF NOT EXISTS rules JSONB;
+
 CREATE TABLE IF NOT EXISTS game_moves (
   id SERIAL PRIMARY KEY,
   game_id VARCHAR(100) REFERENCES games(id) ON DELETE CASCADE,
@@ -87,6 +90,52 @@ CREATE TABLE IF NOT EXISTS game_moves (
   created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- Special rules, see models.HistoricalMove: a defender that won under silent defense and the piece brought back by a rescue
+ALTER TABLE game_moves ADD COLUMN IF NOT EXISTS defender_hidden BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE game_moves ADD COLUMN IF NOT EXISTS rescue_data JSONB;
+
+CREATE TABLE IF NOT EXISTS game_snapshots (
+  game_id VARCHAR(100) PRIMARY KEY REFERENCES games(id) ON DELETE CASCADE,
+  ai1 VARCHAR(50), -- AIs the session was created with
+  ai2 VARCHAR(50),
+  board VARCHAR(200) NOT NULL, -- base64 encoded board after the last move
+  current_player_id INTEGER NOT NULL,
+  round INTEGER NOT NULL,
+  revealed JSONB NOT NULL, -- squares of the pieces revealed in the current round
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 CREATE INDEX idx_game_moves_game_id ON game_moves(game_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_game_moves_game_id_move_index ON game_moves(game_id, move_index);
 CREATE INDEX idx_games_player1_id ON games(player1_user_id);
 CREATE INDEX idx_games_player2_id ON games(player2_user_id);
+
+-- Ratings of users and AIs, see the rating package
+CREATE TABLE IF NOT EXISTS ratings (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  ai_name VARCHAR(50) UNIQUE,
+  rating DOUBLE PRECISION NOT NULL,
+  deviation DOUBLE PRECISION NOT NULL,
+  games_played INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK ((user_id IS NULL) <> (ai_name IS NULL)) -- either a user or an AI
+);
+
+CREATE TABLE IF NOT EXISTS rating_history (
+  id SERIAL PRIMARY KEY,
+  rating_id INTEGER NOT NULL REFERENCES ratings(id) ON DELETE CASCADE,
+  opponent_rating_id INTEGER REFERENCES ratings(id) ON DELETE SET NULL,
+  game_id VARCHAR(100) NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+  result VARCHAR(10) NOT NULL, -- win, loss or draw
+  rating_before DOUBLE PRECISION NOT NULL,
+  rating_after DOUBLE PRECISION NOT NULL,
+  deviation_before DOUBLE PRECISION NOT NULL,
+  deviation_after DOUBLE PRECISION NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (rating_id, game_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ratings_rating ON ratings(rating DESC);
+CREATE INDEX IF NOT EXISTS idx_rating_history_rating_id ON rating_history(rating_id, created_at DESC);
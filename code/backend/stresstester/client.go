@@ -0,0 +1,269 @@
+package stresstester
+
+import (
+	"bytes"
+	"digital-innovation/stratego/api"
+	"digital-innovation/stratego/models"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Kinds of requests whose latency is measured
+const (
+	LatencyCreateGame = "createGame" // POST /games
+	LatencyConnect    = "connect"    // WebSocket handshake
+	LatencyValidMoves = "validMoves" // getValidMoves until validMoves
+	LatencyMove       = "move"       // move until moveResult
+)
+
+// maxMoveAttempts is how often a client tries other pieces before waiting for the next update of the board.
+const maxMoveAttempts = 10
+
+// incoming is a message from the server with its data left encoded.
+type incoming struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// client is one simulated player: it creates a human vs AI game, sets it up and plays random legal moves.
+type client struct {
+	id        int
+	options   Options
+	http      *http.Client
+	latencies *latencies
+	sent      *atomic.Int64
+	received  *atomic.Int64
+	rng       *rand.Rand
+
+	conn     *websocket.Conn
+	state    api.GameStateMessage
+	board    [][]api.PieceDTO
+	lakes    map[api.PositionDTO]bool
+	pending  string // request waiting for its response, empty if none
+	sentAt   time.Time
+	from     api.PositionDTO
+	attempts int
+	moves    int
+}
+
+// run plays one game until it is over or the client made options.Moves moves.
+func (c *client) run() error {
+	gameID, err := c.createGame()
+	if err != nil {
+		return err
+	}
+	if err := c.connect(gameID); err != nil {
+		return err
+	}
+	defer c.conn.Close()
+
+	if err := c.send(api.MsgTypeRandomizeSetup, nil); err != nil {
+		return err
+	}
+	if err := c.send(api.MsgTypeStartGame, api.StartGameMessage{}); err != nil {
+		return err
+	}
+	// Starting resets the turn delay of the AI, so the speed is set afterwards
+	if err := c.send(api.MsgTypeSetSpeed, api.SetSpeedMessage{SpeedMs: c.options.SpeedMs}); err != nil {
+		return err
+	}
+
+	for {
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.options.IdleTimeout)); err != nil {
+			return err
+		}
+		var msg incoming
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		c.received.Add(1)
+
+		done, err := c.handle(msg)
+		if err != nil {
+			return err
+		}
+		if done {
+			return c.close()
+		}
+	}
+}
+
+func (c *client) createGame() (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"gameId":   fmt.Sprintf("stress-%d-%d", time.Now().UnixNano(), c.id),
+		"gameType": models.HumanVsAi,
+		"ai1":      c.options.AI,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	resp, err := c.http.Post(c.options.URL+"/games", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create game: %w", err)
+	}
+	defer resp.Body.Close()
+	c.latencies.record(LatencyCreateGame, time.Since(start))
+
+	var created struct {
+		GameID string `json:"gameId"`
+		Error  string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("create game: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("create game: %s (%d)", created.Error, resp.StatusCode)
+	}
+	return created.GameID, nil
+}
+
+func (c *client) connect(gameID string) error {
+	url := "ws" + strings.TrimPrefix(c.options.URL, "http") + "/game/" + gameID + "?player=0"
+	start := time.Now()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	c.latencies.record(LatencyConnect, time.Since(start))
+	c.conn = conn
+	return nil
+}
+
+// close ends the connection the way a browser does when the player leaves.
+func (c *client) close() error {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	return c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+}
+
+func (c *client) send(msgType string, data any) error {
+	if err := c.conn.WriteJSON(api.WSMessage{Type: msgType, Data: data}); err != nil {
+		return fmt.Errorf("write %s: %w", msgType, err)
+	}
+	c.sent.Add(1)
+	return nil
+}
+
+// request sends a message and remembers it, so the latency can be measured when the response arrives.
+func (c *client) request(msgType string, data any) error {
+	c.pending = msgType
+	c.sentAt = time.Now()
+	return c.send(msgType, data)
+}
+
+// handle processes a message from the server and reports whether the client is done.
+func (c *client) handle(msg incoming) (bool, error) {
+	switch msg.Type {
+	case api.MsgTypeGameState:
+		if err := json.Unmarshal(msg.Data, &c.state); err != nil {
+			return false, err
+		}
+		if c.state.IsGameOver {
+			return true, nil
+		}
+		c.attempts = 0
+		return false, c.tryMove()
+
+	case api.MsgTypeBoardState:
+		var board api.BoardStateMessage
+		if err := json.Unmarshal(msg.Data, &board); err != nil {
+			return false, err
+		}
+		c.board = board.Board
+		c.lakes = make(map[api.PositionDTO]bool, len(board.Lakes))
+		for _, lake := range board.Lakes {
+			c.lakes[lake] = true
+		}
+		c.attempts = 0
+		return false, c.tryMove()
+
+	case api.MsgTypeCombat:
+		// The server waits for the animation before it continues
+		return false, c.send(api.MsgTypeAnimationComplete, nil)
+
+	case api.MsgTypeValidMoves:
+		var valid api.ValidMovesMessage
+		if err := json.Unmarshal(msg.Data, &valid); err != nil {
+			return false, err
+		}
+		c.latencies.record(LatencyValidMoves, time.Since(c.sentAt))
+		c.pending = ""
+		if len(valid.ValidMoves) == 0 {
+			return false, c.tryMove()
+		}
+		to := valid.ValidMoves[c.rng.IntN(len(valid.ValidMoves))]
+		return false, c.request(api.MsgTypeMove, api.MoveMessage{From: c.from, To: to})
+
+	case api.MsgTypeMoveResult:
+		var result api.MoveResultMessage
+		if err := json.Unmarshal(msg.Data, &result); err != nil {
+			return false, err
+		}
+		c.latencies.record(LatencyMove, time.Since(c.sentAt))
+		c.pending = ""
+		if !result.Success {
+			return false, c.tryMove()
+		}
+		c.moves++
+		return c.options.Moves > 0 && c.moves >= c.options.Moves, nil
+
+	case api.MsgTypeError:
+		// e.g. valid moves asked for with an outdated board
+		c.pending = ""
+		return false, c.tryMove()
+
+	case api.MsgTypeGameOver:
+		return true, nil
+	}
+	return false, nil
+}
+
+// tryMove asks for the valid moves of a random own piece that looks movable on the last known board,
+// if it is the client's turn and no request is pending.
+func (c *client) tryMove() error {
+	if c.pending != "" || c.board == nil || c.state.IsSetupPhase || c.state.IsGameOver || c.state.CurrentPlayerID != 0 {
+		return nil
+	}
+	if c.attempts >= maxMoveAttempts {
+		return nil
+	}
+	c.attempts++
+
+	candidates := movablePieces(c.board, c.lakes, 0)
+	if len(candidates) == 0 {
+		return nil
+	}
+	c.from = candidates[c.rng.IntN(len(candidates))]
+	return c.request(api.MsgTypeGetValidMoves, api.GetValidMovesMessage{Position: c.from})
+}
+
+// movablePieces returns the positions of the player's pieces that have a free or enemy square next to them.
+func movablePieces(board [][]api.PieceDTO, lakes map[api.PositionDTO]bool, playerID int) []api.PositionDTO {
+	var positions []api.PositionDTO
+	for y, row := range board {
+		for x, piece := range row {
+			if piece.OwnerID != playerID || piece.Rank == "" || piece.Rank == "0" || piece.Rank == "B" {
+				continue
+			}
+			for _, d := range [4][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+				nx, ny := x+d[0], y+d[1]
+				if ny < 0 || ny >= len(board) || nx < 0 || nx >= len(board[ny]) || lakes[api.PositionDTO{X: nx, Y: ny}] {
+					continue
+				}
+				if board[ny][nx].OwnerID != playerID { // empty squares have owner -1
+					positions = append(positions, api.PositionDTO{X: x, Y: y})
+					break
+				}
+			}
+		}
+	}
+	return positions
+}
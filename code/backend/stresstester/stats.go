@@ -0,0 +1,64 @@
+package stresstester
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LatencyStats sums up the round trip times of one kind of request.
+type LatencyStats struct {
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// latencies collects round trip times of all clients, by kind of request.
+type latencies struct {
+	mutex   sync.Mutex
+	samples map[string][]time.Duration
+}
+
+func newLatencies() *latencies {
+	return &latencies{samples: make(map[string][]time.Duration)}
+}
+
+func (l *latencies) record(kind string, latency time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.samples[kind] = append(l.samples[kind], latency)
+}
+
+func (l *latencies) stats() map[string]LatencyStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	stats := make(map[string]LatencyStats, len(l.samples))
+	for kind, samples := range l.samples {
+		stats[kind] = computeStats(samples)
+	}
+	return stats
+}
+
+// computeStats returns the percentiles of the samples using the nearest-rank method.
+func computeStats(samples []time.Duration) LatencyStats {
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	return LatencyStats{
+		Count: len(sorted),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P99:   percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
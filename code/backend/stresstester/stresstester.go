@@ -1 +1,199 @@
+// Package stresstester load-tests the game server with simulated players.
+// Every client creates a human vs AI game over POST /games, connects to its WebSocket as player 0,
+// randomizes its setup, starts the game and plays random legal moves with the protocol of api/messages.go.
 package stresstester
+
+import (
+	"digital-innovation/stratego/api"
+	"digital-innovation/stratego/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Options configures a stress test.
+type Options struct {
+	Clients     int           // simulated players, each in its own game
+	Moves       int           // moves per client before it leaves the game, 0 plays until the game is over
+	URL         string        // base URL of a running server, empty starts a server in this process
+	AI          string        // opponent of the clients
+	SpeedMs     int           // turn delay of the AI in milliseconds, the server allows 500 to 5000
+	IdleTimeout time.Duration // a client that gets no message for this long is dropped
+	Logging     bool          // keep the logs of the server and gin
+}
+
+// withDefaults fills in the options that are not set.
+func (o Options) withDefaults() Options {
+	if o.Clients <= 0 {
+		o.Clients = 1
+	}
+	if o.AI == "" {
+		o.AI = models.Fafo
+	}
+	if o.SpeedMs <= 0 {
+		o.SpeedMs = 500
+	}
+	if o.IdleTimeout <= 0 {
+		o.IdleTimeout = 30 * time.Second
+	}
+	return o
+}
+
+// Report is the result of a stress test.
+// The memory and goroutine counts are only measured for a server in this process.
+type Report struct {
+	Clients          int                     `json:"clients"`
+	Completed        int                     `json:"completed"`
+	Dropped          int                     `json:"dropped"`
+	Errors           []string                `json:"errors,omitempty"` // distinct reasons of dropped clients
+	Duration         time.Duration           `json:"duration"`
+	MessagesSent     int64                   `json:"messagesSent"`
+	MessagesReceived int64                   `json:"messagesReceived"`
+	Throughput       float64                 `json:"throughput"` // messages per second in both directions
+	Latencies        map[string]LatencyStats `json:"latencies"`
+	InProcess        bool                    `json:"inProcess"`
+	HeapBefore       uint64                  `json:"heapBefore,omitempty"`
+	HeapAfter        uint64                  `json:"heapAfter,omitempty"`
+	GoroutinesBefore int                     `json:"goroutinesBefore,omitempty"`
+	GoroutinesAfter  int                     `json:"goroutinesAfter,omitempty"`
+}
+
+// Run starts options.Clients clients at the same time and waits until all of them are done.
+func Run(options Options) (Report, error) {
+	options = options.withDefaults()
+	report := Report{Clients: options.Clients, InProcess: options.URL == ""}
+
+	if report.InProcess {
+		if !options.Logging {
+			log.SetOutput(io.Discard)
+			gin.DefaultWriter = io.Discard
+		}
+		report.HeapBefore, report.GoroutinesBefore = measure()
+
+		server := api.NewGameServer()
+		server.DisableRateLimit() // all clients share one IP
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return report, err
+		}
+		httpServer := &http.Server{Handler: server.Handler()}
+		go func() {
+			if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Stress test server error: %v", err)
+			}
+		}()
+		defer httpServer.Close()
+		options.URL = "http://" + listener.Addr().String()
+	}
+
+	latencies := newLatencies()
+	var sent, received atomic.Int64
+	errorCounts := make(map[string]int)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for i := range options.Clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &client{
+				id:        i,
+				options:   options,
+				http:      &http.Client{Timeout: options.IdleTimeout},
+				latencies: latencies,
+				sent:      &sent,
+				received:  &received,
+				rng:       rand.New(rand.NewPCG(uint64(i), uint64(start.UnixNano()))),
+			}
+			err := c.run()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				report.Dropped++
+				errorCounts[err.Error()]++
+			} else {
+				report.Completed++
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	report.MessagesSent = sent.Load()
+	report.MessagesReceived = received.Load()
+	report.Throughput = float64(report.MessagesSent+report.MessagesReceived) / report.Duration.Seconds()
+	report.Latencies = latencies.stats()
+	for reason, count := range errorCounts {
+		report.Errors = append(report.Errors, fmt.Sprintf("%dx %s", count, reason))
+	}
+	sort.Strings(report.Errors)
+
+	if report.InProcess {
+		report.HeapAfter, report.GoroutinesAfter = measure()
+	}
+	return report, nil
+}
+
+// measure returns the live heap after a garbage collection and the number of goroutines.
+func measure() (uint64, int) {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc, runtime.NumGoroutine()
+}
+
+// latencyKinds is the order in which the latencies are printed.
+var latencyKinds = []string{LatencyCreateGame, LatencyConnect, LatencyValidMoves, LatencyMove}
+
+// PrintReport writes the report as plain text.
+func PrintReport(w io.Writer, report Report) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "========================================")
+	fmt.Fprintf(w, "Stress Test (%d clients)\n", report.Clients)
+	fmt.Fprintln(w, "========================================")
+	fmt.Fprintf(w, "Duration: %.2fs\n", report.Duration.Seconds())
+	fmt.Fprintf(w, "Completed clients: %d\n", report.Completed)
+	fmt.Fprintf(w, "Dropped clients: %d\n", report.Dropped)
+	for _, reason := range report.Errors {
+		fmt.Fprintf(w, "  %s\n", reason)
+	}
+	fmt.Fprintf(w, "Messages: %d sent, %d received (%.1f/s)\n", report.MessagesSent, report.MessagesReceived, report.Throughput)
+	fmt.Fprintln(w, "----------------------------------------")
+
+	fmt.Fprintln(w, "Latencies:")
+	fmt.Fprintf(w, "  %-11s %6s %9s %9s %9s %9s\n", "Request", "Count", "p50", "p90", "p99", "Max")
+	for _, kind := range latencyKinds {
+		s := report.Latencies[kind]
+		fmt.Fprintf(w, "  %-11s %6d %9s %9s %9s %9s\n", kind, s.Count, formatLatency(s.P50), formatLatency(s.P90), formatLatency(s.P99), formatLatency(s.Max))
+	}
+	fmt.Fprintln(w, "----------------------------------------")
+
+	if !report.InProcess {
+		fmt.Fprintln(w, "Memory: not measured for a remote server")
+		return
+	}
+	fmt.Fprintf(w, "Heap: %.1f MiB -> %.1f MiB (%+.1f MiB)\n",
+		mebibytes(report.HeapBefore), mebibytes(report.HeapAfter), mebibytes(report.HeapAfter)-mebibytes(report.HeapBefore))
+	fmt.Fprintf(w, "Goroutines: %d -> %d (%+d)\n", report.GoroutinesBefore, report.GoroutinesAfter, report.GoroutinesAfter-report.GoroutinesBefore)
+}
+
+func formatLatency(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
+
+func mebibytes(bytes uint64) float64 {
+	return float64(bytes) / (1 << 20)
+}
@@ -0,0 +1,58 @@
+package stresstester
+
+import (
+	"digital-innovation/stratego/api"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := computeStats(samples)
+	if stats.Count != 100 || stats.P50 != 50*time.Millisecond || stats.P90 != 90*time.Millisecond ||
+		stats.P99 != 99*time.Millisecond || stats.Max != 100*time.Millisecond {
+		t.Errorf("Expected the nearest-rank percentiles of 1ms to 100ms, got: %+v", stats)
+	}
+	if samples[0] != 100*time.Millisecond {
+		t.Errorf("Expected the samples to stay unsorted, got %v first", samples[0])
+	}
+
+	if empty := computeStats(nil); empty != (LatencyStats{}) {
+		t.Errorf("Expected empty stats for no samples, got: %+v", empty)
+	}
+}
+
+func TestMovablePieces(t *testing.T) {
+	board := make([][]api.PieceDTO, 10)
+	for y := range board {
+		board[y] = make([]api.PieceDTO, 10)
+		for x := range board[y] {
+			board[y][x] = api.PieceDTO{OwnerID: -1}
+		}
+	}
+	// A scout boxed in by own pieces, a flag, a bomb and an enemy piece
+	board[9][0] = api.PieceDTO{OwnerID: 0, Rank: "2"}
+	board[8][0] = api.PieceDTO{OwnerID: 0, Rank: "0"}
+	board[9][1] = api.PieceDTO{OwnerID: 0, Rank: "B"}
+	board[5][0] = api.PieceDTO{OwnerID: 0, Rank: "M"}
+	board[4][0] = api.PieceDTO{OwnerID: 1}
+	board[5][1] = api.PieceDTO{OwnerID: 0, Rank: "3"}
+	board[6][1] = api.PieceDTO{OwnerID: 0, Rank: "4"}
+
+	// Only the marshal, miner and sergeant have an empty or enemy square next to them
+	lakes := map[api.PositionDTO]bool{{X: 2, Y: 5}: true}
+	positions := movablePieces(board, lakes, 0)
+	expected := map[api.PositionDTO]bool{{X: 0, Y: 5}: true, {X: 1, Y: 5}: true, {X: 1, Y: 6}: true}
+	if len(positions) != len(expected) {
+		t.Fatalf("Expected %d movable pieces, got: %v", len(expected), positions)
+	}
+	for _, pos := range positions {
+		if !expected[pos] {
+			t.Errorf("Expected %v not to be movable", pos)
+		}
+	}
+}